@@ -0,0 +1,76 @@
+package admin
+
+import (
+	"github.com/go-clarum/clarum-http/registry"
+	"github.com/go-clarum/clarum-http/server"
+)
+
+type Builder struct {
+	name      string
+	port      uint
+	endpoints []*server.Endpoint
+	inProcess bool
+	registry  *registry.Registry
+}
+
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+func (builder *Builder) Name(name string) *Builder {
+	builder.name = name
+	return builder
+}
+
+func (builder *Builder) Port(port uint) *Builder {
+	builder.port = port
+	return builder
+}
+
+// Endpoints managed by the admin API, addressed by their name
+func (builder *Builder) Endpoints(endpoints ...*server.Endpoint) *Builder {
+	builder.endpoints = append(builder.endpoints, endpoints...)
+	return builder
+}
+
+// InProcess builds an admin API that does not open a port. It is an http.Handler and can be mounted into an existing mux.
+func (builder *Builder) InProcess() *Builder {
+	builder.inProcess = true
+	return builder
+}
+
+// Registry registers the admin API when it is built, before it starts listening. Its name & port must not be used
+// by other endpoints. Admin APIs built through clarumhttp.Http() are registered automatically.
+// A registered admin API is unregistered when it is closed.
+func (builder *Builder) Registry(registry *registry.Registry) *Builder {
+	builder.registry = registry
+	return builder
+}
+
+// Build panics if the admin API cannot be registered, so that conflicts are found when global variables are initialized
+func (builder *Builder) Build() *Server {
+	name := builder.name
+	if name == "" {
+		name = "admin"
+	}
+
+	adminServer := newServer(name, builder.port)
+	adminServer.Register(builder.endpoints...)
+
+	if builder.registry != nil {
+		port := builder.port
+		if builder.inProcess {
+			port = 0
+		}
+		if err := builder.registry.Register(registry.Admin, name, port, adminServer, adminServer.Close); err != nil {
+			panic(err)
+		}
+		adminServer.registry = builder.registry
+	}
+
+	if !builder.inProcess {
+		adminServer.start()
+	}
+
+	return adminServer
+}
@@ -0,0 +1,338 @@
+package admin
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-core/config"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/registry"
+	"github.com/go-clarum/clarum-http/server"
+	"github.com/go-clarum/clarum-http/stubs"
+	"net"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+const closeTimeout = time.Second
+
+// Server is a REST API to manage server endpoints while they are running: their stubs, request journal
+// and injected faults. It lets tools that do not use the Go API drive the endpoints.
+//
+//	GET    /endpoints                        names of the managed endpoints
+//	GET    /endpoints/{name}/stubs           stubs, in the order they are matched
+//	POST   /endpoints/{name}/stubs           adds a stub, replacing a stub with the same name
+//	DELETE /endpoints/{name}/stubs           removes all stubs
+//	DELETE /endpoints/{name}/stubs/{stub}    removes a stub
+//	GET    /endpoints/{name}/journal         received requests
+//	DELETE /endpoints/{name}/journal         clears the journal
+//	GET    /endpoints/{name}/fault           injected fault
+//	PUT    /endpoints/{name}/fault           injects a fault into every request
+//	DELETE /endpoints/{name}/fault           clears the fault
+//	GET    /endpoints/{name}/scenarios       current state of the scenarios
+//	DELETE /endpoints/{name}/scenarios       resets all scenarios
+//	PUT    /endpoints/{name}/scenarios/{s}   moves a scenario into a state
+//	DELETE /endpoints/{name}/scenarios/{s}   resets a scenario
+//	POST   /endpoints/{name}/reset           removes stubs, journal & fault and resets the scenarios
+type Server struct {
+	name          string
+	port          uint
+	mux           *http.ServeMux
+	server        *http.Server
+	endpoints     map[string]*server.Endpoint
+	endpointsLock sync.Mutex
+	registry      *registry.Registry
+	logger        *logging.Logger
+}
+
+// FaultDefinition describes a fault in JSON, with the delay as a duration like '500ms'
+type FaultDefinition struct {
+	Delay  string `json:"delay,omitempty"`
+	Status int    `json:"status,omitempty"`
+	Abort  bool   `json:"abort,omitempty"`
+}
+
+// ScenarioDefinition is the state of a scenario in JSON
+type ScenarioDefinition struct {
+	State string `json:"state"`
+}
+
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+func newServer(name string, port uint) *Server {
+	adminServer := &Server{
+		name:      name,
+		port:      port,
+		mux:       http.NewServeMux(),
+		endpoints: make(map[string]*server.Endpoint),
+		logger:    logging.NewLogger(config.LoggingLevel(), adminLogPrefix(name)),
+	}
+
+	adminServer.mux.HandleFunc("GET /endpoints", adminServer.listEndpoints)
+	adminServer.mux.HandleFunc("GET /endpoints/{name}/stubs", adminServer.withEndpoint(listStubs))
+	adminServer.mux.HandleFunc("POST /endpoints/{name}/stubs", adminServer.withEndpoint(addStub))
+	adminServer.mux.HandleFunc("DELETE /endpoints/{name}/stubs", adminServer.withEndpoint(clearStubs))
+	adminServer.mux.HandleFunc("DELETE /endpoints/{name}/stubs/{stub}", adminServer.withEndpoint(removeStub))
+	adminServer.mux.HandleFunc("GET /endpoints/{name}/journal", adminServer.withEndpoint(listJournal))
+	adminServer.mux.HandleFunc("DELETE /endpoints/{name}/journal", adminServer.withEndpoint(clearJournal))
+	adminServer.mux.HandleFunc("GET /endpoints/{name}/fault", adminServer.withEndpoint(getFault))
+	adminServer.mux.HandleFunc("PUT /endpoints/{name}/fault", adminServer.withEndpoint(injectFault))
+	adminServer.mux.HandleFunc("DELETE /endpoints/{name}/fault", adminServer.withEndpoint(clearFault))
+	adminServer.mux.HandleFunc("GET /endpoints/{name}/scenarios", adminServer.withEndpoint(listScenarios))
+	adminServer.mux.HandleFunc("DELETE /endpoints/{name}/scenarios", adminServer.withEndpoint(resetScenarios))
+	adminServer.mux.HandleFunc("PUT /endpoints/{name}/scenarios/{scenario}", adminServer.withEndpoint(setScenarioState))
+	adminServer.mux.HandleFunc("DELETE /endpoints/{name}/scenarios/{scenario}", adminServer.withEndpoint(resetScenario))
+	adminServer.mux.HandleFunc("POST /endpoints/{name}/reset", adminServer.withEndpoint(reset))
+
+	return adminServer
+}
+
+// Register makes endpoints manageable. An endpoint replaces a registered endpoint with the same name.
+func (adminServer *Server) Register(endpoints ...*server.Endpoint) {
+	adminServer.endpointsLock.Lock()
+	defer adminServer.endpointsLock.Unlock()
+
+	for _, endpoint := range endpoints {
+		adminServer.endpoints[endpoint.Name()] = endpoint
+	}
+}
+
+func (adminServer *Server) Unregister(name string) {
+	adminServer.endpointsLock.Lock()
+	defer adminServer.endpointsLock.Unlock()
+
+	delete(adminServer.endpoints, name)
+}
+
+func (adminServer *Server) ServeHTTP(resWriter http.ResponseWriter, request *http.Request) {
+	adminServer.logger.Infof("received admin request [method: %s, url: %s]", request.Method, request.URL)
+	adminServer.mux.ServeHTTP(resWriter, request)
+}
+
+// Close stops the admin API and frees its port
+func (adminServer *Server) Close() error {
+	if adminServer.registry != nil {
+		adminServer.registry.Unregister(adminServer.name)
+	}
+	if adminServer.server == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	defer cancel()
+
+	return adminServer.server.Shutdown(ctx)
+}
+
+func (adminServer *Server) start() {
+	adminServer.server = &http.Server{
+		Addr:    fmt.Sprintf(":%d", adminServer.port),
+		Handler: adminServer,
+	}
+
+	// bound synchronously, so that the API can be called right after it was built
+	listener, err := net.Listen("tcp", adminServer.server.Addr)
+	if err != nil {
+		adminServer.logger.Errorf("error - %s", err)
+		return
+	}
+
+	go func() {
+		if err := adminServer.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			adminServer.logger.Errorf("error - %s", err)
+		} else {
+			adminServer.logger.Info("closed admin server")
+		}
+	}()
+}
+
+func (adminServer *Server) listEndpoints(resWriter http.ResponseWriter, _ *http.Request) {
+	adminServer.endpointsLock.Lock()
+	names := make([]string, 0, len(adminServer.endpoints))
+	for name := range adminServer.endpoints {
+		names = append(names, name)
+	}
+	adminServer.endpointsLock.Unlock()
+
+	sort.Strings(names)
+	writeJson(resWriter, http.StatusOK, names)
+}
+
+// withEndpoint resolves the endpoint named in the path; unknown endpoints are answered with 404 (Not Found)
+func (adminServer *Server) withEndpoint(handler func(*server.Endpoint, http.ResponseWriter, *http.Request)) http.HandlerFunc {
+	return func(resWriter http.ResponseWriter, request *http.Request) {
+		name := request.PathValue("name")
+
+		adminServer.endpointsLock.Lock()
+		endpoint, exists := adminServer.endpoints[name]
+		adminServer.endpointsLock.Unlock()
+
+		if !exists {
+			writeError(resWriter, http.StatusNotFound, fmt.Sprintf("unknown endpoint [%s]", name))
+			return
+		}
+		handler(endpoint, resWriter, request)
+	}
+}
+
+func listStubs(endpoint *server.Endpoint, resWriter http.ResponseWriter, _ *http.Request) {
+	definitions := make([]stubs.Definition, 0)
+	for _, stub := range endpoint.Stubs() {
+		definitions = append(definitions, stubs.FromStub(stub))
+	}
+	writeJson(resWriter, http.StatusOK, definitions)
+}
+
+func addStub(endpoint *server.Endpoint, resWriter http.ResponseWriter, request *http.Request) {
+	definition := stubs.Definition{}
+	if err := json.NewDecoder(request.Body).Decode(&definition); err != nil {
+		writeError(resWriter, http.StatusBadRequest, "invalid stub - "+err.Error())
+		return
+	}
+	if definition.Name == "" {
+		writeError(resWriter, http.StatusBadRequest, "invalid stub - name is missing")
+		return
+	}
+
+	stub, err := definition.Stub()
+	if err != nil {
+		writeError(resWriter, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	endpoint.AddStubs(stub)
+	writeJson(resWriter, http.StatusCreated, stubs.FromStub(stub))
+}
+
+func clearStubs(endpoint *server.Endpoint, resWriter http.ResponseWriter, _ *http.Request) {
+	endpoint.ClearStubs()
+	resWriter.WriteHeader(http.StatusNoContent)
+}
+
+func removeStub(endpoint *server.Endpoint, resWriter http.ResponseWriter, request *http.Request) {
+	name := request.PathValue("stub")
+	if !endpoint.RemoveStub(name) {
+		writeError(resWriter, http.StatusNotFound, fmt.Sprintf("unknown stub [%s]", name))
+		return
+	}
+	resWriter.WriteHeader(http.StatusNoContent)
+}
+
+func listJournal(endpoint *server.Endpoint, resWriter http.ResponseWriter, _ *http.Request) {
+	journal := endpoint.Journal()
+	if journal == nil {
+		journal = make([]server.JournalEntry, 0)
+	}
+	writeJson(resWriter, http.StatusOK, journal)
+}
+
+func clearJournal(endpoint *server.Endpoint, resWriter http.ResponseWriter, _ *http.Request) {
+	endpoint.ClearJournal()
+	resWriter.WriteHeader(http.StatusNoContent)
+}
+
+func getFault(endpoint *server.Endpoint, resWriter http.ResponseWriter, _ *http.Request) {
+	fault := endpoint.Fault()
+	if fault == nil {
+		writeError(resWriter, http.StatusNotFound, "no fault injected")
+		return
+	}
+
+	definition := FaultDefinition{Status: fault.Status, Abort: fault.Abort}
+	if fault.Delay > 0 {
+		definition.Delay = fault.Delay.String()
+	}
+	writeJson(resWriter, http.StatusOK, definition)
+}
+
+func injectFault(endpoint *server.Endpoint, resWriter http.ResponseWriter, request *http.Request) {
+	definition := FaultDefinition{}
+	if err := json.NewDecoder(request.Body).Decode(&definition); err != nil {
+		writeError(resWriter, http.StatusBadRequest, "invalid fault - "+err.Error())
+		return
+	}
+
+	fault := &server.Fault{Status: definition.Status, Abort: definition.Abort}
+	if definition.Delay != "" {
+		delay, err := time.ParseDuration(definition.Delay)
+		if err != nil {
+			writeError(resWriter, http.StatusBadRequest, "invalid fault - "+err.Error())
+			return
+		}
+		fault.Delay = delay
+	}
+
+	if fault.Delay < 0 {
+		writeError(resWriter, http.StatusBadRequest, "invalid fault - delay must not be negative")
+		return
+	}
+	if fault.Status != 0 && (fault.Status < 100 || fault.Status > 599) {
+		writeError(resWriter, http.StatusBadRequest, fmt.Sprintf("invalid fault - unknown status [%d]", fault.Status))
+		return
+	}
+	if fault.Delay == 0 && fault.Status == 0 && !fault.Abort {
+		writeError(resWriter, http.StatusBadRequest, "invalid fault - a delay, a status or abort is required")
+		return
+	}
+
+	endpoint.InjectFault(fault)
+	writeJson(resWriter, http.StatusOK, definition)
+}
+
+func clearFault(endpoint *server.Endpoint, resWriter http.ResponseWriter, _ *http.Request) {
+	endpoint.ClearFault()
+	resWriter.WriteHeader(http.StatusNoContent)
+}
+
+func listScenarios(endpoint *server.Endpoint, resWriter http.ResponseWriter, _ *http.Request) {
+	writeJson(resWriter, http.StatusOK, endpoint.Scenarios())
+}
+
+func resetScenarios(endpoint *server.Endpoint, resWriter http.ResponseWriter, _ *http.Request) {
+	endpoint.ResetScenarios()
+	resWriter.WriteHeader(http.StatusNoContent)
+}
+
+func setScenarioState(endpoint *server.Endpoint, resWriter http.ResponseWriter, request *http.Request) {
+	definition := ScenarioDefinition{}
+	if err := json.NewDecoder(request.Body).Decode(&definition); err != nil {
+		writeError(resWriter, http.StatusBadRequest, "invalid scenario state - "+err.Error())
+		return
+	}
+	if definition.State == "" {
+		writeError(resWriter, http.StatusBadRequest, "invalid scenario state - state is missing")
+		return
+	}
+
+	endpoint.SetScenarioState(request.PathValue("scenario"), definition.State)
+	writeJson(resWriter, http.StatusOK, definition)
+}
+
+func resetScenario(endpoint *server.Endpoint, resWriter http.ResponseWriter, request *http.Request) {
+	endpoint.ResetScenario(request.PathValue("scenario"))
+	resWriter.WriteHeader(http.StatusNoContent)
+}
+
+func reset(endpoint *server.Endpoint, resWriter http.ResponseWriter, _ *http.Request) {
+	endpoint.Reset()
+	resWriter.WriteHeader(http.StatusNoContent)
+}
+
+func writeJson(resWriter http.ResponseWriter, status int, body any) {
+	resWriter.Header().Set("Content-Type", "application/json")
+	resWriter.WriteHeader(status)
+	_ = json.NewEncoder(resWriter).Encode(body)
+}
+
+func writeError(resWriter http.ResponseWriter, status int, message string) {
+	writeJson(resWriter, status, errorResponse{Error: message})
+}
+
+func adminLogPrefix(name string) string {
+	return fmt.Sprintf("%s: ", name)
+}
@@ -0,0 +1,112 @@
+package admin
+
+import (
+	"github.com/go-clarum/clarum-http/server"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStubs(t *testing.T) {
+	endpoint := server.NewEndpointBuilder().Name("users").InProcess().Build()
+	adminServer := NewBuilder().Endpoints(endpoint).InProcess().Build()
+
+	expectResponse(t, adminServer, http.MethodGet, "/endpoints", "", http.StatusOK, "[\"users\"]")
+	expectResponse(t, adminServer, http.MethodPost, "/endpoints/users/stubs",
+		"{\"name\": \"health\", \"request\": {\"path\": \"/health\"}, \"response\": {\"payload\": \"UP\"}}",
+		http.StatusCreated, "\"name\":\"health\"")
+	expectResponse(t, adminServer, http.MethodGet, "/endpoints/users/stubs", "",
+		http.StatusOK, "[{\"name\":\"health\",\"request\":{\"path\":\"/health\"},\"response\":{\"status\":200,\"payload\":\"UP\"}}]")
+
+	if len(endpoint.Stubs()) != 1 {
+		t.Errorf("Stub was not added to the endpoint")
+	}
+
+	expectResponse(t, adminServer, http.MethodDelete, "/endpoints/users/stubs/health", "", http.StatusNoContent, "")
+	expectResponse(t, adminServer, http.MethodDelete, "/endpoints/users/stubs/health", "",
+		http.StatusNotFound, "unknown stub [health]")
+}
+
+func TestInvalidRequests(t *testing.T) {
+	endpoint := server.NewEndpointBuilder().Name("users").InProcess().Build()
+	adminServer := NewBuilder().Endpoints(endpoint).InProcess().Build()
+
+	expectResponse(t, adminServer, http.MethodGet, "/endpoints/orders/stubs", "",
+		http.StatusNotFound, "unknown endpoint [orders]")
+	expectResponse(t, adminServer, http.MethodPost, "/endpoints/users/stubs", "{\"request\": {}}",
+		http.StatusBadRequest, "name is missing")
+	expectResponse(t, adminServer, http.MethodPost, "/endpoints/users/stubs", "{",
+		http.StatusBadRequest, "invalid stub")
+	expectResponse(t, adminServer, http.MethodPut, "/endpoints/users/fault", "{\"delay\": \"soon\"}",
+		http.StatusBadRequest, "invalid fault")
+	expectResponse(t, adminServer, http.MethodPut, "/endpoints/users/fault", "{\"delay\": \"-1s\"}",
+		http.StatusBadRequest, "delay must not be negative")
+	expectResponse(t, adminServer, http.MethodPut, "/endpoints/users/fault", "{\"status\": 600}",
+		http.StatusBadRequest, "unknown status [600]")
+	expectResponse(t, adminServer, http.MethodPut, "/endpoints/users/fault", "{\"status\": 42}",
+		http.StatusBadRequest, "unknown status [42]")
+	expectResponse(t, adminServer, http.MethodPut, "/endpoints/users/fault", "{}",
+		http.StatusBadRequest, "a delay, a status or abort is required")
+	if endpoint.Fault() != nil {
+		t.Errorf("Invalid fault was injected %+v", endpoint.Fault())
+	}
+}
+
+func TestScenarios(t *testing.T) {
+	endpoint := server.NewEndpointBuilder().Name("users").InProcess().Build()
+	adminServer := NewBuilder().Endpoints(endpoint).InProcess().Build()
+
+	expectResponse(t, adminServer, http.MethodPost, "/endpoints/users/stubs",
+		"{\"name\": \"pending\", \"scenario\": \"export\", \"requiredState\": \"Started\", \"newState\": \"done\"}",
+		http.StatusCreated, "\"scenario\":\"export\",\"requiredState\":\"Started\",\"newState\":\"done\"")
+	expectResponse(t, adminServer, http.MethodGet, "/endpoints/users/scenarios", "",
+		http.StatusOK, "{\"export\":\"Started\"}")
+
+	expectResponse(t, adminServer, http.MethodPut, "/endpoints/users/scenarios/export", "{\"state\": \"done\"}",
+		http.StatusOK, "")
+	if state := endpoint.ScenarioState("export"); state != "done" {
+		t.Errorf("Unexpected scenario state [%s]", state)
+	}
+	expectResponse(t, adminServer, http.MethodPut, "/endpoints/users/scenarios/export", "{}",
+		http.StatusBadRequest, "state is missing")
+
+	expectResponse(t, adminServer, http.MethodDelete, "/endpoints/users/scenarios/export", "", http.StatusNoContent, "")
+	if state := endpoint.ScenarioState("export"); state != server.ScenarioStarted {
+		t.Errorf("Scenario was not reset, state is [%s]", state)
+	}
+}
+
+func TestFault(t *testing.T) {
+	endpoint := server.NewEndpointBuilder().Name("users").InProcess().Build()
+	adminServer := NewBuilder().Endpoints(endpoint).InProcess().Build()
+
+	expectResponse(t, adminServer, http.MethodGet, "/endpoints/users/fault", "", http.StatusNotFound, "no fault")
+	expectResponse(t, adminServer, http.MethodPut, "/endpoints/users/fault", "{\"delay\": \"1s\", \"status\": 503}",
+		http.StatusOK, "")
+
+	if fault := endpoint.Fault(); fault == nil || fault.Delay != time.Second || fault.Status != http.StatusServiceUnavailable {
+		t.Errorf("Unexpected fault %+v", fault)
+	}
+	expectResponse(t, adminServer, http.MethodGet, "/endpoints/users/fault", "",
+		http.StatusOK, "{\"delay\":\"1s\",\"status\":503}")
+
+	expectResponse(t, adminServer, http.MethodPost, "/endpoints/users/reset", "", http.StatusNoContent, "")
+	if endpoint.Fault() != nil {
+		t.Errorf("Fault was not reset")
+	}
+}
+
+func expectResponse(t *testing.T, handler http.Handler, method string, url string, body string,
+	expectedStatus int, expectedBody string) {
+	t.Helper()
+
+	recorder := httptest.NewRecorder()
+	handler.ServeHTTP(recorder, httptest.NewRequest(method, url, strings.NewReader(body)))
+
+	if recorder.Code != expectedStatus || !strings.Contains(recorder.Body.String(), expectedBody) {
+		t.Errorf("Expected [%d] containing [%s] for %s %s, but got [%d: %s]", expectedStatus, expectedBody,
+			method, url, recorder.Code, recorder.Body)
+	}
+}
@@ -1,14 +1,34 @@
 package client
 
 import (
+	"crypto/tls"
+	"github.com/go-clarum/clarum-core/durations"
+	clarumstrings "github.com/go-clarum/clarum-core/validators/strings"
+	"github.com/go-clarum/clarum-http/har"
+	"github.com/go-clarum/clarum-http/internal/lifecycle"
+	"github.com/go-clarum/clarum-http/openapi"
+	"github.com/go-clarum/clarum-http/registry"
+	"net/http"
 	"time"
 )
 
 type EndpointBuilder struct {
-	baseUrl     string
-	contentType string
-	name        string
-	timeout     time.Duration
+	baseUrl      string
+	contentType  string
+	name         string
+	timeout      time.Duration
+	retryPolicy  retryPolicy
+	transport    http.RoundTripper
+	httpClient   *http.Client
+	interceptors interceptors
+	handler      http.Handler
+	streamTypes  []string
+	protocols    *http.Protocols
+	tlsConfig    *tls.Config
+	harRecorder  *har.Recorder
+	openApiSpec  *openapi.Spec
+	isolateTests bool
+	registry     *registry.Registry
 }
 
 func NewEndpointBuilder() *EndpointBuilder {
@@ -35,6 +55,167 @@ func (builder *EndpointBuilder) Timeout(timeout time.Duration) *EndpointBuilder
 	return builder
 }
 
+// Retries enables retrying a request up to the given number of times. By default, only transport errors
+// (connection refused, connection reset etc.) are retried. Use RetryOnStatus() to retry on responses as well.
+// Every attempt is logged, so retries will not hide problems of the system under test.
+func (builder *EndpointBuilder) Retries(retries uint) *EndpointBuilder {
+	builder.retryPolicy.maxRetries = retries
+	return builder
+}
+
+// RetryOnStatus configures the response status codes for which a request will be retried.
+func (builder *EndpointBuilder) RetryOnStatus(statusCodes ...int) *EndpointBuilder {
+	builder.retryPolicy.statusCodes = append(builder.retryPolicy.statusCodes, statusCodes...)
+	return builder
+}
+
+// RetryBackoff sets the initial wait time between two attempts, which doubles after every attempt.
+// A Retry-After header sent by the server takes precedence over the backoff.
+func (builder *EndpointBuilder) RetryBackoff(backoff time.Duration) *EndpointBuilder {
+	builder.retryPolicy.backoff = backoff
+	return builder
+}
+
+// RetryMaxDelay caps the wait time between two attempts, both for the backoff & for a Retry-After header.
+// The action timeout of the config is used by default.
+func (builder *EndpointBuilder) RetryMaxDelay(maxDelay time.Duration) *EndpointBuilder {
+	builder.retryPolicy.maxDelay = maxDelay
+	return builder
+}
+
+// Transport sets a custom http.RoundTripper to be used by the endpoint (proxies, custom dialers, instrumentation).
+// The configured Timeout is still applied.
+func (builder *EndpointBuilder) Transport(transport http.RoundTripper) *EndpointBuilder {
+	builder.transport = transport
+	return builder
+}
+
+// HttpClient replaces the http.Client used by the endpoint. The client is used as it is,
+// which means that Timeout() and Transport() will have no effect.
+func (builder *EndpointBuilder) HttpClient(httpClient *http.Client) *EndpointBuilder {
+	builder.httpClient = httpClient
+	return builder
+}
+
+// InProcess makes the endpoint dispatch all requests directly into the given http.Handler, without opening
+// any socket. If no base url is configured, http://localhost is used.
+func (builder *EndpointBuilder) InProcess(handler http.Handler) *EndpointBuilder {
+	builder.handler = handler
+	return builder
+}
+
+// Http2 forces HTTP/2 over TLS, negotiated with ALPN. The request fails if the server does not support HTTP/2.
+// Like all protocol options, it has no effect when Transport(), HttpClient() or InProcess() are used.
+func (builder *EndpointBuilder) Http2() *EndpointBuilder {
+	builder.protocols = &http.Protocols{}
+	builder.protocols.SetHTTP2(true)
+	return builder
+}
+
+// H2C forces HTTP/2 over cleartext with prior knowledge, without an upgrade from HTTP/1.1.
+func (builder *EndpointBuilder) H2C() *EndpointBuilder {
+	builder.protocols = &http.Protocols{}
+	builder.protocols.SetUnencryptedHTTP2(true)
+	return builder
+}
+
+// TLSConfig sets the TLS configuration of the transport, for example to trust the certificate of a test server.
+func (builder *EndpointBuilder) TLSConfig(tlsConfig *tls.Config) *EndpointBuilder {
+	builder.tlsConfig = tlsConfig
+	return builder
+}
+
+// StreamContentTypes adds content types of responses which are not buffered by the endpoint, so that
+// they can be validated incrementally by a stream receive action. Event streams and NDJSON are always streamed.
+func (builder *EndpointBuilder) StreamContentTypes(contentTypes ...string) *EndpointBuilder {
+	builder.streamTypes = append(builder.streamTypes, contentTypes...)
+	return builder
+}
+
+// Har records every exchange of the endpoint, with timings, into the given recorder.
+func (builder *EndpointBuilder) Har(recorder *har.Recorder) *EndpointBuilder {
+	builder.harRecorder = recorder
+	return builder
+}
+
+// OpenApi validates every received response against the operation of the spec that matches its request,
+// in addition to the validation of the expected message. The exercised operations are reported
+// as the coverage of the spec, when the test run finishes.
+func (builder *EndpointBuilder) OpenApi(spec *openapi.Spec) *EndpointBuilder {
+	builder.openApiSpec = spec
+	return builder
+}
+
+// BeforeSend adds interceptors that are called for every outgoing request, in the order they were added.
+func (builder *EndpointBuilder) BeforeSend(interceptors ...RequestInterceptor) *EndpointBuilder {
+	builder.interceptors.beforeSend = append(builder.interceptors.beforeSend, interceptors...)
+	return builder
+}
+
+// AfterReceive adds interceptors that are called for every incoming response, in the order they were added.
+func (builder *EndpointBuilder) AfterReceive(interceptors ...ResponseInterceptor) *EndpointBuilder {
+	builder.interceptors.afterReceive = append(builder.interceptors.afterReceive, interceptors...)
+	return builder
+}
+
+// IsolateTests lets parallel tests share the endpoint. Requests sent in a test carry its name in the test header
+// (X-Clarum-Test) and their responses are only received by the same test.
+func (builder *EndpointBuilder) IsolateTests() *EndpointBuilder {
+	builder.isolateTests = true
+	return builder
+}
+
+// Registry registers the endpoint when it is built. Its name must not be used by other endpoints.
+// Endpoints built through clarumhttp.Http() are registered automatically.
+// A registered endpoint is unregistered when it is closed.
+func (builder *EndpointBuilder) Registry(registry *registry.Registry) *EndpointBuilder {
+	builder.registry = registry
+	return builder
+}
+
+// Build panics if the endpoint cannot be registered, so that conflicts are found when global variables are initialized
 func (builder *EndpointBuilder) Build() *Endpoint {
-	return newEndpoint(builder.name, builder.baseUrl, builder.contentType, builder.timeout)
+	baseUrl := builder.baseUrl
+	if builder.handler != nil && clarumstrings.IsBlank(baseUrl) {
+		baseUrl = inProcessBaseUrl
+	}
+
+	endpoint := newEndpoint(builder.name, baseUrl, builder.contentType, builder.timeout)
+	if builder.registry != nil {
+		if err := builder.registry.Register(registry.Client, builder.name, 0, endpoint, endpoint.Close); err != nil {
+			panic(err)
+		}
+		endpoint.registry = builder.registry
+	}
+
+	lifecycle.OnFinish(endpoint.finish)
+
+	endpoint.retryPolicy = builder.retryPolicy
+	endpoint.retryPolicy.backoff = durations.GetDurationWithDefault(builder.retryPolicy.backoff, defaultRetryBackoff)
+	endpoint.retryPolicy.logger = endpoint.logger
+	endpoint.interceptors = builder.interceptors
+	endpoint.harRecorder = builder.harRecorder
+	endpoint.openApiSpec = builder.openApiSpec
+	endpoint.isolateTests = builder.isolateTests
+	if builder.openApiSpec != nil {
+		builder.openApiSpec.TrackCoverage()
+	}
+	endpoint.streamContentTypes = append(endpoint.streamContentTypes, builder.streamTypes...)
+
+	if builder.handler != nil {
+		endpoint.client.Transport = &handlerTransport{handler: builder.handler}
+	} else if builder.httpClient != nil {
+		endpoint.client = builder.httpClient
+	} else if builder.transport != nil {
+		endpoint.client.Transport = builder.transport
+	} else if builder.protocols != nil || builder.tlsConfig != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.TLSClientConfig = builder.tlsConfig
+		if builder.protocols != nil {
+			transport.Protocols = builder.protocols
+		}
+		endpoint.client.Transport = transport
+	}
+
+	return endpoint
 }
@@ -2,6 +2,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"errors"
 	"fmt"
 	"github.com/go-clarum/clarum-core/config"
@@ -10,45 +11,106 @@ import (
 	"github.com/go-clarum/clarum-core/logging"
 	clarumstrings "github.com/go-clarum/clarum-core/validators/strings"
 	"github.com/go-clarum/clarum-http/constants"
+	"github.com/go-clarum/clarum-http/har"
+	"github.com/go-clarum/clarum-http/internal"
+	"github.com/go-clarum/clarum-http/internal/exchanges"
+	"github.com/go-clarum/clarum-http/internal/sse"
+	"github.com/go-clarum/clarum-http/internal/timing"
 	"github.com/go-clarum/clarum-http/internal/utils"
 	"github.com/go-clarum/clarum-http/internal/validators"
 	"github.com/go-clarum/clarum-http/message"
+	"github.com/go-clarum/clarum-http/openapi"
+	"github.com/go-clarum/clarum-http/registry"
 	"io"
 	"net/http"
+	"net/http/httptrace"
+	"strings"
+	"sync"
 	"time"
 )
 
 type Endpoint struct {
-	name            string
-	baseUrl         string
-	contentType     string
-	client          *http.Client
-	responseChannel chan *responsePair
-	logger          *logging.Logger
+	name               string
+	baseUrl            string
+	contentType        string
+	client             *http.Client
+	retryPolicy        retryPolicy
+	interceptors       interceptors
+	streamContentTypes []string
+	harRecorder        *har.Recorder
+	openApiSpec        *openapi.Spec
+	responseChannel    chan *responsePair
+	isolateTests       bool
+	testChannels       map[string]chan *responsePair
+	testChannelsLock   sync.Mutex
+	registry           *registry.Registry
+	tracker            *exchanges.Tracker
+	logger             *logging.Logger
 }
 
 type responsePair struct {
+	request  *message.RequestMessage
 	response *http.Response
-	error    error
+	attempts int
+	timings  *timing.Timings
+	// cancel releases the request context - streams call it when closed, to stop the transport from reading
+	cancel context.CancelFunc
+	error  error
+	// tracked is marked as done by the action receiving the response
+	tracked *exchanges.Exchange
+	// options of the send action, used again when the request is sent again
+	options sendOptions
+}
+
+// sendOptions apply to a single send action
+type sendOptions struct {
+	// test marks the request with the test header & routes its response to this test
+	test string
+	ctx  context.Context
+	// timeout cancels the request if its response was not received completely in time
+	timeout time.Duration
 }
 
-// TODO: handle empty name
 func newEndpoint(name string, baseUrl string, contentType string, timeout time.Duration) *Endpoint {
 	client := http.Client{
 		Timeout: durations.GetDurationWithDefault(timeout, 10*time.Second),
 	}
 
 	return &Endpoint{
-		name:            name,
-		baseUrl:         baseUrl,
-		contentType:     contentType,
-		client:          &client,
-		responseChannel: make(chan *responsePair),
-		logger:          logging.NewLogger(config.LoggingLevel(), clientLogPrefix(name)),
+		name:               name,
+		baseUrl:            baseUrl,
+		contentType:        contentType,
+		client:             &client,
+		streamContentTypes: []string{sse.ContentType, constants.ContentTypeNdJsonHeader},
+		responseChannel:    make(chan *responsePair),
+		testChannels:       make(map[string]chan *responsePair),
+		tracker:            exchanges.NewTracker(),
+		logger:             logging.NewLogger(config.LoggingLevel(), clientLogPrefix(name)),
+	}
+}
+
+// Close releases the idle connections of the endpoint and frees its name, so that an endpoint built
+// inside a test can be built again by the next run of the test
+func (endpoint *Endpoint) Close() error {
+	if endpoint.registry != nil {
+		endpoint.registry.Unregister(endpoint.name)
+	}
+	endpoint.client.CloseIdleConnections()
+	return nil
+}
+
+// finish is called when the test run finishes: the responses that were not received by the test are reported
+// and the endpoint is closed
+func (endpoint *Endpoint) finish() error {
+	defer endpoint.Close()
+
+	if unhandled := endpoint.tracker.Unhandled(); len(unhandled) > 0 {
+		return endpoint.handleError("unhandled exchanges:\n"+strings.Join(unhandled, "\n"), nil)
 	}
+	return nil
 }
 
-func (endpoint *Endpoint) send(message *message.RequestMessage) error {
+func (endpoint *Endpoint) send(message *message.RequestMessage, options sendOptions) error {
 	if message == nil {
 		return endpoint.handleError("message to send is nil", nil)
 	}
@@ -62,37 +124,73 @@ func (endpoint *Endpoint) send(message *message.RequestMessage) error {
 		return err
 	}
 
-	req, err := endpoint.buildRequest(messageToSend)
+	if options.test != "" {
+		messageToSend.Header(constants.TestHeaderName, options.test)
+	}
+
+	ctx := internal.ActionContext(options.ctx)
+	requestCtx, cancelTimeout := ctx, context.CancelFunc(func() {})
+	if options.timeout > 0 {
+		requestCtx, cancelTimeout = context.WithTimeout(ctx, options.timeout)
+	}
+
+	req, err := endpoint.buildRequest(requestCtx, messageToSend)
 	// we return error here directly and not in the goroutine below
 	// this way we can signal to the test synchronously that there was an error
 	if err != nil {
+		cancelTimeout()
 		return endpoint.handleError("canceled message", err)
 	}
 
+	tracked := endpoint.tracker.Start(fmt.Sprintf("response to [%s %s]", req.Method, req.URL),
+		"a client receive action", options.test)
+	// the channel is looked up while the test is running, since it is removed when the test ends
+	responses := endpoint.testResponseChannel(options.test)
+
+	control.RunningActions.Add(1)
 	go func() {
-		control.RunningActions.Add(1)
 		defer control.RunningActions.Done()
 
 		endpoint.logOutgoingRequest(message.MessagePayload, req)
-		res, err := endpoint.client.Do(req)
+		responsePair := endpoint.doWithRetries(messageToSend, req)
+		responsePair.request = messageToSend
+		responsePair.tracked = tracked
+		responsePair.options = options
+		cancelAttempt := responsePair.cancel
+		responsePair.cancel = func() {
+			cancelAttempt()
+			cancelTimeout()
+		}
 
 		// we log the error here directly, but will do error handling downstream
-		if err != nil {
-			endpoint.logger.Errorf("error on response - %s", err)
+		responsePayload := ""
+		if responsePair.error != nil {
+			endpoint.logger.Errorf("error on response - %s", responsePair.error)
+			responsePair.cancel()
+		} else if endpoint.isStream(responsePair.response) {
+			// streams are consumed by the receive action, so the body must not be read here
+			endpoint.logIncomingStreamResponse(responsePair.response)
 		} else {
-			endpoint.logIncomingResponse(res)
+			responsePayload = endpoint.logIncomingResponse(responsePair.response)
+			responsePair.cancel()
 		}
 
-		responsePair := &responsePair{
-			response: res,
-			error:    err,
+		responsePair.timings.Finish()
+		endpoint.logger.Infof("exchange timings %s", responsePair.timings)
+		endpoint.recordHar(req, messageToSend.MessagePayload, responsePair, responsePayload)
+		if endpoint.openApiSpec != nil && responsePair.error == nil {
+			endpoint.openApiSpec.Record(responsePair.response.Request, responsePair.response.StatusCode)
 		}
 
 		select {
 		// we send the error downstream for it to be returned when an action is called
-		case endpoint.responseChannel <- responsePair:
+		case responses <- responsePair:
 		case <-time.After(config.ActionTimeout()):
 			endpoint.handleError("action timed out - no client receive action called in test", nil)
+			endpoint.tracker.TimedOut(tracked)
+		case <-ctx.Done():
+			endpoint.handleError("action canceled - no client receive action called before the context ended", ctx.Err())
+			endpoint.tracker.TimedOut(tracked)
 		}
 	}()
 
@@ -103,25 +201,180 @@ func (endpoint *Endpoint) send(message *message.RequestMessage) error {
 func (endpoint *Endpoint) receive(message *message.ResponseMessage, validationOptions receiveOptions) (*http.Response, error) {
 	endpoint.logger.Debugf("message to receive %s", message.ToString())
 
+	responsePair, err := endpoint.nextResponse(validationOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.tracker.Done(responsePair.tracked)
+	if responsePair.error != nil {
+		return responsePair.response, endpoint.handleError(
+			fmt.Sprintf("error while receiving response after %d attempt(s)", responsePair.attempts), responsePair.error)
+	}
+
+	messageToReceive := endpoint.getMessageToReceive(message)
+	endpoint.logger.Debugf("validating message %s", messageToReceive.ToString())
+
+	return responsePair.response, errors.Join(
+		endpoint.validateAttempts(validationOptions.expectedAttempts, responsePair.attempts),
+		endpoint.validateResponseTime(validationOptions.maxResponseTime, responsePair.timings),
+		validators.ValidateHttpProto(validationOptions.expectedProto, responsePair.response.Proto, endpoint.logger),
+		validators.ValidateHttpStatusCode(messageToReceive, responsePair.response.StatusCode, endpoint.logger),
+		validators.ValidateHttpHeaders(&messageToReceive.Message, responsePair.response.Header, endpoint.logger),
+		// the contract is validated before the payload, which closes the body
+		validators.ValidateOpenApiResponse(endpoint.openApiSpec, responsePair.response, endpoint.logger),
+		validators.ValidateHttpPayload(&messageToReceive.Message, responsePair.response.Body,
+			validationOptions.expectedPayloadType, endpoint.logger))
+}
+
+// receiveError expects the exchange to fail on transport level instead of returning a response
+func (endpoint *Endpoint) receiveError(expected transportError, validationOptions receiveOptions) error {
+	endpoint.logger.Debugf("transport error to receive [%s]", expected)
+
+	responsePair, err := endpoint.nextResponse(validationOptions)
+	if err != nil {
+		return err
+	}
+
+	endpoint.tracker.Done(responsePair.tracked)
+	if responsePair.error == nil {
+		endpoint.discardResponse(responsePair.response)
+		return endpoint.handleError(fmt.Sprintf("validation error - expected transport error [%s] but received response with status [%d]",
+			expected, responsePair.response.StatusCode), nil)
+	}
+
+	if !expected.matches(responsePair.error) {
+		return endpoint.handleError(fmt.Sprintf("validation error - transport error mismatch - expected [%s] but received [%s]",
+			expected, responsePair.error), nil)
+	}
+
+	endpoint.logger.Infof("transport error validation successful - received [%s]", expected)
+	return endpoint.validateAttempts(validationOptions.expectedAttempts, responsePair.attempts)
+}
+
+// nextResponse waits for the next response to a request of the test. Responses to requests that were not sent
+// by an isolated test are received by any test.
+func (endpoint *Endpoint) nextResponse(options receiveOptions) (*responsePair, error) {
+	ctx := internal.ActionContext(options.ctx)
+	testResponses := endpoint.testResponseChannel(options.test)
+	if testResponses == nil {
+		return nil, endpoint.handleError(fmt.Sprintf("receive action failed - test [%s] is not running", options.test), nil)
+	}
+
 	select {
 	case responsePair := <-endpoint.responseChannel:
-		if responsePair.error != nil {
-			return responsePair.response, endpoint.handleError("error while receiving response", responsePair.error)
+		return responsePair, nil
+	case responsePair := <-testResponses:
+		return responsePair, nil
+	case <-time.After(internal.ActionTimeout(options.timeout)):
+		return nil, endpoint.handleError("receive action timed out - no response received for validation", nil)
+	case <-ctx.Done():
+		return nil, endpoint.handleError("receive action canceled", ctx.Err())
+	}
+}
+
+// testResponseChannel is the channel of the responses to the requests of the test, or the shared channel
+// for requests of no test. It is nil for a test that is not running, so that no channel is left behind.
+func (endpoint *Endpoint) testResponseChannel(test string) chan *responsePair {
+	if test == "" {
+		return endpoint.responseChannel
+	}
+
+	endpoint.testChannelsLock.Lock()
+	defer endpoint.testChannelsLock.Unlock()
+
+	channel, exists := endpoint.testChannels[test]
+	if !exists && !exchanges.Running(test) {
+		return nil
+	}
+	if !exists {
+		channel = make(chan *responsePair)
+		endpoint.testChannels[test] = channel
+	}
+	return channel
+}
+
+// removeTestChannel removes the channel of the responses to the requests of a test that has ended
+func (endpoint *Endpoint) removeTestChannel(test string) {
+	endpoint.testChannelsLock.Lock()
+	defer endpoint.testChannelsLock.Unlock()
+
+	delete(endpoint.testChannels, test)
+}
+
+// doWithRetries sends the request and retries it as long as the retry policy allows it.
+// Each attempt uses a freshly built request, so that the payload can be sent again.
+// The timings are always the ones of the last attempt.
+func (endpoint *Endpoint) doWithRetries(message *message.RequestMessage, req *http.Request) *responsePair {
+	attempt := 1
+
+	for {
+		timings := timing.NewTimings()
+		ctx, cancel := context.WithCancel(httptrace.WithClientTrace(req.Context(), timings.ClientTrace()))
+
+		res, err := endpoint.client.Do(req.WithContext(ctx))
+		if err == nil {
+			if err = endpoint.interceptors.interceptResponse(res); err != nil {
+				endpoint.discardResponse(res)
+				return &responsePair{attempts: attempt, timings: timings, cancel: cancel,
+					error: fmt.Errorf("response interceptor failed - %w", err)}
+			}
 		}
 
-		messageToReceive := endpoint.getMessageToReceive(message)
-		endpoint.logger.Debugf("validating message %s", messageToReceive.ToString())
+		delay, retry := endpoint.retryPolicy.nextDelay(req.Context(), attempt, res, err)
+		if !retry {
+			return &responsePair{response: res, attempts: attempt, timings: timings, cancel: cancel, error: err}
+		}
 
-		return responsePair.response, errors.Join(
-			validators.ValidateHttpStatusCode(messageToReceive, responsePair.response.StatusCode, endpoint.logger),
-			validators.ValidateHttpHeaders(&messageToReceive.Message, responsePair.response.Header, endpoint.logger),
-			validators.ValidateHttpPayload(&messageToReceive.Message, responsePair.response.Body,
-				validationOptions.expectedPayloadType, endpoint.logger))
-	case <-time.After(config.ActionTimeout()):
-		return nil, endpoint.handleError("receive action timed out - no response received for validation", nil)
+		if err != nil {
+			endpoint.logger.Warnf("attempt %d failed - %s - retrying in %s", attempt, err, delay)
+		} else {
+			endpoint.logger.Warnf("attempt %d received status [%d] - retrying in %s", attempt, res.StatusCode, delay)
+			endpoint.discardResponse(res)
+		}
+		cancel()
+
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return &responsePair{attempts: attempt, timings: timings, cancel: func() {}, error: req.Context().Err()}
+		}
+		attempt++
+
+		if req, err = endpoint.buildRequest(req.Context(), message); err != nil {
+			return &responsePair{attempts: attempt, timings: timing.NewTimings(), cancel: func() {}, error: err}
+		}
 	}
 }
 
+func (endpoint *Endpoint) validateAttempts(expectedAttempts int, actualAttempts int) error {
+	if expectedAttempts == 0 {
+		return nil
+	}
+
+	if expectedAttempts != actualAttempts {
+		return endpoint.handleError(fmt.Sprintf("validation error - attempts mismatch - expected [%d] but were [%d]",
+			expectedAttempts, actualAttempts), nil)
+	}
+
+	endpoint.logger.Info("attempts validation successful")
+	return nil
+}
+
+func (endpoint *Endpoint) validateResponseTime(maxResponseTime time.Duration, timings *timing.Timings) error {
+	if maxResponseTime == 0 {
+		return nil
+	}
+
+	if timings.Total > maxResponseTime {
+		return endpoint.handleError(fmt.Sprintf("validation error - response time exceeded - expected within [%s] but took [%s]",
+			maxResponseTime, timings.Total), nil)
+	}
+
+	endpoint.logger.Info("response time validation successful")
+	return nil
+}
+
 // Put missing data into a message to send: baseUrl & ContentType Header
 func (endpoint *Endpoint) getMessageToSend(message *message.RequestMessage) *message.RequestMessage {
 	messageToSend := message.Clone()
@@ -165,10 +418,10 @@ func (endpoint *Endpoint) validateMessageToSend(messageToSend *message.RequestMe
 	return nil
 }
 
-func (endpoint *Endpoint) buildRequest(message *message.RequestMessage) (*http.Request, error) {
+func (endpoint *Endpoint) buildRequest(ctx context.Context, message *message.RequestMessage) (*http.Request, error) {
 	url := utils.BuildPath(message.Url, message.Path)
 
-	req, err := http.NewRequest(message.Method, url, bytes.NewBufferString(message.MessagePayload))
+	req, err := http.NewRequestWithContext(ctx, message.Method, url, bytes.NewBufferString(message.MessagePayload))
 	if err != nil {
 		endpoint.logger.Errorf("error - %s", err)
 		return nil, err
@@ -186,6 +439,11 @@ func (endpoint *Endpoint) buildRequest(message *message.RequestMessage) (*http.R
 	}
 	req.URL.RawQuery = qParams.Encode()
 
+	if err := endpoint.interceptors.interceptRequest(req); err != nil {
+		endpoint.logger.Errorf("request interceptor failed - %s", err)
+		return nil, err
+	}
+
 	return req, nil
 }
 
@@ -196,7 +454,7 @@ func (endpoint *Endpoint) handleError(message string, err error) error {
 	} else {
 		errorMessage = message
 	}
-	endpoint.logger.Errorf(errorMessage)
+	endpoint.logger.Error(errorMessage)
 	return errors.New(endpoint.logger.Prefix() + errorMessage)
 }
 
@@ -212,7 +470,7 @@ func (endpoint *Endpoint) logOutgoingRequest(payload string, req *http.Request)
 
 // we read the body 'as is' for logging, after which we put it back into the response
 // with an open reader so that it can be read downstream again
-func (endpoint *Endpoint) logIncomingResponse(res *http.Response) {
+func (endpoint *Endpoint) logIncomingResponse(res *http.Response) string {
 	bodyBytes, _ := io.ReadAll(res.Body)
 	bodyString := ""
 
@@ -230,6 +488,26 @@ func (endpoint *Endpoint) logIncomingResponse(res *http.Response) {
 		"payload: %s"+
 		"]",
 		res.Status, res.Header, bodyString)
+
+	return bodyString
+}
+
+func (endpoint *Endpoint) logIncomingStreamResponse(res *http.Response) {
+	endpoint.logger.Infof("received HTTP stream response ["+
+		"status: %s, "+
+		"headers: %s"+
+		"]",
+		res.Status, res.Header)
+}
+
+// a response which will be retried is never validated, but its body must be consumed
+// so that the underlying connection can be reused
+func (endpoint *Endpoint) discardResponse(res *http.Response) {
+	_, _ = io.Copy(io.Discard, res.Body)
+
+	if err := res.Body.Close(); err != nil {
+		endpoint.logger.Errorf("could not close response body - %s", err)
+	}
 }
 
 func clientLogPrefix(endpointName string) string {
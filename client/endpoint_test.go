@@ -1,9 +1,12 @@
 package client
 
 import (
+	"context"
+	"fmt"
 	"github.com/go-clarum/clarum-http/constants"
 	"github.com/go-clarum/clarum-http/message"
 	"net/http"
+	"strings"
 	"testing"
 	"time"
 )
@@ -154,7 +157,7 @@ func TestBuildRequest(t *testing.T) {
 		QueryParam("someParameter", "someValue").
 		Payload("batman!")
 
-	newRequest, err := endpoint.buildRequest(requestMessage)
+	newRequest, err := endpoint.buildRequest(context.Background(), requestMessage)
 	if err != nil {
 		t.Errorf("error is unexpected")
 	}
@@ -182,3 +185,149 @@ func TestBuildRequest(t *testing.T) {
 		t.Errorf("invalid newRequest.URL.QueryParams[someParameter]")
 	}
 }
+
+func TestBuildRequestUsesContext(t *testing.T) {
+	endpoint := newEndpoint("name", "baseUrl", "", 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	newRequest, err := endpoint.buildRequest(ctx, message.Get().BaseUrl("http://localhost:8080"))
+	if err != nil {
+		t.Errorf("error is unexpected")
+	}
+
+	if newRequest.Context() != ctx {
+		t.Errorf("request does not use the context of the action")
+	}
+}
+
+func TestFinishReportsUnhandledExchanges(t *testing.T) {
+	endpoint := NewEndpointBuilder().
+		Name("finishClient").
+		InProcess(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusOK)
+		})).
+		Build()
+
+	if err := endpoint.send(message.Get("users"), sendOptions{}); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	err := endpoint.finish()
+	expected := "finishClient: unhandled exchanges:\nresponse to [GET http://localhost/users] is waiting for a client receive action"
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error [%s], but got [%v]", expected, err)
+	}
+
+	// the response is consumed, so that the send goroutine does not wait for the timeout
+	if _, err := endpoint.receive(message.Response(http.StatusOK), receiveOptions{}); err != nil {
+		t.Errorf("No error expected, but got %s", err)
+	}
+	if err := endpoint.finish(); err != nil {
+		t.Errorf("No error expected after the response was received, but got %s", err)
+	}
+}
+
+// fakeTest records the cleanup & the errors of a test
+type fakeTest struct {
+	cleanups []func()
+	errors   []string
+}
+
+func (test *fakeTest) Name() string {
+	return "TestFake"
+}
+
+func (test *fakeTest) Cleanup(cleanup func()) {
+	test.cleanups = append(test.cleanups, cleanup)
+}
+
+func (test *fakeTest) Errorf(format string, args ...any) {
+	test.errors = append(test.errors, fmt.Sprintf(format, args...))
+}
+
+func (test *fakeTest) end() {
+	for i := len(test.cleanups) - 1; i >= 0; i-- {
+		test.cleanups[i]()
+	}
+}
+
+func TestUnreceivedResponseFailsTest(t *testing.T) {
+	endpoint := NewEndpointBuilder().
+		Name("watchedClient").
+		InProcess(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusOK)
+		})).
+		Build()
+
+	test := &fakeTest{}
+	endpoint.tracker.Watch(test, endpoint.logger.Prefix(), nil)
+	if err := endpoint.send(message.Get("users"), sendOptions{}); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	test.end()
+
+	expected := "watchedClient: unhandled exchanges at the end of the test:\n" +
+		"response to [GET http://localhost/users] is waiting for a client receive action"
+	if len(test.errors) != 1 || test.errors[0] != expected {
+		t.Errorf("Expected test error [%s], but got %s", expected, test.errors)
+	}
+	if err := endpoint.finish(); err != nil {
+		t.Errorf("Exchange must not be reported again when the test run finishes, but got %s", err)
+	}
+
+	// the next test is not affected by the unreceived response
+	nextTest := &fakeTest{}
+	endpoint.tracker.Watch(nextTest, endpoint.logger.Prefix(), nil)
+	nextTest.end()
+	if len(nextTest.errors) != 0 {
+		t.Errorf("No test error expected, but got %s", nextTest.errors)
+	}
+}
+
+func TestIsolatedTestChannelIsRemoved(t *testing.T) {
+	endpoint := NewEndpointBuilder().
+		Name("isolatedClient").
+		IsolateTests().
+		InProcess(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusOK)
+		})).
+		Build()
+
+	t.Run("isolated", func(t *testing.T) {
+		endpoint.In(t).Send().Message(message.Get("users"))
+		endpoint.In(t).Receive().Message(message.Response(http.StatusOK))
+
+		if len(endpoint.testChannels) != 1 {
+			t.Errorf("Expected the channel of the test, but got %v", endpoint.testChannels)
+		}
+	})
+
+	if len(endpoint.testChannels) != 0 {
+		t.Errorf("Channel of the test was not removed when it ended %v", endpoint.testChannels)
+	}
+}
+
+func TestReceiveOfEndedTestFails(t *testing.T) {
+	endpoint := NewEndpointBuilder().
+		Name("endedTestClient").
+		IsolateTests().
+		InProcess(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			writer.WriteHeader(http.StatusOK)
+		})).
+		Build()
+
+	ended := ""
+	t.Run("ended", func(t *testing.T) {
+		endpoint.In(t)
+		ended = t.Name()
+	})
+
+	_, err := endpoint.receive(message.Response(http.StatusOK), receiveOptions{test: ended, timeout: time.Minute})
+	if err == nil || !strings.Contains(err.Error(), "receive action failed - test ["+ended+"] is not running") {
+		t.Errorf("Expected error for the ended test, but got [%v]", err)
+	}
+	if len(endpoint.testChannels) != 0 {
+		t.Errorf("Channel was created for the ended test %v", endpoint.testChannels)
+	}
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"testing"
+)
+
+// ErrorReceiveActionBuilder used to configure a receive action on a client endpoint that expects a transport error
+// without the context of a test. The method chain will end with the expected error type, which will return an error.
+// The error will be returned if a response was received or if the transport failed in a different way.
+type ErrorReceiveActionBuilder struct {
+	endpoint *Endpoint
+	options  *receiveOptions
+}
+
+// TestErrorReceiveActionBuilder used to configure a receive action on a client endpoint that expects a transport error
+// with the context of a test. The method chain will end with the expected error type, which will not return anything.
+// If a response was received or the transport failed in a different way, the test will fail by calling t.Error().
+type TestErrorReceiveActionBuilder struct {
+	test *testing.T
+	ErrorReceiveActionBuilder
+}
+
+func (testBuilder *TestErrorReceiveActionBuilder) ConnectionRefused() {
+	testBuilder.expect(connectionRefused)
+}
+
+func (testBuilder *TestErrorReceiveActionBuilder) Timeout() {
+	testBuilder.expect(timeout)
+}
+
+func (testBuilder *TestErrorReceiveActionBuilder) TLSHandshake() {
+	testBuilder.expect(tlsHandshake)
+}
+
+func (testBuilder *TestErrorReceiveActionBuilder) ConnectionReset() {
+	testBuilder.expect(connectionReset)
+}
+
+func (testBuilder *TestErrorReceiveActionBuilder) EOF() {
+	testBuilder.expect(eof)
+}
+
+func (builder *ErrorReceiveActionBuilder) ConnectionRefused() error {
+	return builder.endpoint.receiveError(connectionRefused, *builder.options)
+}
+
+func (builder *ErrorReceiveActionBuilder) Timeout() error {
+	return builder.endpoint.receiveError(timeout, *builder.options)
+}
+
+func (builder *ErrorReceiveActionBuilder) TLSHandshake() error {
+	return builder.endpoint.receiveError(tlsHandshake, *builder.options)
+}
+
+func (builder *ErrorReceiveActionBuilder) ConnectionReset() error {
+	return builder.endpoint.receiveError(connectionReset, *builder.options)
+}
+
+func (builder *ErrorReceiveActionBuilder) EOF() error {
+	return builder.endpoint.receiveError(eof, *builder.options)
+}
+
+func (testBuilder *TestErrorReceiveActionBuilder) expect(expected transportError) {
+	if err := testBuilder.endpoint.receiveError(expected, *testBuilder.options); err != nil {
+		testBuilder.test.Error(err)
+	}
+}
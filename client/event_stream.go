@@ -0,0 +1,208 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-core/config"
+	"github.com/go-clarum/clarum-http/constants"
+	"github.com/go-clarum/clarum-http/internal"
+	"github.com/go-clarum/clarum-http/internal/sse"
+	"github.com/go-clarum/clarum-http/internal/validators"
+	"github.com/go-clarum/clarum-http/message"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// EventStream is an open text/event-stream response on a client endpoint without the context of a test.
+// Events are validated one by one, as they arrive.
+type EventStream struct {
+	endpoint    *Endpoint
+	request     *message.RequestMessage
+	options     sendOptions
+	response    *http.Response
+	payloadType internal.PayloadType
+	cancel      context.CancelFunc
+	events      chan *eventResult
+	readerDone  chan struct{}
+	closed      chan struct{}
+	closeOnce   sync.Once
+	lastEventId string
+}
+
+// TestEventStream is an open text/event-stream response on a client endpoint with the context of a test.
+// Any error encountered while receiving or validating events will fail the test by calling t.Error().
+type TestEventStream struct {
+	test *testing.T
+	*EventStream
+}
+
+type eventResult struct {
+	event *message.EventMessage
+	error error
+}
+
+// receiveEventStream validates the response headers & opens the stream. The body is not validated.
+func (endpoint *Endpoint) receiveEventStream(message *message.ResponseMessage, validationOptions receiveOptions) (*EventStream, error) {
+	endpoint.logger.Debugf("event stream to receive %s", message.ToString())
+
+	responsePair, err := endpoint.nextResponse(validationOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.tracker.Done(responsePair.tracked)
+	if responsePair.error != nil {
+		responsePair.cancel()
+		return nil, endpoint.handleError(
+			fmt.Sprintf("error while receiving response after %d attempt(s)", responsePair.attempts), responsePair.error)
+	}
+
+	if mediaType, _, _ := mime.ParseMediaType(responsePair.response.Header.Get(constants.ContentTypeHeaderName)); mediaType != sse.ContentType {
+		endpoint.discardResponse(responsePair.response)
+		responsePair.cancel()
+		return nil, endpoint.handleError(fmt.Sprintf("validation error - expected event stream but received content type [%s]",
+			responsePair.response.Header.Get(constants.ContentTypeHeaderName)), nil)
+	}
+
+	// the content type of the endpoint is not used here, since it is always the one of the event stream
+	eventStream := newEventStream(endpoint, responsePair, validationOptions.expectedPayloadType)
+	messageToReceive := message.Clone()
+
+	if err := errors.Join(
+		endpoint.validateAttempts(validationOptions.expectedAttempts, responsePair.attempts),
+		validators.ValidateHttpProto(validationOptions.expectedProto, responsePair.response.Proto, endpoint.logger),
+		validators.ValidateHttpStatusCode(messageToReceive, responsePair.response.StatusCode, endpoint.logger),
+		validators.ValidateHttpHeaders(&messageToReceive.Message, responsePair.response.Header, endpoint.logger),
+	); err != nil {
+		eventStream.Close()
+		return nil, err
+	}
+
+	return eventStream, nil
+}
+
+func newEventStream(endpoint *Endpoint, responsePair *responsePair, payloadType internal.PayloadType) *EventStream {
+	eventStream := &EventStream{
+		endpoint:    endpoint,
+		request:     responsePair.request,
+		options:     responsePair.options,
+		response:    responsePair.response,
+		payloadType: payloadType,
+		cancel:      responsePair.cancel,
+		events:      make(chan *eventResult),
+		readerDone:  make(chan struct{}),
+		closed:      make(chan struct{}),
+	}
+
+	go eventStream.read()
+
+	return eventStream
+}
+
+func (eventStream *EventStream) read() {
+	defer close(eventStream.readerDone)
+	reader := sse.NewReader(eventStream.response.Body)
+
+	for {
+		event, err := reader.Next()
+
+		select {
+		case eventStream.events <- &eventResult{event: event, error: err}:
+		case <-eventStream.closed:
+			return
+		}
+
+		if err != nil {
+			return
+		}
+	}
+}
+
+// Event blocks until the next event arrives and validates it against the expected one.
+// Only the fields set on the expected event are validated.
+func (eventStream *EventStream) Event(expected *message.EventMessage) error {
+	if eventStream == nil {
+		return errors.New("event stream is not open")
+	}
+	endpoint := eventStream.endpoint
+
+	select {
+	case result := <-eventStream.events:
+		if result.error == io.EOF {
+			return endpoint.handleError("event stream ended - no event received for validation", nil)
+		} else if result.error != nil {
+			return endpoint.handleError("error while receiving event", result.error)
+		}
+
+		endpoint.logger.Infof("received event %s", result.event.ToString())
+		if result.event.EventId != "" {
+			eventStream.lastEventId = result.event.EventId
+		}
+
+		return validators.ValidateEvent(expected, result.event, eventStream.payloadType, endpoint.logger)
+	case <-eventStream.closed:
+		return endpoint.handleError("event stream is closed", nil)
+	case <-time.After(config.ActionTimeout()):
+		return endpoint.handleError("event receive timed out - no event received for validation", nil)
+	}
+}
+
+// LastEventId returns the id of the last received event that had one
+func (eventStream *EventStream) LastEventId() string {
+	if eventStream == nil {
+		return ""
+	}
+	return eventStream.lastEventId
+}
+
+// Close ends the stream from the client side. The request is canceled first and the body is closed
+// only after the reader stopped.
+func (eventStream *EventStream) Close() {
+	if eventStream == nil {
+		return
+	}
+
+	eventStream.closeOnce.Do(func() {
+		close(eventStream.closed)
+		eventStream.cancel()
+		<-eventStream.readerDone
+
+		if err := eventStream.response.Body.Close(); err != nil {
+			eventStream.endpoint.logger.Errorf("could not close event stream - %s", err)
+		}
+	})
+}
+
+// Reconnect closes the stream and sends the original request again, with the Last-Event-ID header
+// set to the id of the last received event. The new stream is received with another receive action.
+func (eventStream *EventStream) Reconnect() error {
+	if eventStream == nil {
+		return errors.New("event stream is not open")
+	}
+	eventStream.Close()
+
+	request := eventStream.request.Clone()
+	if eventStream.lastEventId != "" {
+		request.Header(sse.LastEventIdHeaderName, eventStream.lastEventId)
+	}
+
+	eventStream.endpoint.logger.Infof("reconnecting event stream with last event id [%s]", eventStream.lastEventId)
+	return eventStream.endpoint.send(request, eventStream.options)
+}
+
+func (testStream *TestEventStream) Event(expected *message.EventMessage) {
+	if err := testStream.EventStream.Event(expected); err != nil {
+		testStream.test.Error(err)
+	}
+}
+
+func (testStream *TestEventStream) Reconnect() {
+	if err := testStream.EventStream.Reconnect(); err != nil {
+		testStream.test.Error(err)
+	}
+}
@@ -0,0 +1,53 @@
+package client
+
+import (
+	"github.com/go-clarum/clarum-http/har"
+	"github.com/go-clarum/clarum-http/internal/timing"
+	"net/http"
+	"time"
+)
+
+// recordHar adds the exchange to the HAR recorder of the endpoint, if one is configured.
+// The body of a stream is not recorded, since it is consumed by the test.
+func (endpoint *Endpoint) recordHar(req *http.Request, requestPayload string, responsePair *responsePair, responsePayload string) {
+	if endpoint.harRecorder == nil {
+		return
+	}
+
+	entry := har.Entry{
+		StartedDateTime: responsePair.timings.Start,
+		Time:            float64(responsePair.timings.Total) / float64(time.Millisecond),
+		Timings:         harTimings(responsePair.timings),
+	}
+
+	if responsePair.error != nil {
+		entry.Request = har.NewRequest(req, requestPayload)
+		entry.Response = har.NewResponse(0, "", http.Header{}, "")
+		entry.Comment = responsePair.error.Error()
+	} else {
+		// the request of the response is the one that was sent, after all interceptors were applied
+		entry.Request = har.NewRequest(responsePair.response.Request, requestPayload)
+		entry.Response = har.NewResponse(responsePair.response.StatusCode, responsePair.response.Proto,
+			responsePair.response.Header, responsePayload)
+	}
+
+	endpoint.harRecorder.Add(entry)
+}
+
+// the connect time of HAR includes the TLS handshake
+func harTimings(timings *timing.Timings) har.Timings {
+	connect := timings.Connect
+	if connect > 0 {
+		connect += timings.TLS
+	}
+
+	return har.Timings{
+		Blocked: -1,
+		DNS:     har.Milliseconds(timings.DNS),
+		Connect: har.Milliseconds(connect),
+		Ssl:     har.Milliseconds(timings.TLS),
+		Send:    0,
+		Wait:    max(har.Milliseconds(timings.FirstByte-timings.DNS-timings.Connect-timings.TLS), 0),
+		Receive: max(har.Milliseconds(timings.Total-timings.FirstByte), 0),
+	}
+}
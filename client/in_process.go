@@ -0,0 +1,47 @@
+package client
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+)
+
+const inProcessBaseUrl = "http://localhost"
+
+// handlerTransport dispatches requests directly into an http.Handler instead of sending them over the network.
+// The response is captured with an httptest.ResponseRecorder, so the handler must return for the response
+// to become available.
+type handlerTransport struct {
+	handler http.Handler
+}
+
+// RoundTrip returns a transport error if the handler panics, like a server that closes the connection. A handler that
+// aborts with http.ErrAbortHandler results in an error that matches both http.ErrAbortHandler and io.EOF.
+func (transport *handlerTransport) RoundTrip(request *http.Request) (response *http.Response, err error) {
+	serverRequest := request.Clone(request.Context())
+	serverRequest.RequestURI = request.URL.RequestURI()
+	serverRequest.RemoteAddr = "127.0.0.1:0"
+	if serverRequest.Body == nil {
+		serverRequest.Body = http.NoBody
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			response = nil
+			if r == http.ErrAbortHandler {
+				err = fmt.Errorf("in-process handler aborted the request - %w (%w)", http.ErrAbortHandler, io.EOF)
+			} else {
+				err = fmt.Errorf("in-process handler panicked - %v", r)
+			}
+		}
+	}()
+
+	recorder := httptest.NewRecorder()
+	transport.handler.ServeHTTP(recorder, serverRequest)
+
+	response = recorder.Result()
+	response.Request = request
+
+	return response, nil
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestHandlerTransport(t *testing.T) {
+	transport := &handlerTransport{
+		handler: http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			if request.RequestURI != "/my/api?someParameter=someValue" {
+				t.Errorf("invalid request.RequestURI [%s]", request.RequestURI)
+			}
+			body, _ := io.ReadAll(request.Body)
+
+			writer.Header().Set("ETag", "1234")
+			writer.WriteHeader(http.StatusCreated)
+			_, _ = writer.Write(body)
+		}),
+	}
+
+	endpoint := newEndpoint("name", inProcessBaseUrl, "", 0)
+	endpoint.client.Transport = transport
+
+	request, _ := http.NewRequest(http.MethodPost, "http://localhost/my/api?someParameter=someValue",
+		strings.NewReader("batman!"))
+
+	response, err := endpoint.client.Do(request)
+	if err != nil {
+		t.Fatalf("error is unexpected - %s", err)
+	}
+
+	if response.StatusCode != http.StatusCreated {
+		t.Errorf("invalid response.StatusCode")
+	}
+	if response.Header.Get("ETag") != "1234" {
+		t.Errorf("invalid response.Header[ETag]")
+	}
+	if body, _ := io.ReadAll(response.Body); string(body) != "batman!" {
+		t.Errorf("invalid response.Body")
+	}
+}
+
+func TestHandlerTransportPanic(t *testing.T) {
+	endpoint := newEndpoint("name", inProcessBaseUrl, "", 0)
+	endpoint.client.Transport = &handlerTransport{
+		handler: http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			panic("handler bug")
+		}),
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost/my/api", nil)
+
+	response, err := endpoint.client.Do(request)
+	if err == nil || !strings.Contains(err.Error(), "in-process handler panicked - handler bug") {
+		t.Errorf("Expected handler panic error, but got [%v]", err)
+	}
+	if response != nil {
+		t.Errorf("no response expected")
+	}
+}
+
+func TestHandlerTransportAbort(t *testing.T) {
+	endpoint := newEndpoint("name", inProcessBaseUrl, "", 0)
+	endpoint.client.Transport = &handlerTransport{
+		handler: http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			panic(http.ErrAbortHandler)
+		}),
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost/my/api", nil)
+
+	_, err := endpoint.client.Do(request)
+	if !errors.Is(err, http.ErrAbortHandler) || !errors.Is(err, io.EOF) {
+		t.Errorf("Expected aborted handler error, but got [%v]", err)
+	}
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"net/http"
+)
+
+// RequestInterceptor is called for every outgoing request, right before it is sent (also for each retry).
+// It may modify the request, for example to inject headers. Returning an error cancels the request.
+type RequestInterceptor func(request *http.Request) error
+
+// ResponseInterceptor is called for every incoming response, before it is logged and validated.
+// Returning an error will make the receive action fail.
+type ResponseInterceptor func(response *http.Response) error
+
+type interceptors struct {
+	beforeSend   []RequestInterceptor
+	afterReceive []ResponseInterceptor
+}
+
+func (interceptors *interceptors) interceptRequest(request *http.Request) error {
+	for _, interceptor := range interceptors.beforeSend {
+		if err := interceptor(request); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (interceptors *interceptors) interceptResponse(response *http.Response) error {
+	for _, interceptor := range interceptors.afterReceive {
+		if err := interceptor(response); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -1,14 +1,25 @@
 package client
 
 import (
+	"context"
 	"github.com/go-clarum/clarum-http/internal"
 	"github.com/go-clarum/clarum-http/message"
 	"net/http"
 	"testing"
+	"time"
 )
 
 type receiveOptions struct {
 	expectedPayloadType internal.PayloadType
+	expectedAttempts    int
+	maxResponseTime     time.Duration
+	expectedProto       string
+	// chunkDelimiter separates the chunks of a response stream
+	chunkDelimiter string
+	// test is the name of the test whose responses are received, if the endpoint isolates tests
+	test    string
+	ctx     context.Context
+	timeout time.Duration
 }
 
 // ReceiveActionBuilder used to configure a receive action on a client endpoint without the context of a test
@@ -37,6 +48,147 @@ func (builder *ReceiveActionBuilder) Json() *ReceiveActionBuilder {
 	return builder
 }
 
+// Attempts validates how many times the request was sent until this response was received.
+// Useful together with the retry configuration of the endpoint.
+func (testBuilder *TestReceiveActionBuilder) Attempts(attempts int) *TestReceiveActionBuilder {
+	testBuilder.options.expectedAttempts = attempts
+	return testBuilder
+}
+
+// Attempts validates how many times the request was sent until this response was received.
+// Useful together with the retry configuration of the endpoint.
+func (builder *ReceiveActionBuilder) Attempts(attempts int) *ReceiveActionBuilder {
+	builder.options.expectedAttempts = attempts
+	return builder
+}
+
+// Within validates that the response was received completely in the given time after the request was sent.
+// When the request was retried, only the last attempt is measured.
+func (testBuilder *TestReceiveActionBuilder) Within(maxResponseTime time.Duration) *TestReceiveActionBuilder {
+	testBuilder.options.maxResponseTime = maxResponseTime
+	return testBuilder
+}
+
+// Within validates that the response was received completely in the given time after the request was sent.
+// When the request was retried, only the last attempt is measured.
+func (builder *ReceiveActionBuilder) Within(maxResponseTime time.Duration) *ReceiveActionBuilder {
+	builder.options.maxResponseTime = maxResponseTime
+	return builder
+}
+
+// Proto validates the protocol of the response, for example 'HTTP/1.1' or 'HTTP/2.0'.
+func (testBuilder *TestReceiveActionBuilder) Proto(proto string) *TestReceiveActionBuilder {
+	testBuilder.options.expectedProto = proto
+	return testBuilder
+}
+
+// Proto validates the protocol of the response, for example 'HTTP/1.1' or 'HTTP/2.0'.
+func (builder *ReceiveActionBuilder) Proto(proto string) *ReceiveActionBuilder {
+	builder.options.expectedProto = proto
+	return builder
+}
+
+// ChunkDelimiter splits the body of a response stream into chunks on the given delimiter, which is not part of a chunk.
+// By default, NDJSON streams are split on line breaks & any other stream on the reads of the body.
+func (testBuilder *TestReceiveActionBuilder) ChunkDelimiter(delimiter string) *TestReceiveActionBuilder {
+	testBuilder.options.chunkDelimiter = delimiter
+	return testBuilder
+}
+
+// ChunkDelimiter splits the body of a response stream into chunks on the given delimiter, which is not part of a chunk.
+// By default, NDJSON streams are split on line breaks & any other stream on the reads of the body.
+func (builder *ReceiveActionBuilder) ChunkDelimiter(delimiter string) *ReceiveActionBuilder {
+	builder.options.chunkDelimiter = delimiter
+	return builder
+}
+
+// Context cancels the action when the context ends. The context of the test is used by default.
+func (testBuilder *TestReceiveActionBuilder) Context(ctx context.Context) *TestReceiveActionBuilder {
+	testBuilder.options.ctx = ctx
+	return testBuilder
+}
+
+// Context cancels the action when the context ends.
+func (builder *ReceiveActionBuilder) Context(ctx context.Context) *ReceiveActionBuilder {
+	builder.options.ctx = ctx
+	return builder
+}
+
+// Timeout overrides the action timeout of the config for this action: how long it waits for a response.
+func (testBuilder *TestReceiveActionBuilder) Timeout(timeout time.Duration) *TestReceiveActionBuilder {
+	testBuilder.options.timeout = timeout
+	return testBuilder
+}
+
+// Timeout overrides the action timeout of the config for this action: how long it waits for a response.
+func (builder *ReceiveActionBuilder) Timeout(timeout time.Duration) *ReceiveActionBuilder {
+	builder.options.timeout = timeout
+	return builder
+}
+
+// ExpectError switches the receive action to expect a transport error instead of a response.
+func (testBuilder *TestReceiveActionBuilder) ExpectError() *TestErrorReceiveActionBuilder {
+	return &TestErrorReceiveActionBuilder{
+		test: testBuilder.test,
+		ErrorReceiveActionBuilder: ErrorReceiveActionBuilder{
+			endpoint: testBuilder.endpoint,
+			options:  testBuilder.options,
+		},
+	}
+}
+
+// ExpectError switches the receive action to expect a transport error instead of a response.
+func (builder *ReceiveActionBuilder) ExpectError() *ErrorReceiveActionBuilder {
+	return &ErrorReceiveActionBuilder{
+		endpoint: builder.endpoint,
+		options:  builder.options,
+	}
+}
+
+// EventStream validates the status & headers of a text/event-stream response and opens the stream,
+// so that events can be validated one by one, as they arrive. Use Json() before to validate event data as JSON.
+// The stream is closed when the test ends, if it was not closed before.
+func (testBuilder *TestReceiveActionBuilder) EventStream(message *message.ResponseMessage) *TestEventStream {
+	eventStream, err := testBuilder.endpoint.receiveEventStream(message, *testBuilder.options)
+	if err != nil {
+		testBuilder.test.Error(err)
+	}
+	testBuilder.test.Cleanup(eventStream.Close)
+
+	return &TestEventStream{
+		test:        testBuilder.test,
+		EventStream: eventStream,
+	}
+}
+
+// EventStream validates the status & headers of a text/event-stream response and opens the stream,
+// so that events can be validated one by one, as they arrive. Use Json() before to validate event data as JSON.
+func (builder *ReceiveActionBuilder) EventStream(message *message.ResponseMessage) (*EventStream, error) {
+	return builder.endpoint.receiveEventStream(message, *builder.options)
+}
+
+// Stream validates the status & headers of a streamed response and opens the stream, so that the body
+// can be validated incrementally. Use Json() before to validate records as JSON.
+// The stream is closed when the test ends, if it was not closed before.
+func (testBuilder *TestReceiveActionBuilder) Stream(message *message.ResponseMessage) *TestResponseStream {
+	responseStream, err := testBuilder.endpoint.receiveResponseStream(message, *testBuilder.options)
+	if err != nil {
+		testBuilder.test.Error(err)
+	}
+	testBuilder.test.Cleanup(responseStream.Close)
+
+	return &TestResponseStream{
+		test:           testBuilder.test,
+		ResponseStream: responseStream,
+	}
+}
+
+// Stream validates the status & headers of a streamed response and opens the stream, so that the body
+// can be validated incrementally. Use Json() before to validate records as JSON.
+func (builder *ReceiveActionBuilder) Stream(message *message.ResponseMessage) (*ResponseStream, error) {
+	return builder.endpoint.receiveResponseStream(message, *builder.options)
+}
+
 func (testBuilder *TestReceiveActionBuilder) Message(message *message.ResponseMessage) {
 	if _, err := testBuilder.endpoint.receive(message, *testBuilder.options); err != nil {
 		testBuilder.test.Error(err)
@@ -0,0 +1,322 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-core/config"
+	"github.com/go-clarum/clarum-http/constants"
+	"github.com/go-clarum/clarum-http/internal"
+	"github.com/go-clarum/clarum-http/internal/validators"
+	"github.com/go-clarum/clarum-http/message"
+	"io"
+	"mime"
+	"net/http"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+const streamReadBufferSize = 32 * 1024
+
+// ResponseStream is an open streamed response on a client endpoint without the context of a test.
+// The body is validated incrementally, chunk by chunk or record by record, instead of being buffered.
+// Chunks are split on the delimiter of the receive action, or on line breaks for NDJSON. Without a delimiter,
+// a chunk is the data returned by one read on the body, which only matches a flush of the server
+// as long as the server pauses between flushes.
+type ResponseStream struct {
+	endpoint    *Endpoint
+	response    *http.Response
+	payloadType internal.PayloadType
+	delimiter   string
+	cancel      context.CancelFunc
+	reads       chan *readResult
+	readerDone  chan struct{}
+	closed      chan struct{}
+	closeOnce   sync.Once
+	pending     []byte
+	ended       bool
+}
+
+// TestResponseStream is an open streamed response on a client endpoint with the context of a test.
+// Any error encountered while receiving or validating will fail the test by calling t.Error().
+type TestResponseStream struct {
+	test *testing.T
+	*ResponseStream
+}
+
+type readResult struct {
+	data  []byte
+	error error
+}
+
+// isStream checks if the response body must be left to be consumed by a stream receive action
+func (endpoint *Endpoint) isStream(res *http.Response) bool {
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get(constants.ContentTypeHeaderName))
+	return slices.Contains(endpoint.streamContentTypes, mediaType)
+}
+
+// receiveResponseStream validates the response headers & opens the stream. The body is not validated.
+func (endpoint *Endpoint) receiveResponseStream(message *message.ResponseMessage, validationOptions receiveOptions) (*ResponseStream, error) {
+	endpoint.logger.Debugf("response stream to receive %s", message.ToString())
+
+	responsePair, err := endpoint.nextResponse(validationOptions)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint.tracker.Done(responsePair.tracked)
+	if responsePair.error != nil {
+		responsePair.cancel()
+		return nil, endpoint.handleError(
+			fmt.Sprintf("error while receiving response after %d attempt(s)", responsePair.attempts), responsePair.error)
+	}
+
+	responseStream := newResponseStream(endpoint, responsePair, validationOptions.expectedPayloadType,
+		chunkDelimiter(validationOptions.chunkDelimiter, responsePair.response))
+	messageToReceive := endpoint.getMessageToReceive(message)
+
+	if err := errors.Join(
+		endpoint.validateAttempts(validationOptions.expectedAttempts, responsePair.attempts),
+		validators.ValidateHttpProto(validationOptions.expectedProto, responsePair.response.Proto, endpoint.logger),
+		validators.ValidateHttpStatusCode(messageToReceive, responsePair.response.StatusCode, endpoint.logger),
+		validators.ValidateHttpHeaders(&messageToReceive.Message, responsePair.response.Header, endpoint.logger),
+	); err != nil {
+		responseStream.Close()
+		return nil, err
+	}
+
+	return responseStream, nil
+}
+
+// chunkDelimiter is the delimiter of the receive action, or a line break for NDJSON streams
+func chunkDelimiter(delimiter string, res *http.Response) string {
+	if delimiter != "" {
+		return delimiter
+	}
+	mediaType, _, _ := mime.ParseMediaType(res.Header.Get(constants.ContentTypeHeaderName))
+	if mediaType == constants.ContentTypeNdJsonHeader {
+		return "\n"
+	}
+	return ""
+}
+
+func newResponseStream(endpoint *Endpoint, responsePair *responsePair, payloadType internal.PayloadType,
+	delimiter string) *ResponseStream {
+	responseStream := &ResponseStream{
+		endpoint:    endpoint,
+		response:    responsePair.response,
+		payloadType: payloadType,
+		delimiter:   delimiter,
+		cancel:      responsePair.cancel,
+		reads:       make(chan *readResult),
+		readerDone:  make(chan struct{}),
+		closed:      make(chan struct{}),
+	}
+
+	go responseStream.read()
+
+	return responseStream
+}
+
+func (responseStream *ResponseStream) read() {
+	defer close(responseStream.readerDone)
+
+	for {
+		buffer := make([]byte, streamReadBufferSize)
+		n, err := responseStream.response.Body.Read(buffer)
+
+		if n > 0 {
+			if !responseStream.publish(&readResult{data: buffer[:n]}) {
+				return
+			}
+		}
+		if err != nil {
+			responseStream.publish(&readResult{error: err})
+			return
+		}
+	}
+}
+
+func (responseStream *ResponseStream) publish(result *readResult) bool {
+	select {
+	case responseStream.reads <- result:
+		return true
+	case <-responseStream.closed:
+		return false
+	}
+}
+
+// next returns the data of the next read on the body
+func (responseStream *ResponseStream) next() ([]byte, error) {
+	if responseStream.ended {
+		return nil, io.EOF
+	}
+
+	select {
+	case result := <-responseStream.reads:
+		if result.error != nil {
+			responseStream.ended = true
+		}
+		return result.data, result.error
+	case <-responseStream.closed:
+		return nil, errors.New("response stream is closed")
+	case <-time.After(config.ActionTimeout()):
+		return nil, errors.New("no data received")
+	}
+}
+
+// Chunk blocks until the next chunk arrives and validates it against the expected data.
+func (responseStream *ResponseStream) Chunk(expected string) error {
+	if responseStream == nil {
+		return errors.New("response stream is not open")
+	}
+	endpoint := responseStream.endpoint
+
+	var chunk []byte
+	var err error
+	if responseStream.delimiter != "" {
+		chunk, err = responseStream.nextDelimited(responseStream.delimiter)
+	} else {
+		chunk, err = responseStream.nextRead()
+	}
+	if err != nil {
+		return endpoint.handleError("error while receiving chunk", err)
+	}
+
+	endpoint.logger.Infof("received chunk [%s]", chunk)
+	return responseStream.validate(expected, chunk)
+}
+
+// Record blocks until the next line-delimited record arrives and validates it against the expected one.
+// Use Json() on the receive action to validate NDJSON records.
+func (responseStream *ResponseStream) Record(expected string) error {
+	if responseStream == nil {
+		return errors.New("response stream is not open")
+	}
+	endpoint := responseStream.endpoint
+
+	record, err := responseStream.nextDelimited("\n")
+	if err != nil {
+		return endpoint.handleError("error while receiving record", err)
+	}
+
+	endpoint.logger.Infof("received record [%s]", record)
+	return responseStream.validate(expected, record)
+}
+
+// nextRead returns the data left over from a previous part, or the data of the next read on the body
+func (responseStream *ResponseStream) nextRead() ([]byte, error) {
+	if len(responseStream.pending) > 0 {
+		data := responseStream.pending
+		responseStream.pending = nil
+		return data, nil
+	}
+	return responseStream.next()
+}
+
+// nextDelimited returns the data up to the next delimiter, without it. The last part does not need a delimiter.
+// Line breaks also remove a preceding carriage return.
+func (responseStream *ResponseStream) nextDelimited(delimiter string) ([]byte, error) {
+	for {
+		if index := bytes.Index(responseStream.pending, []byte(delimiter)); index >= 0 {
+			part := responseStream.pending[:index]
+			responseStream.pending = responseStream.pending[index+len(delimiter):]
+			if delimiter == "\n" {
+				part = bytes.TrimSuffix(part, []byte("\r"))
+			}
+			return part, nil
+		}
+
+		data, err := responseStream.next()
+		if err == io.EOF && len(responseStream.pending) > 0 {
+			part := responseStream.pending
+			responseStream.pending = nil
+			return part, nil
+		} else if err != nil {
+			return nil, err
+		}
+		responseStream.pending = append(responseStream.pending, data...)
+	}
+}
+
+// End validates that the body has no more data.
+func (responseStream *ResponseStream) End() error {
+	if responseStream == nil {
+		return errors.New("response stream is not open")
+	}
+	endpoint := responseStream.endpoint
+
+	if len(responseStream.pending) > 0 {
+		return endpoint.handleError(fmt.Sprintf("validation error - expected end of stream but received [%s]",
+			responseStream.pending), nil)
+	}
+
+	data, err := responseStream.next()
+	if err == nil {
+		responseStream.pending = data
+		return endpoint.handleError(fmt.Sprintf("validation error - expected end of stream but received [%s]", data), nil)
+	} else if err != io.EOF {
+		return endpoint.handleError("error while receiving end of stream", err)
+	}
+
+	endpoint.logger.Info("end of stream validation successful")
+	return nil
+}
+
+// Trailer validates a trailer sent after the body. Trailers are only available after End() was successful.
+func (responseStream *ResponseStream) Trailer(key string, value string) error {
+	if responseStream == nil {
+		return errors.New("response stream is not open")
+	}
+
+	return validators.ValidateHttpTrailer(key, value, responseStream.response.Trailer, responseStream.endpoint.logger)
+}
+
+// Close ends the stream from the client side. The request is canceled first and the body is closed
+// only after the reader stopped, since closing the body during a read can block the connection for the next exchange.
+func (responseStream *ResponseStream) Close() {
+	if responseStream == nil {
+		return
+	}
+
+	responseStream.closeOnce.Do(func() {
+		close(responseStream.closed)
+		responseStream.cancel()
+		<-responseStream.readerDone
+
+		if err := responseStream.response.Body.Close(); err != nil {
+			responseStream.endpoint.logger.Errorf("could not close response stream - %s", err)
+		}
+	})
+}
+
+func (responseStream *ResponseStream) validate(expected string, actual []byte) error {
+	return validators.ValidateStreamPart(expected, actual, responseStream.payloadType, responseStream.endpoint.logger)
+}
+
+func (testStream *TestResponseStream) Chunk(expected string) {
+	if err := testStream.ResponseStream.Chunk(expected); err != nil {
+		testStream.test.Error(err)
+	}
+}
+
+func (testStream *TestResponseStream) Record(expected string) {
+	if err := testStream.ResponseStream.Record(expected); err != nil {
+		testStream.test.Error(err)
+	}
+}
+
+func (testStream *TestResponseStream) End() {
+	if err := testStream.ResponseStream.End(); err != nil {
+		testStream.test.Error(err)
+	}
+}
+
+func (testStream *TestResponseStream) Trailer(key string, value string) {
+	if err := testStream.ResponseStream.Trailer(key, value); err != nil {
+		testStream.test.Error(err)
+	}
+}
@@ -0,0 +1,84 @@
+package client
+
+import (
+	"context"
+	"github.com/go-clarum/clarum-core/config"
+	"github.com/go-clarum/clarum-core/durations"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/constants"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+const defaultRetryBackoff = 100 * time.Millisecond
+
+// retryPolicy decides if a request has to be sent again. Retries are disabled by default (maxRetries = 0).
+// A request is retried when the transport returned an error (connection refused, reset etc.)
+// or when the response has one of the configured status codes.
+type retryPolicy struct {
+	maxRetries  uint
+	statusCodes []int
+	backoff     time.Duration
+	// maxDelay caps the wait between two attempts, the action timeout is used if not set
+	maxDelay time.Duration
+	logger   *logging.Logger
+}
+
+// nextDelay returns the time to wait before the next attempt and whether another attempt should be made at all.
+// The backoff doubles with every attempt. If the response contains a Retry-After header, its value is used instead.
+// Both are capped by the max delay, so that a retry never waits longer than a receive action.
+// A request whose context has ended is never retried.
+func (policy *retryPolicy) nextDelay(ctx context.Context, attempt int, res *http.Response, err error) (time.Duration, bool) {
+	if uint(attempt) > policy.maxRetries || ctx.Err() != nil {
+		return 0, false
+	}
+
+	if err == nil && !slices.Contains(policy.statusCodes, res.StatusCode) {
+		return 0, false
+	}
+
+	if err == nil {
+		if delay, ok := parseRetryAfter(res.Header.Get(constants.RetryAfterHeaderName)); ok {
+			if maxDelay := policy.maxDelayOrDefault(); delay > maxDelay {
+				policy.logger.Warnf("Retry-After delay [%s] of the server is capped to [%s]", delay, maxDelay)
+				return maxDelay, true
+			}
+			return delay, true
+		}
+	}
+
+	return policy.backoffDelay(attempt), true
+}
+
+// backoffDelay doubles the backoff with every attempt, without overflowing the max delay
+func (policy *retryPolicy) backoffDelay(attempt int) time.Duration {
+	maxDelay := policy.maxDelayOrDefault()
+	shift := attempt - 1
+	if shift >= 62 || policy.backoff > maxDelay>>shift {
+		return maxDelay
+	}
+	return policy.backoff << shift
+}
+
+func (policy *retryPolicy) maxDelayOrDefault() time.Duration {
+	return durations.GetDurationWithDefault(policy.maxDelay, config.ActionTimeout())
+}
+
+// parseRetryAfter supports both formats of the header: delay-seconds & HTTP-date
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+
+	return 0, false
+}
@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/constants"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNoRetriesByDefault(t *testing.T) {
+	policy := retryPolicy{}
+
+	if _, retry := policy.nextDelay(context.Background(), 1, nil, errors.New("connection refused")); retry {
+		t.Errorf("no retry expected")
+	}
+}
+
+func TestRetryOnTransportError(t *testing.T) {
+	policy := retryPolicy{maxRetries: 2, backoff: time.Second}
+
+	delay, retry := policy.nextDelay(context.Background(), 1, nil, errors.New("connection refused"))
+	if !retry || delay != time.Second {
+		t.Errorf("retry after 1s expected, but got %v - %s", retry, delay)
+	}
+
+	delay, retry = policy.nextDelay(context.Background(), 2, nil, errors.New("connection refused"))
+	if !retry || delay != 2*time.Second {
+		t.Errorf("retry after 2s expected, but got %v - %s", retry, delay)
+	}
+
+	if _, retry = policy.nextDelay(context.Background(), 3, nil, errors.New("connection refused")); retry {
+		t.Errorf("no retry expected after max retries")
+	}
+}
+
+func TestNoRetryWhenContextEnded(t *testing.T) {
+	policy := retryPolicy{maxRetries: 2, backoff: time.Second}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, retry := policy.nextDelay(ctx, 1, nil, context.Canceled); retry {
+		t.Errorf("no retry expected after the context ended")
+	}
+}
+
+func TestRetryOnStatus(t *testing.T) {
+	policy := retryPolicy{maxRetries: 1, statusCodes: []int{http.StatusServiceUnavailable}, backoff: time.Second}
+
+	if _, retry := policy.nextDelay(context.Background(), 1, &http.Response{StatusCode: http.StatusOK}, nil); retry {
+		t.Errorf("no retry expected for status 200")
+	}
+
+	delay, retry := policy.nextDelay(context.Background(), 1, &http.Response{StatusCode: http.StatusServiceUnavailable}, nil)
+	if !retry || delay != time.Second {
+		t.Errorf("retry after 1s expected, but got %v - %s", retry, delay)
+	}
+}
+
+func TestRetryAfterHeader(t *testing.T) {
+	policy := retryPolicy{maxRetries: 1, statusCodes: []int{http.StatusTooManyRequests}, backoff: time.Second}
+	res := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{constants.RetryAfterHeaderName: []string{"3"}},
+	}
+
+	delay, retry := policy.nextDelay(context.Background(), 1, res, nil)
+	if !retry || delay != 3*time.Second {
+		t.Errorf("retry after 3s expected, but got %v - %s", retry, delay)
+	}
+}
+
+func TestRetryAfterHeaderIsCapped(t *testing.T) {
+	policy := retryPolicy{maxRetries: 1, statusCodes: []int{http.StatusTooManyRequests}, backoff: time.Second,
+		maxDelay: 5 * time.Second, logger: logging.NewLogger(slog.LevelError, "test: ")}
+	res := &http.Response{
+		StatusCode: http.StatusTooManyRequests,
+		Header:     http.Header{constants.RetryAfterHeaderName: []string{"86400"}},
+	}
+
+	delay, retry := policy.nextDelay(context.Background(), 1, res, nil)
+	if !retry || delay != 5*time.Second {
+		t.Errorf("retry after 5s expected, but got %v - %s", retry, delay)
+	}
+}
+
+func TestBackoffIsCapped(t *testing.T) {
+	policy := retryPolicy{maxRetries: 100, backoff: time.Second, maxDelay: 5 * time.Second}
+
+	delay, _ := policy.nextDelay(context.Background(), 4, nil, errors.New("connection refused"))
+	if delay != 5*time.Second {
+		t.Errorf("backoff must be capped to 5s, but got %s", delay)
+	}
+
+	delay, _ = policy.nextDelay(context.Background(), 100, nil, errors.New("connection refused"))
+	if delay != 5*time.Second {
+		t.Errorf("backoff must not overflow, but got %s", delay)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if _, ok := parseRetryAfter(""); ok {
+		t.Errorf("empty value must not be parsed")
+	}
+	if _, ok := parseRetryAfter("soon"); ok {
+		t.Errorf("invalid value must not be parsed")
+	}
+	if delay, ok := parseRetryAfter("Mon, 02 Jan 2006 15:04:05 GMT"); !ok || delay != 0 {
+		t.Errorf("date in the past must result in no delay")
+	}
+}
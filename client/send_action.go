@@ -1,8 +1,10 @@
 package client
 
 import (
+	"context"
 	"github.com/go-clarum/clarum-http/message"
 	"testing"
+	"time"
 )
 
 // SendActionBuilder used to configure a send action on a client endpoint without the context of a test
@@ -10,6 +12,7 @@ import (
 // The error will be a problem encountered during sending.
 type SendActionBuilder struct {
 	endpoint *Endpoint
+	options  sendOptions
 }
 
 // TestSendActionBuilder used to configure a send action on a client endpoint with the context of a test
@@ -20,12 +23,38 @@ type TestSendActionBuilder struct {
 	SendActionBuilder
 }
 
+// Context is used for the request, which is canceled when the context ends. The context of the test is used by default.
+func (testBuilder *TestSendActionBuilder) Context(ctx context.Context) *TestSendActionBuilder {
+	testBuilder.options.ctx = ctx
+	return testBuilder
+}
+
+// Context is used for the request, which is canceled when the context ends.
+func (builder *SendActionBuilder) Context(ctx context.Context) *SendActionBuilder {
+	builder.options.ctx = ctx
+	return builder
+}
+
+// Timeout cancels the request if its response was not received completely in time, which includes reading a stream.
+// An exchange that times out is received as a transport error.
+func (testBuilder *TestSendActionBuilder) Timeout(timeout time.Duration) *TestSendActionBuilder {
+	testBuilder.options.timeout = timeout
+	return testBuilder
+}
+
+// Timeout cancels the request if its response was not received completely in time, which includes reading a stream.
+// An exchange that times out is received as a transport error.
+func (builder *SendActionBuilder) Timeout(timeout time.Duration) *SendActionBuilder {
+	builder.options.timeout = timeout
+	return builder
+}
+
 func (testBuilder *TestSendActionBuilder) Message(message *message.RequestMessage) {
-	if err := testBuilder.endpoint.send(message); err != nil {
+	if err := testBuilder.endpoint.send(message, testBuilder.options); err != nil {
 		testBuilder.test.Error(err)
 	}
 }
 
 func (builder *SendActionBuilder) Message(message *message.RequestMessage) error {
-	return builder.endpoint.send(message)
+	return builder.endpoint.send(message, builder.options)
 }
@@ -12,7 +12,11 @@ type TestActionBuilder struct {
 	endpoint *Endpoint
 }
 
+// In runs the actions in the context of the test. The test fails when it ends, if a response to a request it sent
+// was never received by a receive action. The OpenAPI coverage report is saved when the test ends, so that it is
+// written even if the test run is not finished with clarumhttp.Finish().
 func (endpoint *Endpoint) In(t *testing.T) *TestActionBuilder {
+	endpoint.tracker.Watch(t, endpoint.logger.Prefix(), func() { endpoint.testEnded(t.Name()) })
 	return &TestActionBuilder{
 		test:     t,
 		endpoint: endpoint,
@@ -39,6 +43,7 @@ func (testBuilder *TestActionBuilder) Send() *TestSendActionBuilder {
 		test: testBuilder.test,
 		SendActionBuilder: SendActionBuilder{
 			endpoint: testBuilder.endpoint,
+			options:  sendOptions{test: testBuilder.isolatedTest(), ctx: testBuilder.test.Context()},
 		},
 	}
 }
@@ -50,7 +55,30 @@ func (testBuilder *TestActionBuilder) Receive() *TestReceiveActionBuilder {
 			endpoint: testBuilder.endpoint,
 			options: &receiveOptions{
 				expectedPayloadType: internal.Plaintext,
+				test:                testBuilder.isolatedTest(),
+				ctx:                 testBuilder.test.Context(),
 			},
 		},
 	}
 }
+
+// isolatedTest is the name of the test, if the endpoint isolates tests
+func (testBuilder *TestActionBuilder) isolatedTest() string {
+	if !testBuilder.endpoint.isolateTests {
+		return ""
+	}
+	return testBuilder.test.Name()
+}
+
+// testEnded is called once at the end of every test that used the endpoint
+func (endpoint *Endpoint) testEnded(test string) {
+	if endpoint.isolateTests {
+		endpoint.removeTestChannel(test)
+	}
+	if endpoint.openApiSpec == nil {
+		return
+	}
+	if err := endpoint.openApiSpec.SaveCoverage(); err != nil {
+		endpoint.logger.Errorf("could not save OpenAPI coverage - %s", err)
+	}
+}
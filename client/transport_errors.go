@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+)
+
+// transportError is a category of errors returned by the HTTP transport instead of a response
+type transportError int
+
+const (
+	connectionRefused transportError = iota
+	timeout
+	tlsHandshake
+	connectionReset
+	eof
+)
+
+func (expected transportError) String() string {
+	switch expected {
+	case connectionRefused:
+		return "connection refused"
+	case timeout:
+		return "timeout"
+	case tlsHandshake:
+		return "TLS handshake"
+	case connectionReset:
+		return "connection reset"
+	case eof:
+		return "EOF"
+	default:
+		return "unknown"
+	}
+}
+
+// matches checks if the error returned by the transport belongs to the expected category
+func (expected transportError) matches(err error) bool {
+	switch expected {
+	case connectionRefused:
+		return errors.Is(err, syscall.ECONNREFUSED)
+	case timeout:
+		var netErr net.Error
+		return errors.Is(err, context.DeadlineExceeded) || (errors.As(err, &netErr) && netErr.Timeout())
+	case tlsHandshake:
+		return isTLSError(err)
+	case connectionReset:
+		return errors.Is(err, syscall.ECONNRESET)
+	case eof:
+		return errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+	default:
+		return false
+	}
+}
+
+// isTLSError checks the typed errors of the TLS handshake & certificate verification first. The "tls: " prefix is
+// only a fallback for the handshake errors that crypto/tls returns untyped, like an unsupported protocol version.
+func isTLSError(err error) bool {
+	var verificationErr *tls.CertificateVerificationError
+	var recordHeaderErr tls.RecordHeaderError
+	var alertErr tls.AlertError
+	if errors.As(err, &verificationErr) || errors.As(err, &recordHeaderErr) || errors.As(err, &alertErr) {
+		return true
+	}
+
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	var invalidCertErr x509.CertificateInvalidError
+	if errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr) || errors.As(err, &invalidCertErr) {
+		return true
+	}
+
+	return strings.Contains(err.Error(), "tls: ")
+}
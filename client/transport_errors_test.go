@@ -0,0 +1,50 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestTransportErrorMatches(t *testing.T) {
+	refused := wrapAsClientError(&net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)})
+	reset := wrapAsClientError(&net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)})
+	deadline := wrapAsClientError(context.DeadlineExceeded)
+	handshake := wrapAsClientError(&tls.CertificateVerificationError{Err: errors.New("x509: unknown authority")})
+	recordHeader := wrapAsClientError(tls.RecordHeaderError{Msg: "first record does not look like a TLS handshake"})
+	alert := wrapAsClientError(tls.AlertError(40))
+	untyped := wrapAsClientError(errors.New("tls: server selected unsupported protocol version 300"))
+	closed := wrapAsClientError(io.EOF)
+	unexpectedEOF := wrapAsClientError(io.ErrUnexpectedEOF)
+
+	checkMatch(t, connectionRefused, refused, true)
+	checkMatch(t, connectionRefused, reset, false)
+	checkMatch(t, connectionReset, reset, true)
+	checkMatch(t, connectionReset, closed, false)
+	checkMatch(t, timeout, deadline, true)
+	checkMatch(t, timeout, refused, false)
+	checkMatch(t, tlsHandshake, handshake, true)
+	checkMatch(t, tlsHandshake, recordHeader, true)
+	checkMatch(t, tlsHandshake, alert, true)
+	checkMatch(t, tlsHandshake, untyped, true)
+	checkMatch(t, tlsHandshake, closed, false)
+	checkMatch(t, eof, closed, true)
+	checkMatch(t, eof, unexpectedEOF, true)
+	checkMatch(t, eof, deadline, false)
+}
+
+func wrapAsClientError(err error) error {
+	return &url.Error{Op: "Get", URL: "http://localhost", Err: err}
+}
+
+func checkMatch(t *testing.T, expected transportError, err error, shouldMatch bool) {
+	if expected.matches(err) != shouldMatch {
+		t.Errorf("expected [%s] match on [%s] to be %v", expected, err, shouldMatch)
+	}
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"errors"
+	"github.com/go-clarum/clarum-http/contract"
+	"github.com/go-clarum/clarum-http/internal"
+	"testing"
+)
+
+// Verify replays every interaction of the contract against the provider & validates its responses.
+// The endpoint plays the role of the consumer, so its base url must point to the provider.
+// JSON bodies are validated as JSON, any other body as plain text.
+func (endpoint *Endpoint) Verify(pact *contract.Pact) error {
+	var verificationErrors []error
+	for _, interaction := range pact.Interactions {
+		verificationErrors = append(verificationErrors, endpoint.verify(interaction))
+	}
+
+	return errors.Join(verificationErrors...)
+}
+
+// Verify replays every interaction of the contract as a subtest, named after the interaction
+func (testBuilder *TestActionBuilder) Verify(pact *contract.Pact) {
+	for _, interaction := range pact.Interactions {
+		testBuilder.test.Run(interaction.Description, func(t *testing.T) {
+			if err := testBuilder.endpoint.verify(interaction); err != nil {
+				t.Error(err)
+			}
+		})
+	}
+}
+
+func (endpoint *Endpoint) verify(interaction contract.Interaction) error {
+	endpoint.logger.Infof("verifying interaction [%s] of provider", interaction.Description)
+
+	if err := endpoint.send(interaction.RequestMessage(), sendOptions{}); err != nil {
+		return err
+	}
+
+	options := receiveOptions{expectedPayloadType: internal.Plaintext}
+	if interaction.IsJson() {
+		options.expectedPayloadType = internal.Json
+	}
+	_, err := endpoint.receive(interaction.ResponseMessage(), options)
+
+	return err
+}
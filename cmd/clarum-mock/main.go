@@ -0,0 +1,50 @@
+// Command clarum-mock runs server endpoints with the stubs defined in a YAML file, without writing Go.
+// The file is watched and the stubs are reloaded when it changes. With an admin port, the endpoints
+// can also be managed through the admin REST API.
+//
+//	clarum-mock -clm-stubs clarum-stubs.yaml -clm-admin-port 9000
+package main
+
+import (
+	"flag"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/admin"
+	"github.com/go-clarum/clarum-http/registry"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+var stubsFile = flag.String("clm-stubs", "clarum-stubs.yaml", "YAML file with the endpoint & stub definitions.")
+var reloadInterval = flag.Duration("clm-reload", time.Second, "Interval in which the stubs file is checked for changes.")
+var adminPort = flag.Uint("clm-admin-port", 0, "Port of the admin API. The admin API is disabled by default.")
+
+func main() {
+	flag.Parse()
+
+	endpoints := registry.New()
+	var adminServer *admin.Server
+	if *adminPort != 0 {
+		adminServer = admin.NewBuilder().Port(*adminPort).Registry(endpoints).Build()
+	}
+
+	mock := newMockServer(*stubsFile, adminServer, endpoints)
+	mock.reload()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	ticker := time.NewTicker(*reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			mock.reload()
+		case <-signals:
+			logging.Info("stopping mock server")
+			mock.close()
+			return
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"github.com/go-clarum/clarum-core/files"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/admin"
+	"github.com/go-clarum/clarum-http/registry"
+	"github.com/go-clarum/clarum-http/server"
+	"github.com/go-clarum/clarum-http/stubs"
+	"os"
+	"reflect"
+	"time"
+)
+
+// Definitions is the content of the stubs file
+type Definitions struct {
+	Endpoints []EndpointDefinition `yaml:"endpoints"`
+}
+
+type EndpointDefinition struct {
+	Name        string             `yaml:"name"`
+	Port        uint               `yaml:"port"`
+	ContentType string             `yaml:"contentType"`
+	Stubs       []stubs.Definition `yaml:"stubs"`
+}
+
+// mockServer runs one server endpoint per definition and keeps them in sync with the stubs file
+type mockServer struct {
+	filePath  string
+	modified  time.Time
+	endpoints map[string]*runningEndpoint
+	admin     *admin.Server
+	registry  *registry.Registry
+}
+
+type runningEndpoint struct {
+	definition EndpointDefinition
+	endpoint   *server.Endpoint
+}
+
+// the endpoints are registered with the admin API, if there is one, and with the registry, so that port conflicts
+// are reported before an endpoint is started
+func newMockServer(filePath string, adminServer *admin.Server, endpoints *registry.Registry) *mockServer {
+	return &mockServer{
+		filePath:  filePath,
+		endpoints: make(map[string]*runningEndpoint),
+		admin:     adminServer,
+		registry:  endpoints,
+	}
+}
+
+// reload reads the stubs file if it changed since the last reload. An invalid file is reported,
+// the endpoints keep running with the last valid definitions.
+func (mock *mockServer) reload() {
+	info, err := os.Stat(mock.filePath)
+	if err != nil {
+		logging.Errorf("could not read stubs file - %s", err)
+		return
+	}
+	if !info.ModTime().After(mock.modified) {
+		return
+	}
+	mock.modified = info.ModTime()
+
+	definitions, err := files.ReadYamlFileToStruct[Definitions](mock.filePath)
+	if err != nil {
+		return
+	}
+
+	logging.Infof("loading stubs file [%s]", mock.filePath)
+	mock.apply(definitions)
+}
+
+// apply starts new endpoints, stops removed ones and replaces the stubs of the others. An endpoint is restarted
+// only if its port or content type changed. An endpoint whose port is in conflict keeps its last valid definition.
+func (mock *mockServer) apply(definitions *Definitions) {
+	defined := make(map[string]bool)
+	for _, definition := range definitions.Endpoints {
+		defined[definition.Name] = true
+	}
+
+	// removed endpoints are stopped first, so that their ports can be used by the other definitions
+	for name, running := range mock.endpoints {
+		if !defined[name] {
+			mock.stop(running)
+			delete(mock.endpoints, name)
+		}
+	}
+
+	for _, definition := range definitions.Endpoints {
+		endpointStubs, err := stubs.Stubs(definition.Stubs)
+		if err != nil {
+			logging.Errorf("invalid stubs of endpoint [%s] - %s", definition.Name, err)
+			continue
+		}
+
+		running, exists := mock.endpoints[definition.Name]
+		if exists && sameListener(running.definition, definition) {
+			if !reflect.DeepEqual(running.definition.Stubs, definition.Stubs) {
+				running.endpoint.ReplaceStubs(endpointStubs...)
+				logging.Infof("reloaded %d stubs of endpoint [%s]", len(endpointStubs), definition.Name)
+			}
+			running.definition = definition
+			continue
+		}
+
+		if err := mock.registry.Check(registry.Server, definition.Name, definition.Port); err != nil {
+			logging.Errorf("could not start endpoint [%s] - %s", definition.Name, err)
+			continue
+		}
+
+		if exists {
+			mock.stop(running)
+		}
+		endpoint := server.NewEndpointBuilder().
+			Name(definition.Name).
+			Port(definition.Port).
+			ContentType(definition.ContentType).
+			Stubs(endpointStubs...).
+			StubsOnly().
+			Registry(mock.registry).
+			Build()
+		mock.endpoints[definition.Name] = &runningEndpoint{definition: definition, endpoint: endpoint}
+		if mock.admin != nil {
+			mock.admin.Register(endpoint)
+		}
+		logging.Infof("started endpoint [%s] on port %d with %d stubs", definition.Name, definition.Port,
+			len(endpointStubs))
+	}
+}
+
+func (mock *mockServer) close() {
+	for name, running := range mock.endpoints {
+		mock.stop(running)
+		delete(mock.endpoints, name)
+	}
+	if mock.admin != nil {
+		if err := mock.admin.Close(); err != nil {
+			logging.Errorf("could not stop admin API - %s", err)
+		}
+	}
+}
+
+func (mock *mockServer) stop(running *runningEndpoint) {
+	if mock.admin != nil {
+		mock.admin.Unregister(running.definition.Name)
+	}
+	// the endpoint is finished right away, so that rebuilt endpoints are not kept until the process ends
+	if err := running.endpoint.Finish(); err != nil {
+		logging.Errorf("could not stop endpoint [%s] - %s", running.definition.Name, err)
+	} else {
+		logging.Infof("stopped endpoint [%s]", running.definition.Name)
+	}
+}
+
+func sameListener(first EndpointDefinition, second EndpointDefinition) bool {
+	return first.Port == second.Port && first.ContentType == second.ContentType
+}
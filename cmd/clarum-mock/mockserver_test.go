@@ -0,0 +1,144 @@
+package main
+
+import (
+	"github.com/go-clarum/clarum-http/admin"
+	"github.com/go-clarum/clarum-http/registry"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const firstDefinitions = `
+endpoints:
+  - name: users
+    port: 8104
+    stubs:
+      - name: get-user
+        request:
+          method: GET
+          path: /users/1
+        response:
+          status: 200
+          headers:
+            Content-Type: application/json
+          payload: '{"id": 1}'
+`
+
+const secondDefinitions = `
+endpoints:
+  - name: users
+    port: 8104
+    stubs:
+      - name: get-user
+        request:
+          method: GET
+          path: /users/1
+        response:
+          status: 410
+  - name: orders
+    port: 8105
+    stubs:
+      - name: any-order
+        request:
+          path: /orders
+        response:
+          payload: none
+`
+
+func TestReload(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "clarum-stubs.yaml")
+	writeDefinitions(t, filePath, firstDefinitions, time.Now())
+
+	adminServer := admin.NewBuilder().InProcess().Build()
+	mock := newMockServer(filePath, adminServer, registry.New())
+	defer mock.close()
+	mock.reload()
+
+	expectResponse(t, "http://localhost:8104/users/1", http.StatusOK, "{\"id\": 1}")
+	// requests without stub are not passed to tests
+	expectResponse(t, "http://localhost:8104/users/2", http.StatusNotFound, "")
+
+	writeDefinitions(t, filePath, secondDefinitions, time.Now().Add(time.Second))
+	mock.reload()
+
+	expectResponse(t, "http://localhost:8104/users/1", http.StatusGone, "")
+	expectResponse(t, "http://localhost:8105/orders", http.StatusOK, "none")
+
+	writeDefinitions(t, filePath, "endpoints: [", time.Now().Add(2*time.Second))
+	mock.reload()
+
+	// the last valid definitions are kept
+	expectResponse(t, "http://localhost:8105/orders", http.StatusOK, "none")
+
+	writeDefinitions(t, filePath, firstDefinitions, time.Now().Add(3*time.Second))
+	mock.reload()
+
+	if _, err := http.Get("http://localhost:8105/orders"); err == nil {
+		t.Errorf("Removed endpoint is still running")
+	}
+
+	recorder := httptest.NewRecorder()
+	adminServer.ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/endpoints", nil))
+	if strings.TrimSpace(recorder.Body.String()) != "[\"users\"]" {
+		t.Errorf("Removed endpoint is still registered with the admin API: %s", recorder.Body)
+	}
+}
+
+const conflictingDefinitions = `
+endpoints:
+  - name: users
+    port: 8104
+  - name: orders
+    port: 8104
+    stubs:
+      - name: any-order
+        request:
+          path: /orders
+        response:
+          payload: moved
+`
+
+func TestReloadWithPortConflict(t *testing.T) {
+	filePath := filepath.Join(t.TempDir(), "clarum-stubs.yaml")
+	writeDefinitions(t, filePath, secondDefinitions, time.Now())
+
+	mock := newMockServer(filePath, nil, registry.New())
+	defer mock.close()
+	mock.reload()
+
+	writeDefinitions(t, filePath, conflictingDefinitions, time.Now().Add(time.Second))
+	mock.reload()
+
+	// the endpoint keeps its last valid definition
+	expectResponse(t, "http://localhost:8105/orders", http.StatusOK, "none")
+	expectResponse(t, "http://localhost:8104/users/1", http.StatusNotFound, "")
+}
+
+func writeDefinitions(t *testing.T, filePath string, definitions string, modified time.Time) {
+	if err := os.WriteFile(filePath, []byte(definitions), 0644); err != nil {
+		t.Fatalf("Could not write definitions - %s", err)
+	}
+	// the modification time is set explicitly, as writes in quick succession can have the same time
+	if err := os.Chtimes(filePath, modified, modified); err != nil {
+		t.Fatalf("Could not set modification time - %s", err)
+	}
+}
+
+func expectResponse(t *testing.T, url string, expectedStatus int, expectedPayload string) {
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	defer response.Body.Close()
+
+	payload, _ := io.ReadAll(response.Body)
+	if response.StatusCode != expectedStatus || string(payload) != expectedPayload {
+		t.Errorf("Expected [%d: %s] from %s, but got [%d: %s]", expectedStatus, expectedPayload, url,
+			response.StatusCode, payload)
+	}
+}
@@ -4,6 +4,11 @@ const (
 	ContentTypeHeaderName   = "Content-Type"
 	AuthorizationHeaderName = "Authorization"
 	ETagHeaderName          = "ETag"
+	RetryAfterHeaderName    = "Retry-After"
+	CacheControlHeaderName  = "Cache-Control"
+	// TestHeaderName carries the name of the test that sent a request, for endpoints that isolate tests
+	TestHeaderName = "X-Clarum-Test"
 
-	ContentTypeJsonHeader = "application/json"
+	ContentTypeJsonHeader   = "application/json"
+	ContentTypeNdJsonHeader = "application/x-ndjson"
 )
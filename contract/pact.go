@@ -0,0 +1,252 @@
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/internal/lifecycle"
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+const pactSpecificationVersion = "3.0.0"
+
+// Pact is a consumer driven contract in the Pact v3 format. The interactions are the requests the consumer
+// sends to the provider, together with the responses it expects.
+type Pact struct {
+	Consumer     Participant   `json:"consumer"`
+	Provider     Participant   `json:"provider"`
+	Interactions []Interaction `json:"interactions"`
+	Metadata     Metadata      `json:"metadata"`
+	lock         sync.Mutex
+}
+
+type Participant struct {
+	Name string `json:"name"`
+}
+
+type Interaction struct {
+	Description    string          `json:"description"`
+	ProviderStates []ProviderState `json:"providerStates,omitempty"`
+	Request        Request         `json:"request"`
+	Response       Response        `json:"response"`
+}
+
+type ProviderState struct {
+	Name string `json:"name"`
+}
+
+type Request struct {
+	Method  string              `json:"method"`
+	Path    string              `json:"path"`
+	Query   map[string][]string `json:"query,omitempty"`
+	Headers map[string]string   `json:"headers,omitempty"`
+	Body    json.RawMessage     `json:"body,omitempty"`
+}
+
+type Response struct {
+	Status  int               `json:"status"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    json.RawMessage   `json:"body,omitempty"`
+}
+
+type Metadata struct {
+	PactSpecification PactSpecification `json:"pactSpecification"`
+}
+
+type PactSpecification struct {
+	Version string `json:"version"`
+}
+
+func NewPact(consumer string, provider string) *Pact {
+	return &Pact{
+		Consumer: Participant{Name: consumer},
+		Provider: Participant{Name: provider},
+		Metadata: Metadata{PactSpecification: PactSpecification{Version: pactSpecificationVersion}},
+	}
+}
+
+// Load reads a contract file, as written by a pact or by other Pact v3 implementations
+func Load(filePath string) (*Pact, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	pact := &Pact{}
+	if err := json.Unmarshal(data, pact); err != nil {
+		return nil, fmt.Errorf("invalid contract file [%s] - %w", filePath, err)
+	}
+	return pact, nil
+}
+
+// WriteOnFinish saves the contract into the given directory when the test run finishes,
+// as '<consumer>-<provider>.json'.
+func (pact *Pact) WriteOnFinish(dir string) *Pact {
+	lifecycle.OnFinish(func() error {
+		filePath := filepath.Join(dir, pact.Consumer.Name+"-"+pact.Provider.Name+".json")
+		if err := pact.Save(filePath); err != nil {
+			return fmt.Errorf("could not write contract - %w", err)
+		}
+		logging.Infof("contract written to [%s]", filePath)
+		return nil
+	})
+	return pact
+}
+
+// Add converts the expected request & the response into an interaction. Only the parts of the request that are
+// expected by the test are part of the contract. Identical interactions are added only once.
+func (pact *Pact) Add(request *message.RequestMessage, response *message.ResponseMessage) {
+	interaction := Interaction{
+		Request: Request{
+			Method:  request.Method,
+			Path:    "/" + strings.TrimPrefix(path.Clean("/"+request.Path), "/"),
+			Headers: nonEmpty(request.Headers),
+			Body:    body(request.MessagePayload),
+		},
+		Response: Response{
+			Status:  response.StatusCode,
+			Headers: nonEmpty(response.Headers),
+			Body:    body(response.MessagePayload),
+		},
+	}
+	if len(request.QueryParams) > 0 {
+		interaction.Request.Query = request.QueryParams
+	}
+	interaction.Description = fmt.Sprintf("%s %s -> %d", interaction.Request.Method, interaction.Request.Path,
+		interaction.Response.Status)
+
+	pact.lock.Lock()
+	defer pact.lock.Unlock()
+
+	// descriptions must be unique for the same provider state
+	duplicates := 0
+	for _, existing := range pact.Interactions {
+		if sameInteraction(existing, interaction) {
+			return
+		}
+		if strings.HasPrefix(existing.Description, interaction.Description) {
+			duplicates++
+		}
+	}
+	if duplicates > 0 {
+		interaction.Description = fmt.Sprintf("%s (%d)", interaction.Description, duplicates+1)
+	}
+
+	pact.Interactions = append(pact.Interactions, interaction)
+}
+
+func (pact *Pact) Save(filePath string) error {
+	pact.lock.Lock()
+	data, err := json.MarshalIndent(pact, "", "  ")
+	pact.lock.Unlock()
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filePath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0644)
+}
+
+// RequestMessage converts the request of the interaction, to be sent to the provider
+func (interaction *Interaction) RequestMessage() *message.RequestMessage {
+	request := &message.RequestMessage{
+		Method: interaction.Request.Method,
+		Path:   interaction.Request.Path,
+	}
+	for key, values := range interaction.Request.Query {
+		request.QueryParam(key, values...)
+	}
+	for header, value := range interaction.Request.Headers {
+		request.Header(header, value)
+	}
+	if payload := payload(interaction.Request.Body); payload != "" {
+		request.Payload(payload)
+	}
+
+	return request
+}
+
+// ResponseMessage converts the response of the interaction, to validate the response of the provider
+func (interaction *Interaction) ResponseMessage() *message.ResponseMessage {
+	response := message.Response(interaction.Response.Status)
+	for header, value := range interaction.Response.Headers {
+		response.Header(header, value)
+	}
+	if payload := payload(interaction.Response.Body); payload != "" {
+		response.Payload(payload)
+	}
+
+	return response
+}
+
+// IsJson reports whether the response body is JSON, in which case it is validated semantically
+func (interaction *Interaction) IsJson() bool {
+	var text string
+	return len(interaction.Response.Body) > 0 && json.Unmarshal(interaction.Response.Body, &text) != nil
+}
+
+// JSON payloads are embedded as they are, any other payload as a JSON string
+func body(payload string) json.RawMessage {
+	if payload == "" {
+		return nil
+	}
+	if json.Valid([]byte(payload)) {
+		return json.RawMessage(payload)
+	}
+
+	encoded, _ := json.Marshal(payload)
+	return encoded
+}
+
+func payload(body json.RawMessage) string {
+	if len(body) == 0 || string(body) == "null" {
+		return ""
+	}
+
+	var text string
+	if err := json.Unmarshal(body, &text); err == nil {
+		return text
+	}
+
+	// saved contracts are indented
+	var compacted bytes.Buffer
+	if err := json.Compact(&compacted, body); err != nil {
+		return string(body)
+	}
+	return compacted.String()
+}
+
+func nonEmpty(headers map[string]string) map[string]string {
+	result := make(map[string]string)
+	for header, value := range headers {
+		if value != "" {
+			result[http.CanonicalHeaderKey(header)] = value
+		}
+	}
+
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+func sameInteraction(first Interaction, second Interaction) bool {
+	firstJson, _ := json.Marshal(first.Request)
+	secondJson, _ := json.Marshal(second.Request)
+	if string(firstJson) != string(secondJson) {
+		return false
+	}
+
+	firstJson, _ = json.Marshal(first.Response)
+	secondJson, _ = json.Marshal(second.Response)
+	return string(firstJson) == string(secondJson)
+}
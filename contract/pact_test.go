@@ -0,0 +1,68 @@
+package contract
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+func TestAddInteractions(t *testing.T) {
+	pact := NewPact("web", "users")
+
+	request := message.Get("users", "1").QueryParam("details", "true").ContentType("")
+	pact.Add(request, message.Response(http.StatusOK).ContentType("application/json").Payload("{\"id\": 1}"))
+	// identical interactions are added once
+	pact.Add(request, message.Response(http.StatusOK).ContentType("application/json").Payload("{\"id\": 1}"))
+	pact.Add(request, message.Response(http.StatusOK).Payload("plain"))
+
+	if len(pact.Interactions) != 2 {
+		t.Fatalf("Expected 2 interactions, but got %d", len(pact.Interactions))
+	}
+
+	first := pact.Interactions[0]
+	if first.Description != "GET /users/1 -> 200" || first.Request.Path != "/users/1" ||
+		first.Request.Query["details"][0] != "true" || first.Request.Headers != nil {
+		t.Errorf("Unexpected interaction %+v", first)
+	}
+	if string(first.Response.Body) != "{\"id\": 1}" || !first.IsJson() {
+		t.Errorf("JSON body expected, but got %s", first.Response.Body)
+	}
+
+	second := pact.Interactions[1]
+	if second.Description != "GET /users/1 -> 200 (2)" || string(second.Response.Body) != "\"plain\"" || second.IsJson() {
+		t.Errorf("Unexpected interaction %+v", second)
+	}
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	pact := NewPact("web", "users")
+	pact.Add(message.Post("users").ContentType("application/json").Payload("{\"name\": \"bruce\"}"),
+		message.Response(http.StatusCreated).Payload("created"))
+
+	filePath := filepath.Join(t.TempDir(), "web-users.json")
+	if err := pact.Save(filePath); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	loaded, err := Load(filePath)
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if loaded.Consumer.Name != "web" || loaded.Provider.Name != "users" ||
+		loaded.Metadata.PactSpecification.Version != "3.0.0" || len(loaded.Interactions) != 1 {
+		t.Fatalf("Unexpected contract %+v", loaded)
+	}
+
+	interaction := loaded.Interactions[0]
+	request := interaction.RequestMessage()
+	if request.Method != http.MethodPost || request.Path != "/users" ||
+		request.MessagePayload != "{\"name\":\"bruce\"}" || request.Headers["Content-Type"] != "application/json" {
+		t.Errorf("Unexpected request %s", request.ToString())
+	}
+
+	response := interaction.ResponseMessage()
+	if response.StatusCode != http.StatusCreated || response.MessagePayload != "created" {
+		t.Errorf("Unexpected response %s", response.ToString())
+	}
+}
@@ -0,0 +1,21 @@
+package http
+
+import (
+	clarumcore "github.com/go-clarum/clarum-core"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/internal/lifecycle"
+)
+
+// Finish waits for all actions to finish, like clarumcore.Finish(), and then finishes the HTTP endpoints: reports are
+// written, exchanges that were not handled by the tests are reported and the servers are shut down. Use it instead of
+// clarumcore.Finish() in TestMain; the returned error lists the unhandled exchanges and should fail the test run.
+// clarum-core has no hook to run code when it finishes, so calling only clarumcore.Finish() skips all of the above.
+func Finish() error {
+	clarumcore.Finish()
+
+	err := lifecycle.Finish()
+	if err != nil {
+		logging.Errorf("test run finished with errors:\n%s", err)
+	}
+	return err
+}
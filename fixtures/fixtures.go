@@ -0,0 +1,224 @@
+package fixtures
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+const RedactedValue = "[REDACTED]"
+
+// Base64Encoding marks a body that is not valid UTF-8 and was therefore saved base64 encoded
+const Base64Encoding = "base64"
+
+// Fixture is a single recorded interaction, saved in its own file
+type Fixture struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+	// FilePath is set when the fixture is loaded
+	FilePath string `json:"-"`
+}
+
+type Request struct {
+	Method  string              `json:"method"`
+	Path    string              `json:"path"`
+	Query   map[string][]string `json:"query,omitempty"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    string              `json:"body,omitempty"`
+	// Encoding is 'base64' if the body is base64 encoded
+	Encoding string `json:"encoding,omitempty"`
+	// BodyHash is calculated before the redaction, so that the fixture can still be matched by the original body
+	BodyHash string `json:"bodyHash"`
+	// QueryHash is calculated before the redaction, so that the fixture can still be matched by the original query
+	QueryHash string `json:"queryHash,omitempty"`
+}
+
+type Response struct {
+	Status  int                 `json:"status"`
+	Headers map[string][]string `json:"headers,omitempty"`
+	Body    string              `json:"body,omitempty"`
+	// Encoding is 'base64' if the body is base64 encoded
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// BodyBytes returns the body as it was received, decoding it if needed
+func (request *Request) BodyBytes() ([]byte, error) {
+	return decodeBody(request.Body, request.Encoding)
+}
+
+// BodyBytes returns the body as it was received, decoding it if needed
+func (response *Response) BodyBytes() ([]byte, error) {
+	return decodeBody(response.Body, response.Encoding)
+}
+
+// Redaction removes sensitive data from fixtures before they are written to disk.
+// The values of the headers are replaced & every match of the body patterns is replaced, in the bodies
+// as well as in the raw query of the request.
+type Redaction struct {
+	Headers      []string
+	BodyPatterns []*regexp.Regexp
+}
+
+// Recorder writes fixtures to a directory. Files are numbered in the order of the interactions,
+// existing files with the same name are overwritten.
+type Recorder struct {
+	dir       string
+	redaction Redaction
+	counter   int
+	lock      sync.Mutex
+}
+
+func NewRecorder(dir string, redaction Redaction) *Recorder {
+	return &Recorder{
+		dir:       dir,
+		redaction: redaction,
+	}
+}
+
+// Record builds a fixture from the exchange, redacts it and writes it to a new file. The file path is returned.
+func (recorder *Recorder) Record(request *http.Request, requestBody []byte, response *http.Response, responseBody []byte) (string, error) {
+	fixture := &Fixture{
+		Request: Request{
+			Method:   request.Method,
+			Path:     request.URL.Path,
+			Query:    recorder.redactQuery(request.URL.RawQuery),
+			Headers:  recorder.redactHeaders(request.Header),
+			BodyHash: HashBody(requestBody),
+		},
+		Response: Response{
+			Status:  response.StatusCode,
+			Headers: recorder.redactHeaders(response.Header),
+		},
+	}
+	if request.URL.RawQuery != "" {
+		fixture.Request.QueryHash = HashQuery(request.URL.Query())
+	}
+	fixture.Request.Body, fixture.Request.Encoding = encodeBody(recorder.redactBody(requestBody))
+	fixture.Response.Body, fixture.Response.Encoding = encodeBody(recorder.redactBody(responseBody))
+	// the length changes with the redaction
+	delete(fixture.Response.Headers, "Content-Length")
+
+	data, err := json.MarshalIndent(fixture, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(recorder.dir, 0o755); err != nil {
+		return "", err
+	}
+
+	recorder.lock.Lock()
+	recorder.counter++
+	fileName := fmt.Sprintf("%04d-%s-%s.json", recorder.counter, strings.ToLower(request.Method), fileNamePart(request.URL.Path))
+	recorder.lock.Unlock()
+
+	filePath := filepath.Join(recorder.dir, fileName)
+	return filePath, os.WriteFile(filePath, data, 0o644)
+}
+
+func (recorder *Recorder) redactHeaders(headers http.Header) map[string][]string {
+	result := make(map[string][]string, len(headers))
+	for header, values := range headers {
+		result[header] = append([]string(nil), values...)
+	}
+
+	for _, header := range recorder.redaction.Headers {
+		canonicalHeader := http.CanonicalHeaderKey(header)
+		if _, exists := result[canonicalHeader]; exists {
+			result[canonicalHeader] = []string{RedactedValue}
+		}
+	}
+
+	return result
+}
+
+func (recorder *Recorder) redactBody(body []byte) []byte {
+	for _, pattern := range recorder.redaction.BodyPatterns {
+		body = pattern.ReplaceAll(body, []byte(RedactedValue))
+	}
+	return body
+}
+
+// redactQuery applies the body patterns to the raw query, so that they match the query as it was sent
+func (recorder *Recorder) redactQuery(rawQuery string) url.Values {
+	query, _ := url.ParseQuery(string(recorder.redactBody([]byte(rawQuery))))
+	return query
+}
+
+// encodeBody keeps text bodies readable & encodes binary bodies (images, gzip, protobuf), which JSON cannot hold
+func encodeBody(body []byte) (string, string) {
+	if utf8.Valid(body) {
+		return string(body), ""
+	}
+	return base64.StdEncoding.EncodeToString(body), Base64Encoding
+}
+
+func decodeBody(body string, encoding string) ([]byte, error) {
+	switch encoding {
+	case "":
+		return []byte(body), nil
+	case Base64Encoding:
+		return base64.StdEncoding.DecodeString(body)
+	default:
+		return nil, fmt.Errorf("unsupported body encoding [%s]", encoding)
+	}
+}
+
+// HashBody returns the hex encoded SHA-256 hash of a body
+func HashBody(body []byte) string {
+	hash := sha256.Sum256(body)
+	return hex.EncodeToString(hash[:])
+}
+
+// HashQuery is independent of the order of the query parameters, but not of the order of the values of a parameter
+func HashQuery(query url.Values) string {
+	return HashBody([]byte(query.Encode()))
+}
+
+// Load reads all fixtures of a directory, ordered by file name
+func Load(dir string) ([]*Fixture, error) {
+	filePaths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(filePaths)
+
+	fixtures := make([]*Fixture, 0, len(filePaths))
+	for _, filePath := range filePaths {
+		data, err := os.ReadFile(filePath)
+		if err != nil {
+			return nil, err
+		}
+
+		fixture := &Fixture{FilePath: filePath}
+		if err := json.Unmarshal(data, fixture); err != nil {
+			return nil, fmt.Errorf("invalid fixture [%s] - %w", filePath, err)
+		}
+		fixtures = append(fixtures, fixture)
+	}
+
+	return fixtures, nil
+}
+
+var fileNameReplacer = regexp.MustCompile("[^a-zA-Z0-9]+")
+
+func fileNamePart(path string) string {
+	part := strings.Trim(fileNameReplacer.ReplaceAllString(path, "-"), "-")
+	if part == "" {
+		return "root"
+	}
+	if len(part) > 50 {
+		return part[:50]
+	}
+	return part
+}
@@ -0,0 +1,144 @@
+package fixtures
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestRecordWithRedaction(t *testing.T) {
+	dir := t.TempDir()
+	recorder := NewRecorder(dir, Redaction{
+		Headers:      []string{"authorization"},
+		BodyPatterns: []*regexp.Regexp{regexp.MustCompile("\"token\": \"[^\"]*\""), regexp.MustCompile("0b79bab50d")},
+	})
+
+	request, _ := http.NewRequest(http.MethodPost, "http://localhost/api/login?user=bruce&token=0b79bab50d", nil)
+	request.Header.Set("Authorization", "Bearer secret")
+	response := &http.Response{StatusCode: http.StatusOK, Header: http.Header{"Content-Length": []string{"26"}}}
+
+	filePath, err := recorder.Record(request, []byte("{\"user\": \"bruce\"}"), response, []byte("{\"token\": \"0b79bab50d\"}"))
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if filepath.Base(filePath) != "0001-post-api-login.json" {
+		t.Errorf("Unexpected file name %s", filePath)
+	}
+
+	data, _ := os.ReadFile(filePath)
+	if strings.Contains(string(data), "secret") || strings.Contains(string(data), "0b79bab50d") {
+		t.Errorf("Fixture was not redacted: %s", data)
+	}
+
+	fixtures, err := Load(dir)
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if len(fixtures) != 1 {
+		t.Fatalf("One fixture expected, but got %d", len(fixtures))
+	}
+
+	fixture := fixtures[0]
+	if fixture.Request.Headers["Authorization"][0] != RedactedValue {
+		t.Errorf("Authorization header was not redacted")
+	}
+	if fixture.Response.Body != "{"+RedactedValue+"}" {
+		t.Errorf("Response body was not redacted: %s", fixture.Response.Body)
+	}
+	if fixture.Request.Query["token"][0] != RedactedValue || fixture.Request.Query["user"][0] != "bruce" {
+		t.Errorf("Query was not redacted: %s", fixture.Request.Query)
+	}
+	if _, exists := fixture.Response.Headers["Content-Length"]; exists {
+		t.Errorf("Content-Length header must not be recorded")
+	}
+	if fixture.Request.BodyHash != HashBody([]byte("{\"user\": \"bruce\"}")) {
+		t.Errorf("Body hash must be calculated from the original body")
+	}
+
+	// the redacted fixture is still matched by the original query
+	request, _ = http.NewRequest(http.MethodPost, "http://localhost/api/login?token=0b79bab50d&user=bruce", nil)
+	if matched, _ := NewReplayer(dir, []MatchRule{Query}).Find(request, nil); matched == nil {
+		t.Errorf("Fixture expected to match the original query")
+	}
+}
+
+func TestRecordBinaryBody(t *testing.T) {
+	dir := t.TempDir()
+	recorder := NewRecorder(dir, Redaction{})
+
+	// a gzip header, which is not valid UTF-8
+	binaryBody := []byte{0x1f, 0x8b, 0x08, 0x00, 0xff, 0xfe, 0x00, 0x80}
+	request, _ := http.NewRequest(http.MethodPost, "http://localhost/api/upload", nil)
+	response := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+
+	if _, err := recorder.Record(request, binaryBody, response, binaryBody); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	fixtures, err := Load(dir)
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	fixture := fixtures[0]
+	if fixture.Response.Encoding != Base64Encoding || fixture.Request.Encoding != Base64Encoding {
+		t.Errorf("Binary bodies must be base64 encoded")
+	}
+	requestBody, _ := fixture.Request.BodyBytes()
+	responseBody, _ := fixture.Response.BodyBytes()
+	if !bytes.Equal(requestBody, binaryBody) || !bytes.Equal(responseBody, binaryBody) {
+		t.Errorf("Binary bodies were corrupted: %v %v", requestBody, responseBody)
+	}
+	if fixture.Request.BodyHash != HashBody(requestBody) {
+		t.Errorf("Body hash must match the decoded body")
+	}
+}
+
+func TestReplaySequenceAndRules(t *testing.T) {
+	dir := t.TempDir()
+	recorder := NewRecorder(dir, Redaction{})
+
+	record(t, recorder, http.MethodGet, "/status?verbose=true", "", "first")
+	record(t, recorder, http.MethodGet, "/status?verbose=true", "", "second")
+	record(t, recorder, http.MethodPost, "/orders", "{\"id\": 1}", "created")
+
+	replayer := NewReplayer(dir, nil)
+	for _, expected := range []string{"first", "second", "second"} {
+		request, _ := http.NewRequest(http.MethodGet, "http://localhost/status", nil)
+		fixture, _ := replayer.Find(request, nil)
+
+		if fixture == nil || fixture.Response.Body != expected {
+			t.Errorf("Fixture with body [%s] expected, but got %+v", expected, fixture)
+		}
+	}
+
+	strictReplayer := NewReplayer(dir, []MatchRule{Method, Path, Query, BodyHash})
+
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost/status", nil)
+	if fixture, _ := strictReplayer.Find(request, nil); fixture != nil {
+		t.Errorf("No fixture expected because of the query, but got %+v", fixture)
+	}
+
+	request, _ = http.NewRequest(http.MethodPost, "http://localhost/orders", nil)
+	if fixture, _ := strictReplayer.Find(request, []byte("{\"id\": 2}")); fixture != nil {
+		t.Errorf("No fixture expected because of the body, but got %+v", fixture)
+	}
+	if fixture, _ := strictReplayer.Find(request, []byte("{\"id\": 1}")); fixture == nil {
+		t.Errorf("Fixture expected, but got none")
+	}
+}
+
+func record(t *testing.T, recorder *Recorder, method string, target string, requestBody string, responseBody string) {
+	request, _ := http.NewRequest(method, "http://localhost"+target, bytes.NewBufferString(requestBody))
+	body, _ := io.ReadAll(request.Body)
+	response := &http.Response{StatusCode: http.StatusOK, Header: http.Header{}}
+
+	if _, err := recorder.Record(request, body, response, []byte(responseBody)); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+}
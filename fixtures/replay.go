@@ -0,0 +1,111 @@
+package fixtures
+
+import (
+	"net/http"
+	"net/url"
+	"slices"
+	"sync"
+)
+
+type MatchRule int
+
+const (
+	Method MatchRule = iota
+	Path
+	Query
+	BodyHash
+)
+
+var DefaultMatchRules = []MatchRule{Method, Path}
+
+// Replayer finds the fixture that answers a request. The fixtures are read from the directory on every request,
+// so that the fixtures recorded earlier in the same run are replayed as well.
+// When several fixtures match, they are used one after the other, in the order of the files. After all of them
+// were used, the last one is repeated - this way a sequence of different responses to the same request can be replayed.
+type Replayer struct {
+	dir   string
+	rules []MatchRule
+	used  map[string]bool
+	lock  sync.Mutex
+}
+
+func NewReplayer(dir string, rules []MatchRule) *Replayer {
+	if len(rules) == 0 {
+		rules = DefaultMatchRules
+	}
+
+	return &Replayer{
+		dir:   dir,
+		rules: rules,
+		used:  make(map[string]bool),
+	}
+}
+
+// Find returns the fixture for the request or nil, if none matches
+func (replayer *Replayer) Find(request *http.Request, body []byte) (*Fixture, error) {
+	fixtures, err := Load(replayer.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	replayer.lock.Lock()
+	defer replayer.lock.Unlock()
+
+	var lastMatch *Fixture
+	for _, fixture := range fixtures {
+		if !replayer.matches(fixture, request, body) {
+			continue
+		}
+
+		if !replayer.used[fixture.FilePath] {
+			replayer.used[fixture.FilePath] = true
+			return fixture, nil
+		}
+		lastMatch = fixture
+	}
+
+	return lastMatch, nil
+}
+
+func (replayer *Replayer) matches(fixture *Fixture, request *http.Request, body []byte) bool {
+	for _, rule := range replayer.rules {
+		switch rule {
+		case Method:
+			if fixture.Request.Method != request.Method {
+				return false
+			}
+		case Path:
+			if fixture.Request.Path != request.URL.Path {
+				return false
+			}
+		case Query:
+			// fixtures recorded without a query hash are matched by their query
+			if fixture.Request.QueryHash != "" {
+				if fixture.Request.QueryHash != HashQuery(request.URL.Query()) {
+					return false
+				}
+			} else if !queryEquals(fixture.Request.Query, request.URL.Query()) {
+				return false
+			}
+		case BodyHash:
+			if fixture.Request.BodyHash != HashBody(body) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+func queryEquals(expected url.Values, actual url.Values) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+
+	for key, values := range expected {
+		if !slices.Equal(values, actual[key]) {
+			return false
+		}
+	}
+	return true
+}
@@ -1,10 +1,27 @@
 module github.com/go-clarum/clarum-http
 
-go 1.21
+go 1.24
 
 // until core is published
 require github.com/go-clarum/clarum-core v0.1.0
 
 require github.com/go-clarum/clarum-json v1.0.0
 
-require gopkg.in/yaml.v3 v3.0.1 // indirect
+require github.com/gorilla/websocket v1.5.3
+
+require github.com/getkin/kin-openapi v0.135.0
+
+require gopkg.in/yaml.v3 v3.0.1
+
+require (
+	github.com/go-openapi/jsonpointer v0.21.0 // indirect
+	github.com/go-openapi/swag v0.23.0 // indirect
+	github.com/gorilla/mux v1.8.0 // indirect
+	github.com/josharian/intern v1.0.0 // indirect
+	github.com/mailru/easyjson v0.7.7 // indirect
+	github.com/mohae/deepcopy v0.0.0-20170929034955-c48cc78d4826 // indirect
+	github.com/oasdiff/yaml v0.0.9 // indirect
+	github.com/oasdiff/yaml3 v0.0.9 // indirect
+	github.com/perimeterx/marshmallow v1.1.5 // indirect
+	github.com/woodsbury/decimal128 v1.3.0 // indirect
+)
@@ -0,0 +1,204 @@
+package har
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// Har is the root of a HAR 1.2 file, see http://www.softwareishard.com/blog/har-12-spec/
+type Har struct {
+	Log Log `json:"log"`
+}
+
+type Log struct {
+	Version string  `json:"version"`
+	Creator Creator `json:"creator"`
+	Entries []Entry `json:"entries"`
+}
+
+type Creator struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type Entry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	// Time is the total time of the exchange in milliseconds
+	Time     float64  `json:"time"`
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+	Cache    struct{} `json:"cache"`
+	Timings  Timings  `json:"timings"`
+	Comment  string   `json:"comment,omitempty"`
+}
+
+type Request struct {
+	Method      string      `json:"method"`
+	Url         string      `json:"url"`
+	HttpVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	QueryString []NameValue `json:"queryString"`
+	PostData    *PostData   `json:"postData,omitempty"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type Response struct {
+	Status      int         `json:"status"`
+	StatusText  string      `json:"statusText"`
+	HttpVersion string      `json:"httpVersion"`
+	Cookies     []Cookie    `json:"cookies"`
+	Headers     []NameValue `json:"headers"`
+	Content     Content     `json:"content"`
+	RedirectURL string      `json:"redirectURL"`
+	HeadersSize int         `json:"headersSize"`
+	BodySize    int         `json:"bodySize"`
+}
+
+type NameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type Cookie struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type PostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	// Encoding is not part of HAR 1.2 for requests, it is used like for the response content
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type Content struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text,omitempty"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+// Timings in milliseconds, -1 is used for phases that do not apply to the exchange
+type Timings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	Ssl     float64 `json:"ssl"`
+}
+
+// headers which describe the transfer of the recorded body and not the body itself
+var skippedHeaders = []string{"Content-Length", "Content-Encoding", "Transfer-Encoding", "Connection", "Host"}
+
+// Load reads a HAR file, as exported by browsers or by a Recorder
+func Load(filePath string) (*Har, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, err
+	}
+
+	har := &Har{}
+	if err := json.Unmarshal(data, har); err != nil {
+		return nil, err
+	}
+	return har, nil
+}
+
+// RequestMessage converts the request of the entry, to be sent by a client endpoint or validated by a server endpoint.
+// The base url of the message is the scheme & host of the recorded url.
+func (entry *Entry) RequestMessage() (*message.RequestMessage, error) {
+	requestUrl, err := url.Parse(entry.Request.Url)
+	if err != nil {
+		return nil, err
+	}
+
+	requestMessage := &message.RequestMessage{
+		Method: entry.Request.Method,
+		Url:    requestUrl.Scheme + "://" + requestUrl.Host,
+		Path:   requestUrl.Path,
+	}
+	for key, values := range requestUrl.Query() {
+		requestMessage.QueryParam(key, values...)
+	}
+	for header, value := range joinHeaders(entry.Request.Headers) {
+		requestMessage.Header(header, value)
+	}
+	if entry.Request.PostData != nil {
+		payload, err := decodeText(entry.Request.PostData.Text, entry.Request.PostData.Encoding)
+		if err != nil {
+			return nil, err
+		}
+		requestMessage.Payload(payload)
+	}
+
+	return requestMessage, nil
+}
+
+// ResponseMessage converts the response of the entry, to be sent by a server endpoint or validated by a client endpoint.
+func (entry *Entry) ResponseMessage() (*message.ResponseMessage, error) {
+	responseMessage := message.Response(entry.Response.Status)
+	for header, value := range joinHeaders(entry.Response.Headers) {
+		responseMessage.Header(header, value)
+	}
+
+	payload, err := decodeText(entry.Response.Content.Text, entry.Response.Content.Encoding)
+	if err != nil {
+		return nil, err
+	}
+	if payload != "" {
+		responseMessage.Payload(payload)
+	}
+
+	return responseMessage, nil
+}
+
+// joinHeaders skips HTTP/2 pseudo-headers & transfer related headers, multiple values are joined
+func joinHeaders(headers []NameValue) map[string]string {
+	result := make(map[string]string)
+
+	for _, header := range headers {
+		name := http.CanonicalHeaderKey(header.Name)
+		if strings.HasPrefix(header.Name, ":") || isSkipped(name) {
+			continue
+		}
+
+		if existing, exists := result[name]; exists {
+			result[name] = existing + ", " + header.Value
+		} else {
+			result[name] = header.Value
+		}
+	}
+
+	return result
+}
+
+func isSkipped(header string) bool {
+	for _, skipped := range skippedHeaders {
+		if skipped == header {
+			return true
+		}
+	}
+	return false
+}
+
+func decodeText(text string, encoding string) (string, error) {
+	if encoding != base64Encoding {
+		return text, nil
+	}
+
+	decoded, err := base64.StdEncoding.DecodeString(text)
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
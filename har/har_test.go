@@ -0,0 +1,129 @@
+package har
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSaveAndLoad(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/api/users?active=true", nil)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set("Content-Length", "17")
+
+	start := time.Now()
+	recorder := NewRecorder()
+	recorder.Add(Entry{
+		StartedDateTime: start.Add(time.Second),
+		Request:         NewRequest(request, "{\"name\": \"second\"}"),
+		Response:        NewResponse(http.StatusNoContent, "HTTP/1.1", http.Header{}, ""),
+	})
+	recorder.Add(Entry{
+		StartedDateTime: start,
+		Time:            Milliseconds(10 * time.Millisecond),
+		Request:         NewRequest(request, "{\"name\": \"bruce\"}"),
+		Response: NewResponse(http.StatusCreated, "HTTP/1.1",
+			http.Header{"Content-Type": []string{"application/json"}}, "{\"id\": 1}"),
+	})
+
+	filePath := filepath.Join(t.TempDir(), "exchanges.har")
+	if err := recorder.Save(filePath); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	loaded, err := Load(filePath)
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if loaded.Log.Version != "1.2" || len(loaded.Log.Entries) != 2 {
+		t.Fatalf("Unexpected HAR log %+v", loaded.Log)
+	}
+
+	entry := loaded.Log.Entries[0]
+	if entry.Time != 10 {
+		t.Errorf("Entries are not sorted by start time, first entry took %fms", entry.Time)
+	}
+
+	requestMessage, err := entry.RequestMessage()
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if requestMessage.Method != http.MethodPost || requestMessage.Url != "http://localhost:8080" ||
+		requestMessage.Path != "/api/users" || requestMessage.QueryParams["active"][0] != "true" {
+		t.Errorf("Unexpected request message %s", requestMessage.ToString())
+	}
+	if requestMessage.MessagePayload != "{\"name\": \"bruce\"}" {
+		t.Errorf("Unexpected request payload %s", requestMessage.MessagePayload)
+	}
+	if _, exists := requestMessage.Headers["Content-Length"]; exists {
+		t.Errorf("Content-Length header must not be converted")
+	}
+
+	responseMessage, err := entry.ResponseMessage()
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if responseMessage.StatusCode != http.StatusCreated || responseMessage.MessagePayload != "{\"id\": 1}" ||
+		responseMessage.Headers["Content-Type"] != "application/json" {
+		t.Errorf("Unexpected response message %s", responseMessage.ToString())
+	}
+}
+
+func TestBase64ResponseContent(t *testing.T) {
+	entry := Entry{
+		Request: Request{Method: http.MethodGet, Url: "https://example.com/logo"},
+		Response: Response{
+			Status: http.StatusOK,
+			Headers: []NameValue{
+				{Name: ":status", Value: "200"},
+				{Name: "x-trace", Value: "a"},
+				{Name: "x-trace", Value: "b"},
+			},
+			Content: Content{Text: "aGVsbG8=", Encoding: "base64"},
+		},
+	}
+
+	responseMessage, err := entry.ResponseMessage()
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if responseMessage.MessagePayload != "hello" {
+		t.Errorf("Base64 content was not decoded: %s", responseMessage.MessagePayload)
+	}
+	if len(responseMessage.Headers) != 1 || !strings.EqualFold(responseMessage.Headers["X-Trace"], "a, b") {
+		t.Errorf("Unexpected headers %s", responseMessage.Headers)
+	}
+}
+
+func TestBinaryBodiesRoundTrip(t *testing.T) {
+	// a gzip header, which is not valid UTF-8
+	binaryBody := string([]byte{0x1f, 0x8b, 0x08, 0x00, 0xff, 0xfe, 0x00, 0x80})
+	request, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/api/upload", nil)
+
+	entry := Entry{
+		Request:  NewRequest(request, binaryBody),
+		Response: NewResponse(http.StatusOK, "HTTP/1.1", http.Header{}, binaryBody),
+	}
+	if entry.Request.PostData.Encoding != "base64" || entry.Response.Content.Encoding != "base64" {
+		t.Errorf("Binary bodies must be base64 encoded")
+	}
+
+	recorder := NewRecorder()
+	recorder.Add(entry)
+	filePath := filepath.Join(t.TempDir(), "binary.har")
+	if err := recorder.Save(filePath); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	har, err := Load(filePath)
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	requestMessage, _ := har.Log.Entries[0].RequestMessage()
+	responseMessage, _ := har.Log.Entries[0].ResponseMessage()
+	if requestMessage.MessagePayload != binaryBody || responseMessage.MessagePayload != binaryBody {
+		t.Errorf("Binary bodies were corrupted")
+	}
+}
@@ -0,0 +1,172 @@
+package har
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+const modulePath = "github.com/go-clarum/clarum-http"
+
+// base64Encoding is used for bodies that are not valid UTF-8, which JSON cannot hold
+const base64Encoding = "base64"
+
+// Recorder collects the exchanges of all endpoints it was configured on. Call Save() at the end
+// of the test run, for example in TestMain, to export them into a HAR file.
+type Recorder struct {
+	entries []Entry
+	lock    sync.Mutex
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// Add an entry. Endpoints add their exchanges automatically.
+func (recorder *Recorder) Add(entry Entry) {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+
+	recorder.entries = append(recorder.entries, entry)
+}
+
+// Entries returns a copy of the recorded entries, ordered by their start time
+func (recorder *Recorder) Entries() []Entry {
+	recorder.lock.Lock()
+	defer recorder.lock.Unlock()
+
+	entries := append([]Entry(nil), recorder.entries...)
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].StartedDateTime.Before(entries[j].StartedDateTime)
+	})
+	return entries
+}
+
+// Save writes all recorded entries into a HAR 1.2 file
+func (recorder *Recorder) Save(filePath string) error {
+	har := &Har{
+		Log: Log{
+			Version: "1.2",
+			Creator: Creator{Name: "clarum-http", Version: moduleVersion()},
+			Entries: recorder.Entries(),
+		},
+	}
+
+	data, err := json.MarshalIndent(har, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(filePath), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filePath, data, 0o644)
+}
+
+// NewRequest converts a request as seen by an endpoint
+func NewRequest(request *http.Request, body string) Request {
+	harRequest := Request{
+		Method:      request.Method,
+		Url:         request.URL.String(),
+		HttpVersion: request.Proto,
+		Cookies:     []Cookie{},
+		Headers:     nameValues(request.Header),
+		QueryString: []NameValue{},
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+
+	// requests received by a server only have the path in the url
+	if request.URL.Host == "" {
+		requestUrl := *request.URL
+		requestUrl.Scheme = "http"
+		if request.TLS != nil {
+			requestUrl.Scheme = "https"
+		}
+		requestUrl.Host = request.Host
+		harRequest.Url = requestUrl.String()
+	}
+
+	for key, values := range request.URL.Query() {
+		for _, value := range values {
+			harRequest.QueryString = append(harRequest.QueryString, NameValue{Name: key, Value: value})
+		}
+	}
+	if body != "" {
+		text, encoding := encodeText(body)
+		harRequest.PostData = &PostData{MimeType: request.Header.Get("Content-Type"), Text: text, Encoding: encoding}
+	}
+
+	return harRequest
+}
+
+// NewResponse converts a response as sent or received by an endpoint
+func NewResponse(status int, proto string, headers http.Header, body string) Response {
+	text, encoding := encodeText(body)
+	return Response{
+		Status:      status,
+		StatusText:  http.StatusText(status),
+		HttpVersion: proto,
+		Cookies:     []Cookie{},
+		Headers:     nameValues(headers),
+		Content: Content{
+			Size:     len(body),
+			MimeType: headers.Get("Content-Type"),
+			Text:     text,
+			Encoding: encoding,
+		},
+		RedirectURL: headers.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(body),
+	}
+}
+
+// encodeText keeps text bodies readable & base64 encodes binary bodies (images, gzip, protobuf)
+func encodeText(body string) (string, string) {
+	if utf8.ValidString(body) {
+		return body, ""
+	}
+	return base64.StdEncoding.EncodeToString([]byte(body)), base64Encoding
+}
+
+// Milliseconds converts a duration into HAR time, -1 if the duration is zero (the phase did not happen)
+func Milliseconds(duration time.Duration) float64 {
+	if duration <= 0 {
+		return -1
+	}
+	return float64(duration) / float64(time.Millisecond)
+}
+
+func nameValues(headers http.Header) []NameValue {
+	result := make([]NameValue, 0, len(headers))
+	for name, values := range headers {
+		for _, value := range values {
+			result = append(result, NameValue{Name: name, Value: value})
+		}
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+func moduleVersion() string {
+	if buildInfo, ok := debug.ReadBuildInfo(); ok {
+		if buildInfo.Main.Path == modulePath {
+			return buildInfo.Main.Version
+		}
+		for _, dependency := range buildInfo.Deps {
+			if dependency.Path == modulePath {
+				return dependency.Version
+			}
+		}
+	}
+	return "(devel)"
+}
@@ -1,10 +1,22 @@
 package http
 
 import (
+	"github.com/go-clarum/clarum-http/admin"
 	"github.com/go-clarum/clarum-http/client"
+	"github.com/go-clarum/clarum-http/internal/lifecycle"
+	"github.com/go-clarum/clarum-http/properties"
+	"github.com/go-clarum/clarum-http/proxy"
+	"github.com/go-clarum/clarum-http/registry"
 	"github.com/go-clarum/clarum-http/server"
 )
 
+// endpoints contains all endpoints built through Http(). They are closed when the test run finishes.
+var endpoints = registry.New()
+
+func init() {
+	lifecycle.OnFinish(endpoints.Close)
+}
+
 type EndpointBuilder struct {
 }
 
@@ -13,9 +25,67 @@ func Http() *EndpointBuilder {
 }
 
 func (heb *EndpointBuilder) Client() *client.EndpointBuilder {
-	return client.NewEndpointBuilder()
+	return client.NewEndpointBuilder().Registry(endpoints)
 }
 
 func (heb *EndpointBuilder) Server() *server.EndpointBuilder {
-	return server.NewEndpointBuilder()
+	return server.NewEndpointBuilder().Registry(endpoints)
+}
+
+// Proxy builds a recording proxy endpoint, placed between the system under test and a real backend
+func (heb *EndpointBuilder) Proxy() *proxy.EndpointBuilder {
+	return proxy.NewEndpointBuilder().Registry(endpoints)
+}
+
+// Admin builds a REST API to manage running server endpoints: stubs, request journal & fault injection
+func (heb *EndpointBuilder) Admin() *admin.Builder {
+	return admin.NewBuilder().Registry(endpoints)
+}
+
+// ClientFromConfig returns a builder of the client defined under 'http.clients' in the config file.
+// It panics if the config file cannot be loaded or the client is not defined or invalid.
+func (heb *EndpointBuilder) ClientFromConfig(name string) *client.EndpointBuilder {
+	definitions, err := properties.Default()
+	if err != nil {
+		panic(err)
+	}
+	builder, err := definitions.Client(name)
+	if err != nil {
+		panic(err)
+	}
+	return builder.Registry(endpoints)
+}
+
+// ServerFromConfig returns a builder of the server defined under 'http.servers' in the config file.
+// It panics if the config file cannot be loaded or the server is not defined or invalid.
+func (heb *EndpointBuilder) ServerFromConfig(name string) *server.EndpointBuilder {
+	definitions, err := properties.Default()
+	if err != nil {
+		panic(err)
+	}
+	builder, err := definitions.Server(name)
+	if err != nil {
+		panic(err)
+	}
+	return builder.Registry(endpoints)
+}
+
+// ClientNamed returns the client endpoint built through Http() with the given name.
+// It panics if there is no such client.
+func (heb *EndpointBuilder) ClientNamed(name string) *client.Endpoint {
+	endpoint, err := endpoints.Lookup(registry.Client, name)
+	if err != nil {
+		panic(err)
+	}
+	return endpoint.(*client.Endpoint)
+}
+
+// ServerNamed returns the server endpoint built through Http() with the given name.
+// It panics if there is no such server.
+func (heb *EndpointBuilder) ServerNamed(name string) *server.Endpoint {
+	endpoint, err := endpoints.Lookup(registry.Server, name)
+	if err != nil {
+		panic(err)
+	}
+	return endpoint.(*server.Endpoint)
 }
@@ -0,0 +1,21 @@
+package internal
+
+import (
+	"context"
+	"github.com/go-clarum/clarum-core/config"
+	"github.com/go-clarum/clarum-core/durations"
+	"time"
+)
+
+// ActionTimeout is the timeout of an action, or the global action timeout if the action has none
+func ActionTimeout(timeout time.Duration) time.Duration {
+	return durations.GetDurationWithDefault(timeout, config.ActionTimeout())
+}
+
+// ActionContext is the context of an action, or the background context if the action has none
+func ActionContext(ctx context.Context) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+	return ctx
+}
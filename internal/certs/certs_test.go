@@ -0,0 +1,22 @@
+package certs
+
+import (
+	"crypto/x509"
+	"testing"
+)
+
+func TestSelfSigned(t *testing.T) {
+	certificate, err := SelfSigned()
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	parsed, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		t.Fatalf("Certificate could not be parsed - %s", err)
+	}
+
+	if err := parsed.VerifyHostname("localhost"); err != nil {
+		t.Errorf("Certificate is not valid for localhost - %s", err)
+	}
+}
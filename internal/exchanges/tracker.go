@@ -0,0 +1,117 @@
+package exchanges
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Tracker keeps track of the exchanges of an endpoint that wait for a test action, and of those that timed out
+// waiting. They are reported at the end of the test that started them, or when the test run finishes, since they
+// point to a test missing an action.
+type Tracker struct {
+	pending  map[*Exchange]struct{}
+	timedOut []*Exchange
+	watched  map[Test]struct{}
+	lock     sync.Mutex
+}
+
+// Exchange is a request or response waiting for a test action
+type Exchange struct {
+	description string
+	waitingFor  string
+	// test is the name of the test that started the exchange, if known
+	test      string
+	startedAt time.Time
+	// a reported exchange was already attributed to a test
+	reported bool
+}
+
+func NewTracker() *Tracker {
+	return &Tracker{
+		pending: make(map[*Exchange]struct{}),
+		watched: make(map[Test]struct{}),
+	}
+}
+
+// Start tracks an exchange, described like 'request [GET /users]', that waits for a test action. If the test that
+// started the exchange is not known, the exchange is attributed to the tests that were running when it started.
+func (tracker *Tracker) Start(description string, waitingFor string, test string) *Exchange {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	exchange := &Exchange{description: description, waitingFor: waitingFor, test: test, startedAt: time.Now()}
+	tracker.pending[exchange] = struct{}{}
+	return exchange
+}
+
+// Await makes the exchange wait for the next test action
+func (tracker *Tracker) Await(exchange *Exchange, waitingFor string) {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	exchange.waitingFor = waitingFor
+}
+
+// Done stops tracking an exchange that was handled by the test
+func (tracker *Tracker) Done(exchange *Exchange) {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	delete(tracker.pending, exchange)
+}
+
+// TimedOut stops tracking an exchange whose test action was never called & remembers it for the report
+func (tracker *Tracker) TimedOut(exchange *Exchange) {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	if _, exists := tracker.pending[exchange]; !exists {
+		return
+	}
+	delete(tracker.pending, exchange)
+	if !exchange.reported {
+		tracker.timedOut = append(tracker.timedOut, exchange)
+	}
+}
+
+// Unhandled describes the exchanges that still wait for a test action & those that timed out waiting.
+// Every exchange is only reported once.
+func (tracker *Tracker) Unhandled() []string {
+	return tracker.unhandledBy("", time.Time{})
+}
+
+// unhandledBy reports the unhandled exchanges of the test: those started by it, and those of unknown tests
+// that were started at or after the start of the test
+func (tracker *Tracker) unhandledBy(test string, start time.Time) []string {
+	tracker.lock.Lock()
+	defer tracker.lock.Unlock()
+
+	belongsToTest := func(exchange *Exchange) bool {
+		if exchange.test != "" && test != "" {
+			return exchange.test == test
+		}
+		return !exchange.startedAt.Before(start)
+	}
+
+	var unhandled []string
+	var remaining []*Exchange
+	for _, exchange := range tracker.timedOut {
+		if !belongsToTest(exchange) {
+			remaining = append(remaining, exchange)
+			continue
+		}
+		exchange.reported = true
+		unhandled = append(unhandled, fmt.Sprintf("%s timed out waiting for %s", exchange.description, exchange.waitingFor))
+	}
+	tracker.timedOut = remaining
+
+	for exchange := range tracker.pending {
+		if exchange.reported || !belongsToTest(exchange) {
+			continue
+		}
+		exchange.reported = true
+		unhandled = append(unhandled, fmt.Sprintf("%s is waiting for %s", exchange.description, exchange.waitingFor))
+	}
+	return unhandled
+}
@@ -0,0 +1,92 @@
+package exchanges
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+
+	handled := tracker.Start("request [GET /users]", "a server receive action", "")
+	tracker.Await(handled, "a server send action")
+	tracker.Done(handled)
+
+	timedOut := tracker.Start("request [GET /orders]", "a server receive action", "")
+	tracker.Await(timedOut, "a server send action")
+	tracker.TimedOut(timedOut)
+
+	tracker.Start("request [POST /orders]", "a server receive action", "")
+
+	unhandled := tracker.Unhandled()
+	if len(unhandled) != 2 ||
+		unhandled[0] != "request [GET /orders] timed out waiting for a server send action" ||
+		unhandled[1] != "request [POST /orders] is waiting for a server receive action" {
+		t.Errorf("Unexpected unhandled exchanges %s", unhandled)
+	}
+
+	if unhandled = tracker.Unhandled(); len(unhandled) != 0 {
+		t.Errorf("Exchanges must only be reported once, but got %s", unhandled)
+	}
+}
+
+func TestUnhandledSince(t *testing.T) {
+	tracker := NewTracker()
+
+	tracker.Start("request [GET /users]", "a server receive action", "")
+	start := time.Now()
+	duringTest := tracker.Start("response to [GET /orders]", "a client receive action", "")
+
+	unhandled := tracker.unhandledBy("TestOrders", start)
+	if len(unhandled) != 1 || unhandled[0] != "response to [GET /orders] is waiting for a client receive action" {
+		t.Errorf("Unexpected unhandled exchanges %s", unhandled)
+	}
+
+	// an exchange attributed to a test is not reported again when it times out
+	tracker.TimedOut(duringTest)
+	unhandled = tracker.Unhandled()
+	if len(unhandled) != 1 || unhandled[0] != "request [GET /users] is waiting for a server receive action" {
+		t.Errorf("Unexpected unhandled exchanges %s", unhandled)
+	}
+}
+
+func TestUnhandledByTest(t *testing.T) {
+	tracker := NewTracker()
+
+	start := time.Now()
+	tracker.Start("request [GET /users]", "a server receive action", "TestUsers")
+	tracker.Start("request [GET /orders]", "a server receive action", "TestOrders")
+	tracker.Start("request [GET /health]", "a server receive action", "")
+
+	unhandled := tracker.unhandledBy("TestOrders", start)
+	if len(unhandled) != 2 {
+		t.Errorf("Expected the exchanges of the test & of no test, but got %s", unhandled)
+	}
+	for _, exchange := range unhandled {
+		if exchange == "request [GET /users] is waiting for a server receive action" {
+			t.Errorf("Exchange of another test was attributed to the test")
+		}
+	}
+}
+
+func TestRunning(t *testing.T) {
+	tracker := NewTracker()
+
+	t.Run("TestUsers", func(t *testing.T) {
+		tracker.Watch(t, "", func() {
+			if Running(t.Name()) {
+				t.Errorf("Test is still running when it ends")
+			}
+		})
+		if !Running(t.Name()) {
+			t.Errorf("Test is not running")
+		}
+	})
+
+	if Running(t.Name() + "/TestUsers") {
+		t.Errorf("Ended test is still running")
+	}
+	if Running("TestUnknown") {
+		t.Errorf("Unknown test is running")
+	}
+}
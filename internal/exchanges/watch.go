@@ -0,0 +1,86 @@
+package exchanges
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// the start of a test is the first time any endpoint was used in it, so that exchanges started by one endpoint
+// are attributed to the test, even if the other endpoint of the exchange is only used later in the test.
+// A test is removed as soon as it starts ending, so that it is no longer running for the hooks of its end.
+var (
+	testStarts     = make(map[Test]time.Time)
+	testStartsLock sync.Mutex
+)
+
+// Test is the part of *testing.T used to check a test for unhandled exchanges
+type Test interface {
+	Name() string
+	Cleanup(func())
+	Errorf(format string, args ...any)
+}
+
+// Watch fails the test when it ends, if exchanges of the tracker that were started during the test still wait
+// for a test action or timed out waiting. Only the first call for a test installs the check. onEnd, if not nil,
+// is called when the test ends, before the check.
+func (tracker *Tracker) Watch(t Test, logPrefix string, onEnd func()) {
+	start := testStart(t)
+
+	tracker.lock.Lock()
+	_, watched := tracker.watched[t]
+	tracker.watched[t] = struct{}{}
+	tracker.lock.Unlock()
+	if watched {
+		return
+	}
+
+	t.Cleanup(func() {
+		tracker.lock.Lock()
+		delete(tracker.watched, t)
+		tracker.lock.Unlock()
+
+		testEnded(t)
+		if onEnd != nil {
+			onEnd()
+		}
+
+		if unhandled := tracker.unhandledBy(t.Name(), start); len(unhandled) > 0 {
+			t.Errorf("%sunhandled exchanges at the end of the test:\n%s", logPrefix, strings.Join(unhandled, "\n"))
+		}
+	})
+}
+
+func testStart(t Test) time.Time {
+	testStartsLock.Lock()
+	defer testStartsLock.Unlock()
+
+	if start, exists := testStarts[t]; exists {
+		return start
+	}
+
+	start := time.Now()
+	testStarts[t] = start
+	t.Cleanup(func() { testEnded(t) })
+	return start
+}
+
+func testEnded(t Test) {
+	testStartsLock.Lock()
+	defer testStartsLock.Unlock()
+
+	delete(testStarts, t)
+}
+
+// Running reports whether a test with the given name used an endpoint and has not ended yet
+func Running(test string) bool {
+	testStartsLock.Lock()
+	defer testStartsLock.Unlock()
+
+	for t := range testStarts {
+		if t.Name() == test {
+			return true
+		}
+	}
+	return false
+}
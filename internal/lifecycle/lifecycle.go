@@ -0,0 +1,54 @@
+package lifecycle
+
+import (
+	"errors"
+	"sync"
+)
+
+type finishHook struct {
+	call func() error
+}
+
+var (
+	finishHooks []*finishHook
+	hooksLock   sync.Mutex
+)
+
+// OnFinish registers a hook that is called when the test run finishes, after all actions have finished.
+// The returned function removes the hook, for endpoints that are finished before the test run.
+func OnFinish(hook func() error) func() {
+	hooksLock.Lock()
+	defer hooksLock.Unlock()
+
+	registered := &finishHook{call: hook}
+	finishHooks = append(finishHooks, registered)
+
+	return func() {
+		hooksLock.Lock()
+		defer hooksLock.Unlock()
+
+		for i, existing := range finishHooks {
+			if existing == registered {
+				finishHooks = append(finishHooks[:i:i], finishHooks[i+1:]...)
+				return
+			}
+		}
+	}
+}
+
+// Finish calls all registered hooks in the order they were registered and returns their errors. Each hook is
+// called only once, even if Finish is called several times. A failing hook does not prevent the others from running.
+func Finish() error {
+	hooksLock.Lock()
+	hooks := finishHooks
+	finishHooks = nil
+	hooksLock.Unlock()
+
+	var errs []error
+	for _, hook := range hooks {
+		if err := hook.call(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,52 @@
+package lifecycle
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestFinishHooks(t *testing.T) {
+	var calls []string
+	OnFinish(func() error {
+		calls = append(calls, "first")
+		return errors.New("first failed")
+	})
+	OnFinish(func() error {
+		calls = append(calls, "second")
+		return nil
+	})
+
+	err := Finish()
+	if err == nil || err.Error() != "first failed" {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if err := Finish(); err != nil {
+		t.Errorf("No error expected on second finish, but got %s", err)
+	}
+
+	if len(calls) != 2 || calls[0] != "first" || calls[1] != "second" {
+		t.Errorf("Hooks were not called once in order: %s", calls)
+	}
+}
+
+func TestRemovedFinishHook(t *testing.T) {
+	var calls []string
+	OnFinish(func() error {
+		calls = append(calls, "kept")
+		return nil
+	})
+	remove := OnFinish(func() error {
+		calls = append(calls, "removed")
+		return nil
+	})
+	remove()
+	// removing twice has no effect
+	remove()
+
+	if err := Finish(); err != nil {
+		t.Errorf("No error expected, but got %s", err)
+	}
+	if len(calls) != 1 || calls[0] != "kept" {
+		t.Errorf("Removed hook was called: %s", calls)
+	}
+}
@@ -0,0 +1,93 @@
+package sse
+
+import (
+	"bufio"
+	"github.com/go-clarum/clarum-http/message"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	ContentType           = "text/event-stream"
+	LastEventIdHeaderName = "Last-Event-ID"
+)
+
+// Encode serializes an event according to the text/event-stream format.
+// Multi-line data is sent as multiple 'data' fields.
+func Encode(event *message.EventMessage) string {
+	var builder strings.Builder
+
+	if event.EventId != "" {
+		builder.WriteString("id: " + event.EventId + "\n")
+	}
+	if event.EventName != "" {
+		builder.WriteString("event: " + event.EventName + "\n")
+	}
+	if event.EventRetry > 0 {
+		builder.WriteString("retry: " + strconv.FormatInt(event.EventRetry.Milliseconds(), 10) + "\n")
+	}
+	for _, line := range strings.Split(event.EventData, "\n") {
+		builder.WriteString("data: " + line + "\n")
+	}
+	builder.WriteString("\n")
+
+	return builder.String()
+}
+
+// Reader parses events from a text/event-stream body
+type Reader struct {
+	reader *bufio.Reader
+}
+
+func NewReader(body io.Reader) *Reader {
+	return &Reader{reader: bufio.NewReader(body)}
+}
+
+// Next blocks until a complete event was read. Comments and empty events are skipped.
+// When the stream ends, io.EOF is returned.
+func (reader *Reader) Next() (*message.EventMessage, error) {
+	event := message.Event()
+	var dataLines []string
+	hasFields := false
+
+	for {
+		line, err := reader.reader.ReadString('\n')
+		if err != nil {
+			if err == io.EOF && line != "" {
+				err = io.ErrUnexpectedEOF
+			}
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			if hasFields {
+				event.EventData = strings.Join(dataLines, "\n")
+				return event, nil
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		hasFields = true
+
+		switch field {
+		case "id":
+			event.EventId = value
+		case "event":
+			event.EventName = value
+		case "data":
+			dataLines = append(dataLines, value)
+		case "retry":
+			if millis, err := strconv.Atoi(value); err == nil {
+				event.EventRetry = time.Duration(millis) * time.Millisecond
+			}
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package sse
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"io"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	event := message.Event().
+		Id("1").
+		Name("update").
+		Retry(2 * time.Second).
+		Data("first\nsecond")
+
+	expected := "id: 1\nevent: update\nretry: 2000\ndata: first\ndata: second\n\n"
+	if actual := Encode(event); actual != expected {
+		t.Errorf("unexpected encoding [%s]", actual)
+	}
+}
+
+func TestReader(t *testing.T) {
+	stream := ": comment\n\n" +
+		"id: 1\r\nevent: update\r\ndata: first\r\ndata: second\r\n\r\n" +
+		"data:no space\nretry: 500\n\n"
+	reader := NewReader(strings.NewReader(stream))
+
+	first, err := reader.Next()
+	if err != nil {
+		t.Fatalf("error is unexpected - %s", err)
+	}
+	if !first.Equals(message.Event().Id("1").Name("update").Data("first\nsecond")) {
+		t.Errorf("unexpected event %s", first.ToString())
+	}
+
+	second, err := reader.Next()
+	if err != nil {
+		t.Fatalf("error is unexpected - %s", err)
+	}
+	if !second.Equals(message.Event().Data("no space").Retry(500 * time.Millisecond)) {
+		t.Errorf("unexpected event %s", second.ToString())
+	}
+
+	if _, err := reader.Next(); err != io.EOF {
+		t.Errorf("expected EOF but got %v", err)
+	}
+}
+
+func TestEncodeAndRead(t *testing.T) {
+	event := message.Event().Id("7").Data("{\"name\": \"Bruce Wayne\"}")
+
+	actual, err := NewReader(strings.NewReader(Encode(event))).Next()
+	if err != nil {
+		t.Fatalf("error is unexpected - %s", err)
+	}
+	if !actual.Equals(event) {
+		t.Errorf("unexpected event %s", actual.ToString())
+	}
+}
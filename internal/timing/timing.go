@@ -0,0 +1,83 @@
+package timing
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// Timings of a single HTTP exchange as seen by a client endpoint.
+// Phases that did not happen (for example the DNS lookup on a reused connection) are zero.
+type Timings struct {
+	Start     time.Time
+	DNS       time.Duration
+	Connect   time.Duration
+	TLS       time.Duration
+	FirstByte time.Duration
+	Total     time.Duration
+
+	lock         sync.Mutex
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+}
+
+func NewTimings() *Timings {
+	return &Timings{Start: time.Now()}
+}
+
+// ClientTrace returns an httptrace.ClientTrace that records the phases of the exchange.
+// The callbacks may be called from different goroutines, so all access is synchronized.
+func (timings *Timings) ClientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			timings.record(func() { timings.dnsStart = time.Now() })
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			timings.record(func() { timings.DNS = time.Since(timings.dnsStart) })
+		},
+		ConnectStart: func(string, string) {
+			timings.record(func() { timings.connectStart = time.Now() })
+		},
+		ConnectDone: func(string, string, error) {
+			timings.record(func() { timings.Connect = time.Since(timings.connectStart) })
+		},
+		TLSHandshakeStart: func() {
+			timings.record(func() { timings.tlsStart = time.Now() })
+		},
+		TLSHandshakeDone: func(tls.ConnectionState, error) {
+			timings.record(func() { timings.TLS = time.Since(timings.tlsStart) })
+		},
+		GotFirstResponseByte: func() {
+			timings.record(func() { timings.FirstByte = time.Since(timings.Start) })
+		},
+	}
+}
+
+// Finish marks the end of the exchange, after the response body has been read completely.
+func (timings *Timings) Finish() {
+	timings.record(func() { timings.Total = time.Since(timings.Start) })
+}
+
+func (timings *Timings) String() string {
+	timings.lock.Lock()
+	defer timings.lock.Unlock()
+
+	return fmt.Sprintf("["+
+		"dns: %s, "+
+		"connect: %s, "+
+		"tls: %s, "+
+		"first byte: %s, "+
+		"total: %s"+
+		"]",
+		timings.DNS, timings.Connect, timings.TLS, timings.FirstByte, timings.Total)
+}
+
+func (timings *Timings) record(update func()) {
+	timings.lock.Lock()
+	defer timings.lock.Unlock()
+
+	update()
+}
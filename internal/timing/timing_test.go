@@ -0,0 +1,39 @@
+package timing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"testing"
+)
+
+func TestClientTrace(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		writer.WriteHeader(http.StatusOK)
+	}))
+	defer testServer.Close()
+
+	timings := NewTimings()
+	request, _ := http.NewRequest(http.MethodGet, testServer.URL, nil)
+	request = request.WithContext(httptrace.WithClientTrace(request.Context(), timings.ClientTrace()))
+
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("error is unexpected - %s", err)
+	}
+	_ = response.Body.Close()
+	timings.Finish()
+
+	if timings.Connect <= 0 {
+		t.Errorf("connect time must be recorded")
+	}
+	if timings.FirstByte <= 0 {
+		t.Errorf("time to first byte must be recorded")
+	}
+	if timings.Total < timings.FirstByte {
+		t.Errorf("total time must include the time to first byte")
+	}
+	if timings.TLS != 0 {
+		t.Errorf("no TLS handshake expected")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	clarumstrings "github.com/go-clarum/clarum-core/validators/strings"
 	"github.com/go-clarum/clarum-http/internal"
 	"github.com/go-clarum/clarum-http/message"
+	"github.com/go-clarum/clarum-http/openapi"
 	"github.com/go-clarum/clarum-json/comparator"
 	"github.com/go-clarum/clarum-json/recorder"
 	"io"
@@ -116,6 +117,49 @@ func ValidateHttpStatusCode(expectedMessage *message.ResponseMessage, actualStat
 	return nil
 }
 
+// ValidateHttpProto validates the protocol version, for example 'HTTP/2.0'. An empty expected protocol is not validated.
+func ValidateHttpProto(expectedProto string, actualProto string, logger *logging.Logger) error {
+	if expectedProto == "" {
+		return nil
+	}
+
+	if expectedProto != actualProto {
+		return handleError(logger, "validation error - protocol mismatch - expected [%s] but received [%s]",
+			expectedProto, actualProto)
+	}
+
+	logger.Info("protocol validation successful")
+	return nil
+}
+
+// ValidateOpenApiRequest validates the request against the matching operation of the spec. No spec means no validation.
+func ValidateOpenApiRequest(spec *openapi.Spec, request *http.Request, logger *logging.Logger) error {
+	if spec == nil {
+		return nil
+	}
+
+	if err := spec.ValidateRequest(request); err != nil {
+		return handleError(logger, "validation error - OpenAPI contract violation - %s", err)
+	}
+
+	logger.Info("OpenAPI request validation successful")
+	return nil
+}
+
+// ValidateOpenApiResponse validates the response against the operation of its request. No spec means no validation.
+func ValidateOpenApiResponse(spec *openapi.Spec, response *http.Response, logger *logging.Logger) error {
+	if spec == nil {
+		return nil
+	}
+
+	if err := spec.ValidateResponse(response); err != nil {
+		return handleError(logger, "validation error - OpenAPI contract violation - %s", err)
+	}
+
+	logger.Info("OpenAPI response validation successful")
+	return nil
+}
+
 func ValidateHttpPayload(expectedMessage *message.Message, actualPayload io.ReadCloser,
 	payloadType internal.PayloadType, logger *logging.Logger) error {
 	defer closeBody(logger, actualPayload)
@@ -139,6 +183,91 @@ func ValidateHttpPayload(expectedMessage *message.Message, actualPayload io.Read
 	return nil
 }
 
+// ValidateEvent validates only the fields that are set on the expected event
+func ValidateEvent(expectedEvent *message.EventMessage, actualEvent *message.EventMessage,
+	payloadType internal.PayloadType, logger *logging.Logger) error {
+	if expectedEvent.EventId != "" && expectedEvent.EventId != actualEvent.EventId {
+		return handleError(logger, "validation error - event id mismatch - expected [%s] but received [%s]",
+			expectedEvent.EventId, actualEvent.EventId)
+	}
+	if expectedEvent.EventName != "" && expectedEvent.EventName != actualEvent.EventName {
+		return handleError(logger, "validation error - event name mismatch - expected [%s] but received [%s]",
+			expectedEvent.EventName, actualEvent.EventName)
+	}
+	if expectedEvent.EventRetry > 0 && expectedEvent.EventRetry != actualEvent.EventRetry {
+		return handleError(logger, "validation error - event retry mismatch - expected [%s] but received [%s]",
+			expectedEvent.EventRetry, actualEvent.EventRetry)
+	}
+
+	if clarumstrings.IsNotBlank(expectedEvent.EventData) {
+		expectedData := &message.Message{MessagePayload: expectedEvent.EventData}
+		if err := validatePayload(expectedData, []byte(actualEvent.EventData), payloadType, logger); err != nil {
+			return handleError(logger, "%s", err)
+		}
+	}
+
+	logger.Info("event validation successful")
+	return nil
+}
+
+func ValidateStreamPart(expectedPart string, actualPart []byte, payloadType internal.PayloadType, logger *logging.Logger) error {
+	expectedMessage := &message.Message{MessagePayload: expectedPart}
+	if err := validatePayload(expectedMessage, actualPart, payloadType, logger); err != nil {
+		return handleError(logger, "%s", err)
+	}
+
+	logger.Info("stream part validation successful")
+	return nil
+}
+
+// ValidateHttpTrailer validates a single trailer. Just like headers, trailers are compared in a case-insensitive way.
+func ValidateHttpTrailer(expectedTrailer string, expectedValue string, actualTrailers http.Header, logger *logging.Logger) error {
+	receivedValues := actualTrailers.Values(expectedTrailer)
+
+	if len(receivedValues) == 0 {
+		return handleError(logger, "validation error - trailer <%s> missing", strings.ToLower(expectedTrailer))
+	} else if !arrays.Contains(receivedValues, expectedValue) {
+		return handleError(logger, "validation error - trailer <%s> mismatch - expected [%s] but received [%s]",
+			strings.ToLower(expectedTrailer), expectedValue, receivedValues)
+	}
+
+	logger.Info("trailer validation successful")
+	return nil
+}
+
+// ValidateFrame validates the frame type and, depending on it, the payload or the close code & reason.
+// Binary payloads are always compared as they are.
+func ValidateFrame(expectedFrame *message.FrameMessage, actualFrame *message.FrameMessage,
+	payloadType internal.PayloadType, logger *logging.Logger) error {
+	if expectedFrame.FrameType != actualFrame.FrameType {
+		return handleError(logger, "validation error - frame type mismatch - expected [%s] but received [%s]",
+			expectedFrame.FrameType, actualFrame.FrameType)
+	}
+
+	if expectedFrame.FrameType == message.CloseFrameType {
+		if expectedFrame.CloseCode != actualFrame.CloseCode {
+			return handleError(logger, "validation error - close code mismatch - expected [%d] but received [%d]",
+				expectedFrame.CloseCode, actualFrame.CloseCode)
+		}
+		if expectedFrame.CloseReason != "" && expectedFrame.CloseReason != actualFrame.CloseReason {
+			return handleError(logger, "validation error - close reason mismatch - expected [%s] but received [%s]",
+				expectedFrame.CloseReason, actualFrame.CloseReason)
+		}
+	} else if expectedFrame.FramePayload != "" {
+		if expectedFrame.FrameType != message.TextFrameType {
+			payloadType = internal.Plaintext
+		}
+
+		expectedPayload := &message.Message{MessagePayload: expectedFrame.FramePayload}
+		if err := validatePayload(expectedPayload, []byte(actualFrame.FramePayload), payloadType, logger); err != nil {
+			return handleError(logger, "%s", err)
+		}
+	}
+
+	logger.Info("frame validation successful")
+	return nil
+}
+
 func closeBody(logger *logging.Logger, body io.ReadCloser) {
 	if err := body.Close(); err != nil {
 		logger.Errorf("unable to close body - %s", err)
@@ -176,7 +305,7 @@ func validatePayload(message *message.Message, actual []byte, payloadType intern
 
 func handleError(logger *logging.Logger, format string, a ...any) error {
 	errorMessage := fmt.Sprintf(format, a...)
-	logger.Errorf(errorMessage)
+	logger.Error(errorMessage)
 	return errors.New(errorMessage)
 }
 
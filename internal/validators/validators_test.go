@@ -4,6 +4,7 @@ import (
 	"github.com/go-clarum/clarum-core/config"
 	"github.com/go-clarum/clarum-core/logging"
 	"github.com/go-clarum/clarum-http/constants"
+	"github.com/go-clarum/clarum-http/internal"
 	"github.com/go-clarum/clarum-http/message"
 	"net/http"
 	"testing"
@@ -200,6 +201,104 @@ func TestValidateQueryParamsMultiValueMismatch(t *testing.T) {
 	}
 }
 
+func TestValidateProtoMismatch(t *testing.T) {
+	err := ValidateHttpProto("HTTP/2.0", "HTTP/1.1", logger)
+
+	if err == nil {
+		t.Errorf("Protocol validation error expected, but got none")
+	}
+
+	if err.Error() != "validation error - protocol mismatch - expected [HTTP/2.0] but received [HTTP/1.1]" {
+		t.Errorf("Protocol validation error message is unexpected")
+	}
+}
+
+func TestValidateEventOK(t *testing.T) {
+	expectedEvent := message.Event().Name("update").Data("{\"id\": \"@ignore@\"}")
+	actualEvent := message.Event().Id("1").Name("update").Data("{\"id\": 1}")
+
+	if err := ValidateEvent(expectedEvent, actualEvent, internal.Json, logger); err != nil {
+		t.Errorf("No event validation error expected, but got %s", err)
+	}
+}
+
+func TestValidateEventIdMismatch(t *testing.T) {
+	err := ValidateEvent(message.Event().Id("1"), message.Event().Id("2"), internal.Plaintext, logger)
+
+	if err == nil {
+		t.Errorf("Event validation error expected, but got none")
+	}
+
+	if err.Error() != "validation error - event id mismatch - expected [1] but received [2]" {
+		t.Errorf("Event validation error message is unexpected")
+	}
+}
+
+func TestValidateStreamPartMismatch(t *testing.T) {
+	err := ValidateStreamPart("first", []byte("second"), internal.Plaintext, logger)
+
+	if err == nil {
+		t.Errorf("Stream part validation error expected, but got none")
+	}
+
+	if err.Error() != "validation error - payload mismatch - expected [first] but received [second]" {
+		t.Errorf("Stream part validation error message is unexpected")
+	}
+}
+
+func TestValidateTrailerOK(t *testing.T) {
+	trailers := http.Header{"X-Checksum": []string{"1234"}}
+
+	if err := ValidateHttpTrailer("x-checksum", "1234", trailers, logger); err != nil {
+		t.Errorf("No trailer validation error expected, but got %s", err)
+	}
+}
+
+func TestValidateTrailerMissing(t *testing.T) {
+	err := ValidateHttpTrailer("X-Checksum", "1234", http.Header{}, logger)
+
+	if err == nil {
+		t.Errorf("Trailer validation error expected, but got none")
+	}
+
+	if err.Error() != "validation error - trailer <x-checksum> missing" {
+		t.Errorf("Trailer validation error message is unexpected")
+	}
+}
+
+func TestValidateFrameJsonOK(t *testing.T) {
+	expectedFrame := message.TextFrame("{\"id\": \"@ignore@\"}")
+	actualFrame := message.TextFrame("{\"id\": 1}")
+
+	if err := ValidateFrame(expectedFrame, actualFrame, internal.Json, logger); err != nil {
+		t.Errorf("No frame validation error expected, but got %s", err)
+	}
+}
+
+func TestValidateFrameTypeMismatch(t *testing.T) {
+	err := ValidateFrame(message.TextFrame("hello"), message.BinaryFrame([]byte("hello")), internal.Plaintext, logger)
+
+	if err == nil {
+		t.Errorf("Frame validation error expected, but got none")
+	}
+
+	if err.Error() != "validation error - frame type mismatch - expected [text] but received [binary]" {
+		t.Errorf("Frame validation error message is unexpected")
+	}
+}
+
+func TestValidateFrameCloseCodeMismatch(t *testing.T) {
+	err := ValidateFrame(message.CloseFrame(1000, ""), message.CloseFrame(1011, "internal error"), internal.Plaintext, logger)
+
+	if err == nil {
+		t.Errorf("Frame validation error expected, but got none")
+	}
+
+	if err.Error() != "validation error - close code mismatch - expected [1000] but received [1011]" {
+		t.Errorf("Frame validation error message is unexpected")
+	}
+}
+
 func createTestMessageWithHeaders() *message.RequestMessage {
 	return message.Post("myPath", "", "some", "/", "api").
 		Header("Connection", "keep-alive").
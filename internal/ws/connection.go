@@ -0,0 +1,153 @@
+package ws
+
+import (
+	"context"
+	"errors"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/internal"
+	"github.com/go-clarum/clarum-http/message"
+	"github.com/gorilla/websocket"
+	"sync"
+	"time"
+)
+
+const (
+	SubprotocolHeaderName = "Sec-WebSocket-Protocol"
+	controlWriteTimeout   = time.Second
+	receivedFramesBuffer  = 16
+)
+
+// Connection wraps an established WebSocket connection. All received frames, including control frames,
+// are published in the order they arrive, so that the test can validate them one by one.
+// Pings are answered automatically with a pong & a received close frame is echoed back, as RFC 6455 requires.
+type Connection struct {
+	conn      *websocket.Conn
+	frames    chan *frameResult
+	closed    chan struct{}
+	closeOnce sync.Once
+	writeLock sync.Mutex
+	logger    *logging.Logger
+}
+
+type frameResult struct {
+	frame *message.FrameMessage
+	error error
+}
+
+func NewConnection(conn *websocket.Conn, logger *logging.Logger) *Connection {
+	connection := &Connection{
+		conn:   conn,
+		frames: make(chan *frameResult, receivedFramesBuffer),
+		closed: make(chan struct{}),
+		logger: logger,
+	}
+
+	conn.SetPingHandler(func(data string) error {
+		connection.publish(&frameResult{frame: message.PingFrame(data)})
+
+		err := conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(controlWriteTimeout))
+		if errors.Is(err, websocket.ErrCloseSent) {
+			return nil
+		}
+		return err
+	})
+	conn.SetPongHandler(func(data string) error {
+		connection.publish(&frameResult{frame: message.PongFrame(data)})
+		return nil
+	})
+
+	go connection.read()
+
+	return connection
+}
+
+func (connection *Connection) Subprotocol() string {
+	return connection.conn.Subprotocol()
+}
+
+func (connection *Connection) read() {
+	defer close(connection.frames)
+	defer connection.Close()
+
+	for {
+		messageType, data, err := connection.conn.ReadMessage()
+
+		var closeError *websocket.CloseError
+		if errors.As(err, &closeError) {
+			connection.publish(&frameResult{frame: message.CloseFrame(closeError.Code, closeError.Text)})
+			return
+		} else if err != nil {
+			connection.publish(&frameResult{error: err})
+			return
+		}
+
+		if messageType == websocket.BinaryMessage {
+			connection.publish(&frameResult{frame: message.BinaryFrame(data)})
+		} else {
+			connection.publish(&frameResult{frame: message.TextFrame(string(data))})
+		}
+	}
+}
+
+func (connection *Connection) publish(result *frameResult) {
+	select {
+	case connection.frames <- result:
+	case <-connection.closed:
+	}
+}
+
+// Next blocks until the next frame is received, the timeout elapsed or the context ended
+func (connection *Connection) Next(ctx context.Context, timeout time.Duration) (*message.FrameMessage, error) {
+	ctx = internal.ActionContext(ctx)
+	select {
+	case result, open := <-connection.frames:
+		if !open {
+			return nil, errors.New("connection is closed")
+		}
+		return result.frame, result.error
+	case <-time.After(internal.ActionTimeout(timeout)):
+		return nil, errors.New("no frame received")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Write sends a frame. Sending a close frame starts the closing handshake, the connection is closed
+// after the close frame of the peer was received.
+func (connection *Connection) Write(frame *message.FrameMessage) error {
+	deadline := time.Now().Add(controlWriteTimeout)
+
+	switch frame.FrameType {
+	case message.TextFrameType:
+		return connection.writeMessage(websocket.TextMessage, []byte(frame.FramePayload))
+	case message.BinaryFrameType:
+		return connection.writeMessage(websocket.BinaryMessage, []byte(frame.FramePayload))
+	case message.PingFrameType:
+		return connection.conn.WriteControl(websocket.PingMessage, []byte(frame.FramePayload), deadline)
+	case message.PongFrameType:
+		return connection.conn.WriteControl(websocket.PongMessage, []byte(frame.FramePayload), deadline)
+	case message.CloseFrameType:
+		return connection.conn.WriteControl(websocket.CloseMessage,
+			websocket.FormatCloseMessage(frame.CloseCode, frame.CloseReason), deadline)
+	default:
+		return errors.New("unsupported frame type")
+	}
+}
+
+// data frames may not be written concurrently
+func (connection *Connection) writeMessage(messageType int, data []byte) error {
+	connection.writeLock.Lock()
+	defer connection.writeLock.Unlock()
+
+	return connection.conn.WriteMessage(messageType, data)
+}
+
+// Close closes the underlying network connection without a closing handshake
+func (connection *Connection) Close() {
+	connection.closeOnce.Do(func() {
+		close(connection.closed)
+		if err := connection.conn.Close(); err != nil {
+			connection.logger.Debugf("could not close connection - %s", err)
+		}
+	})
+}
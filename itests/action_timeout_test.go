@@ -0,0 +1,32 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Server answers after the timeout of the send action, which is shorter than the one of the endpoint
+func TestSendActionTimeout(t *testing.T) {
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		<-release
+	}))
+	defer testServer.Close()
+	defer close(release)
+
+	start := time.Now()
+	testClient.In(t).Send().
+		Timeout(50 * time.Millisecond).
+		Message(message.Get().BaseUrl(testServer.URL))
+
+	testClient.In(t).Receive().
+		ExpectError().
+		Timeout()
+
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("Send action timeout was not applied, exchange took %s", elapsed)
+	}
+}
@@ -0,0 +1,77 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"github.com/go-clarum/clarum-http/server"
+	"net/http"
+	"testing"
+)
+
+// Admin API
+// + stubs are added & removed remotely
+// + the journal lists the received requests
+// + faults are injected & cleared remotely
+// + reset removes stubs, journal & fault
+func TestAdminApi(t *testing.T) {
+	t.Cleanup(managedServer.Reset)
+
+	transportClient.In(t).Send().
+		Message(message.Post("endpoints", "managedServer", "stubs").
+			BaseUrl("http://localhost:8106").
+			ContentType("application/json").
+			Payload("{\"name\": \"get-user\", \"request\": {\"method\": \"GET\", \"path\": \"/users/1\"}, " +
+				"\"response\": {\"status\": 200, \"payload\": \"bruce\"}}"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusCreated))
+
+	transportClient.In(t).Send().
+		Message(message.Get("users", "1").BaseUrl("http://localhost:8103"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusOK).Payload("bruce"))
+
+	transportClient.In(t).Send().
+		Message(message.Get("endpoints", "managedServer", "journal").BaseUrl("http://localhost:8106"))
+	transportClient.In(t).Receive().
+		Json().
+		Message(message.Response(http.StatusOK).
+			Payload("[{\"receivedAt\": \"@ignore@\", \"method\": \"GET\", \"url\": \"/users/1\", \"headers\": \"@ignore@\"}]"))
+
+	transportClient.In(t).Send().
+		Message(message.Put("endpoints", "managedServer", "fault").
+			BaseUrl("http://localhost:8106").
+			Payload("{\"status\": 503}"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusOK))
+
+	transportClient.In(t).Send().
+		Message(message.Get("users", "1").BaseUrl("http://localhost:8103"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusServiceUnavailable))
+
+	transportClient.In(t).Send().
+		Message(message.Post("endpoints", "managedServer", "reset").BaseUrl("http://localhost:8106"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusNoContent))
+
+	// no stub anymore
+	transportClient.In(t).Send().
+		Message(message.Get("users", "1").BaseUrl("http://localhost:8103"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusNotFound))
+
+	if journal := managedServer.Journal(); len(journal) != 1 {
+		t.Errorf("Expected 1 journal entry after reset, but got %d", len(journal))
+	}
+}
+
+// An aborted request is closed without response
+func TestAbortFault(t *testing.T) {
+	managedServer.InjectFault(&server.Fault{Abort: true})
+	t.Cleanup(managedServer.Reset)
+
+	transportClient.In(t).Send().
+		Message(message.Get("users", "1").BaseUrl("http://localhost:8103"))
+	transportClient.In(t).Receive().
+		ExpectError().
+		EOF()
+}
@@ -0,0 +1,56 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/contract"
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+// Consumer driven contracts
+// + the expected request & the response of the server endpoint become an interaction
+// + a request that fails validation is not part of the contract
+// + the saved contract is verified against the provider
+func TestContract(t *testing.T) {
+	transportClient.In(t).Send().
+		Message(message.Get("v1", "users", "1").
+			BaseUrl("http://localhost:8101"))
+
+	pactServer.In(t).Receive().
+		Message(message.Get("v1", "users", "1"))
+	pactServer.In(t).Send().
+		Message(message.Response(http.StatusOK).
+			ContentType("application/json").
+			Payload("{\"id\": 1, \"name\": \"string\"}"))
+
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusOK))
+
+	transportClient.In(t).Send().
+		Message(message.Delete("v1", "users", "1").
+			BaseUrl("http://localhost:8101"))
+
+	if _, err := pactServer.Receive().Message(message.Get("v1", "users", "1")); err == nil {
+		t.Errorf("Validation error expected")
+	}
+	pactServer.In(t).Send().
+		Message(message.Response(http.StatusNoContent))
+
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusNoContent))
+
+	filePath := filepath.Join(t.TempDir(), "itests-users.json")
+	if err := usersPact.Save(filePath); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	loaded, err := contract.Load(filePath)
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if len(loaded.Interactions) != 1 {
+		t.Fatalf("Expected 1 interaction, but got %d", len(loaded.Interactions))
+	}
+
+	verifyingClient.In(t).Verify(loaded)
+}
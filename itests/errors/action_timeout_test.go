@@ -0,0 +1,105 @@
+package errors
+
+import (
+	"context"
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// The following tests check the timeout & context of single actions.
+
+func TestReceiveActionTimeout(t *testing.T) {
+	expectedErrors := []string{
+		"errorsServer: receive action timed out - no request received for validation",
+		"errorsClient: receive action timed out - no response received for validation",
+	}
+
+	_, e1 := errorsServer.Receive().
+		Timeout(50 * time.Millisecond).
+		Message(message.Get())
+	_, e2 := errorsClient.Receive().
+		Timeout(50 * time.Millisecond).
+		Message(message.Response(http.StatusOK))
+
+	checkErrors(t, expectedErrors, e1, e2)
+}
+
+func TestSendActionTimeout(t *testing.T) {
+	expectedErrors := []string{
+		"errorsServer: send action timed out - no request received for validation",
+	}
+
+	e1 := errorsServer.Send().
+		Timeout(50 * time.Millisecond).
+		Message(message.Response(http.StatusOK))
+
+	checkErrors(t, expectedErrors, e1)
+}
+
+func TestActionContextCanceled(t *testing.T) {
+	expectedErrors := []string{
+		"errorsServer: receive action canceled - context canceled",
+		"errorsServer: send action canceled - context canceled",
+		"errorsClient: receive action canceled - context canceled",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, e1 := errorsServer.Receive().
+		Context(ctx).
+		Message(message.Get())
+	e2 := errorsServer.Send().
+		Context(ctx).
+		Message(message.Response(http.StatusOK))
+	_, e3 := errorsClient.Receive().
+		Context(ctx).
+		Message(message.Response(http.StatusOK))
+
+	checkErrors(t, expectedErrors, e1, e2, e3)
+}
+
+func TestWebSocketActionTimeout(t *testing.T) {
+	expectedErrors := []string{
+		"errorsWsServer: receive action timed out - no upgrade request received for validation",
+		"errorsWsServer: send action timed out - no upgrade request received",
+		"errorsWsClient: receive action timed out - no upgrade response received for validation",
+	}
+
+	e1 := errorsWsServer.Receive().
+		Timeout(50 * time.Millisecond).
+		Upgrade(message.Get())
+	e2 := errorsWsServer.Send().
+		Timeout(50 * time.Millisecond).
+		Upgrade(message.Response(http.StatusSwitchingProtocols))
+	e3 := errorsWsClient.Receive().
+		Timeout(50 * time.Millisecond).
+		Upgrade(message.Response(http.StatusSwitchingProtocols))
+
+	checkErrors(t, expectedErrors, e1, e2, e3)
+}
+
+func TestWebSocketActionContextCanceled(t *testing.T) {
+	expectedErrors := []string{
+		"errorsWsServer: receive action canceled - context canceled",
+		"errorsWsServer: send action canceled - context canceled",
+		"errorsWsClient: receive action canceled - context canceled",
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	e1 := errorsWsServer.Receive().
+		Context(ctx).
+		Upgrade(message.Get())
+	e2 := errorsWsServer.Send().
+		Context(ctx).
+		Upgrade(message.Response(http.StatusSwitchingProtocols))
+	e3 := errorsWsClient.Receive().
+		Context(ctx).
+		Upgrade(message.Response(http.StatusSwitchingProtocols))
+
+	checkErrors(t, expectedErrors, e1, e2, e3)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/go-clarum/clarum-http/message"
 	"net/http"
 	"testing"
+	"time"
 )
 
 // The following tests check client receive response validation errors.
@@ -15,7 +16,7 @@ func TestHeaderMissingResponseValidation(t *testing.T) {
 	}
 
 	e1 := errorsClient.Send().
-		Message(message.Get().BaseUrl("http://localhost:8083"))
+		Message(message.Get().BaseUrl("http://localhost:8085"))
 
 	_, e2 := errorsServer.Receive().Message(message.Get())
 	e3 := errorsServer.Send().
@@ -35,7 +36,7 @@ func TestHeaderInvalidResponseValidation(t *testing.T) {
 	}
 
 	e1 := errorsClient.Send().
-		Message(message.Get().BaseUrl("http://localhost:8083"))
+		Message(message.Get().BaseUrl("http://localhost:8085"))
 
 	_, e2 := errorsServer.Receive().Message(message.Get())
 	e3 := errorsServer.Send().
@@ -58,7 +59,7 @@ func TestMissingTextResponsePayloadValidation(t *testing.T) {
 	}
 
 	e1 := errorsClient.Send().
-		Message(message.Get().BaseUrl("http://localhost:8083"))
+		Message(message.Get().BaseUrl("http://localhost:8085"))
 
 	_, e2 := errorsServer.Receive().Message(message.Get())
 	e3 := errorsServer.Send().
@@ -78,7 +79,7 @@ func TestWrongTextResponsePayloadValidation(t *testing.T) {
 	}
 
 	e1 := errorsClient.Send().
-		Message(message.Get().BaseUrl("http://localhost:8083"))
+		Message(message.Get().BaseUrl("http://localhost:8085"))
 
 	_, e2 := errorsServer.Receive().Message(message.Get())
 	e3 := errorsServer.Send().
@@ -91,3 +92,173 @@ func TestWrongTextResponsePayloadValidation(t *testing.T) {
 
 	checkErrors(t, expectedErrors, e1, e2, e3, e4)
 }
+
+// Attempts validation error: client has no retries configured
+func TestAttemptsResponseValidation(t *testing.T) {
+	expectedErrors := []string{
+		"validation error - attempts mismatch - expected [2] but were [1]",
+	}
+
+	e1 := errorsClient.Send().
+		Message(message.Get().BaseUrl("http://localhost:8085"))
+
+	_, e2 := errorsServer.Receive().Message(message.Get())
+	e3 := errorsServer.Send().
+		Message(message.Response(http.StatusOK))
+
+	_, e4 := errorsClient.Receive().
+		Attempts(2).
+		Message(message.Response(http.StatusOK))
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4)
+}
+
+// Transport error validation: response received instead of the expected error
+func TestExpectedTransportErrorValidation(t *testing.T) {
+	expectedErrors := []string{
+		"validation error - expected transport error [connection refused] but received response with status [200]",
+	}
+
+	e1 := errorsClient.Send().
+		Message(message.Get().BaseUrl("http://localhost:8085"))
+
+	_, e2 := errorsServer.Receive().Message(message.Get())
+	e3 := errorsServer.Send().
+		Message(message.Response(http.StatusOK))
+
+	e4 := errorsClient.Receive().
+		ExpectError().
+		ConnectionRefused()
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4)
+}
+
+// Transport error validation: transport failed in a different way
+func TestTransportErrorMismatchValidation(t *testing.T) {
+	expectedErrors := []string{
+		"validation error - transport error mismatch - expected [timeout] but received",
+	}
+
+	e1 := errorsClient.Send().
+		Message(message.Get().BaseUrl("http://localhost:8099"))
+
+	e2 := errorsClient.Receive().
+		ExpectError().
+		Timeout()
+
+	checkErrors(t, expectedErrors, e1, e2)
+}
+
+// Response time validation error: server answers too late
+func TestResponseTimeValidation(t *testing.T) {
+	expectedErrors := []string{
+		"validation error - response time exceeded - expected within [50ms] but took",
+	}
+
+	e1 := errorsClient.Send().
+		Message(message.Get().BaseUrl("http://localhost:8085"))
+
+	_, e2 := errorsServer.Receive().Message(message.Get())
+	time.Sleep(100 * time.Millisecond)
+	e3 := errorsServer.Send().
+		Message(message.Response(http.StatusOK))
+
+	_, e4 := errorsClient.Receive().
+		Within(50 * time.Millisecond).
+		Message(message.Response(http.StatusOK))
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4)
+}
+
+// Event validation error: event data mismatch
+func TestEventStreamValidation(t *testing.T) {
+	expectedErrors := []string{
+		"validation error - payload mismatch - expected [expected data] but received [wrong data]",
+	}
+
+	e1 := errorsClient.Send().
+		Message(message.Get().BaseUrl("http://localhost:8085"))
+
+	_, e2 := errorsServer.Receive().Message(message.Get())
+	serverStream, e3 := errorsServer.Send().
+		EventStream(message.Response(http.StatusOK))
+
+	clientStream, e4 := errorsClient.Receive().
+		EventStream(message.Response(http.StatusOK))
+
+	e5 := serverStream.Event(message.Event().Data("wrong data"))
+	e6 := clientStream.Event(message.Event().Data("expected data"))
+
+	serverStream.Close()
+	clientStream.Close()
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4, e5, e6)
+}
+
+// Event stream validation error: response is not an event stream
+func TestNoEventStreamValidation(t *testing.T) {
+	expectedErrors := []string{
+		"validation error - expected event stream but received content type [text/plain]",
+	}
+
+	e1 := errorsClient.Send().
+		Message(message.Get().BaseUrl("http://localhost:8085"))
+
+	_, e2 := errorsServer.Receive().Message(message.Get())
+	e3 := errorsServer.Send().
+		Message(message.Response(http.StatusOK).ContentType("text/plain"))
+
+	_, e4 := errorsClient.Receive().
+		EventStream(message.Response(http.StatusOK))
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4)
+}
+
+// Stream validation error: more data than expected
+func TestStreamEndValidation(t *testing.T) {
+	expectedErrors := []string{
+		"validation error - expected end of stream but received [unexpected",
+	}
+
+	e1 := errorsClient.Send().
+		Message(message.Get().BaseUrl("http://localhost:8085"))
+
+	_, e2 := errorsServer.Receive().Message(message.Get())
+	serverStream, e3 := errorsServer.Send().
+		Stream(message.Response(http.StatusOK).ContentType("application/x-ndjson"))
+
+	clientStream, e4 := errorsClient.Receive().
+		Stream(message.Response(http.StatusOK))
+
+	e5 := serverStream.Record("expected")
+	e6 := serverStream.Record("unexpected")
+	e7 := clientStream.Record("expected")
+	e8 := clientStream.End()
+
+	e9 := serverStream.Close()
+	clientStream.Close()
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4, e5, e6, e7, e8, e9)
+}
+
+// Protocol validation error: HTTP/1.1 instead of HTTP/2
+func TestProtoValidation(t *testing.T) {
+	expectedErrors := []string{
+		"validation error - protocol mismatch - expected [HTTP/2.0] but received [HTTP/1.1]",
+	}
+
+	e1 := errorsClient.Send().
+		Message(message.Get().BaseUrl("http://localhost:8085"))
+
+	_, e2 := errorsServer.Receive().
+		Proto("HTTP/2.0").
+		Message(message.Get())
+	e3 := errorsServer.Send().
+		Message(message.Response(http.StatusOK))
+
+	_, e4 := errorsClient.Receive().
+		Proto("HTTP/2.0").
+		Message(message.Response(http.StatusOK))
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4)
+}
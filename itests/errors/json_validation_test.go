@@ -17,7 +17,7 @@ func TestJsonServerErrorValidation(t *testing.T) {
 
 	e1 := errorsClient.Send().
 		Message(message.Put().
-			BaseUrl("http://localhost:8083").
+			BaseUrl("http://localhost:8085").
 			Payload("{" +
 				"\"active\": true," +
 				" \"name\": \"Bruce Wayne\"," +
@@ -64,7 +64,7 @@ func TestJsonClientErrorValidation(t *testing.T) {
 
 	e1 := errorsClient.Send().
 		Message(message.Get().
-			BaseUrl("http://localhost:8083"))
+			BaseUrl("http://localhost:8085"))
 
 	_, e2 := errorsServer.Receive().
 		Json().
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+)
+
+// OpenAPI contract violations are reported next to the message validation errors
+func TestOpenApiValidation(t *testing.T) {
+	expectedErrors := []string{
+		"validation error - OpenAPI contract violation - request [POST /v1/users]",
+		"property \"name\" is missing",
+		"validation error - OpenAPI contract violation - response with status [201] to [POST /v1/users]",
+		"value must be an integer",
+	}
+
+	e1 := errorsContractClient.Send().
+		Message(message.Post("users").
+			ContentType("application/json").
+			Payload("{\"id\": 1}"))
+
+	_, e2 := errorsContractServer.Receive().
+		Message(message.Post("v1", "users"))
+	e3 := errorsContractServer.Send().
+		Message(message.Response(http.StatusCreated).
+			ContentType("application/json").
+			Payload("{\"id\": \"one\", \"name\": \"bruce\"}"))
+
+	_, e4 := errorsContractClient.Receive().
+		Message(message.Response(http.StatusCreated))
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4)
+}
+
+// a request without a matching operation is a contract violation
+func TestOpenApiUnknownOperation(t *testing.T) {
+	expectedErrors := []string{
+		"validation error - OpenAPI contract violation - no operation found for [DELETE /v1/users/1]",
+	}
+
+	e1 := errorsContractClient.Send().
+		Message(message.Delete("users", "1"))
+
+	_, e2 := errorsContractServer.Receive().
+		Message(message.Delete("v1", "users", "1"))
+	e3 := errorsContractServer.Send().
+		Message(message.Response(http.StatusNoContent))
+
+	_, e4 := errorsContractClient.Receive().
+		Message(message.Response(http.StatusNoContent))
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4)
+}
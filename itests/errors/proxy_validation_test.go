@@ -0,0 +1,27 @@
+package errors
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+)
+
+// Proxy validation errors: request mismatch & unreachable upstream
+func TestProxyValidation(t *testing.T) {
+	expectedErrors := []string{
+		"validation error - method mismatch - expected [POST] but received [GET]",
+		"errorsProxy: upstream did not respond",
+	}
+
+	e1 := errorsClient.Send().
+		Message(message.Get().BaseUrl("http://localhost:8093"))
+
+	// the system under test receives a Bad Gateway
+	_, e2 := errorsClient.Receive().
+		Message(message.Response(http.StatusBadGateway))
+
+	e3 := errorsProxy.Receive().Request(message.Post())
+	e4 := errorsProxy.Receive().Response(message.Response(http.StatusOK))
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4)
+}
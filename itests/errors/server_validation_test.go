@@ -15,7 +15,7 @@ func TestMethodValidation(t *testing.T) {
 		"validation error - method mismatch - expected [POST] but received [GET]",
 	}
 
-	e1 := errorsClient.Send().Message(message.Get().BaseUrl("http://localhost:8083/myApp"))
+	e1 := errorsClient.Send().Message(message.Get().BaseUrl("http://localhost:8085/myApp"))
 
 	_, e2 := errorsServer.Receive().Message(message.Post("myApp"))
 	e3 := errorsServer.Send().
@@ -35,7 +35,7 @@ func TestInvalidStatusCode(t *testing.T) {
 		"validation error - status mismatch - expected [200] but received [500]",
 	}
 
-	e1 := errorsClient.Send().Message(message.Get().BaseUrl("http://localhost:8083/myApp"))
+	e1 := errorsClient.Send().Message(message.Get().BaseUrl("http://localhost:8085/myApp"))
 
 	_, e2 := errorsServer.Receive().Message(message.Get("myApp"))
 	e3 := errorsServer.Send().
@@ -54,7 +54,7 @@ func TestStatusCodeValidation(t *testing.T) {
 		"validation error - status mismatch - expected [200] but received [400]",
 	}
 
-	e1 := errorsClient.Send().Message(message.Get().BaseUrl("http://localhost:8083/myApp"))
+	e1 := errorsClient.Send().Message(message.Get().BaseUrl("http://localhost:8085/myApp"))
 
 	_, e2 := errorsServer.Receive().Message(message.Get("myApp"))
 	e3 := errorsServer.Send().
@@ -75,7 +75,7 @@ func TestPathValidation(t *testing.T) {
 
 	e1 := errorsClient.Send().
 		Message(message.Get("my", "resource", "1234").
-			BaseUrl("http://localhost:8083"))
+			BaseUrl("http://localhost:8085"))
 
 	_, e2 := errorsServer.Receive().Message(message.Get("my", "resource", "5433"))
 	e3 := errorsServer.Send().
@@ -95,7 +95,7 @@ func TestHeaderMissingValidation(t *testing.T) {
 
 	e1 := errorsClient.Send().
 		Message(message.Get().
-			BaseUrl("http://localhost:8083").
+			BaseUrl("http://localhost:8085").
 			Authorization("Bearer: 123152123123"))
 
 	_, e2 := errorsServer.Receive().Message(message.Get().
@@ -118,7 +118,7 @@ func TestHeaderInvalidValidation(t *testing.T) {
 
 	e1 := errorsClient.Send().
 		Message(message.Get().
-			BaseUrl("http://localhost:8083").
+			BaseUrl("http://localhost:8085").
 			Authorization("Bearer: 123152123123"))
 
 	_, e2 := errorsServer.Receive().Message(message.Get().
@@ -140,7 +140,7 @@ func TestQueryParamMissingValidation(t *testing.T) {
 
 	e1 := errorsClient.Send().
 		Message(message.Get().
-			BaseUrl("http://localhost:8083").
+			BaseUrl("http://localhost:8085").
 			QueryParam("param1", "value1"))
 
 	_, e2 := errorsServer.Receive().Message(message.Get().
@@ -163,7 +163,7 @@ func TestQueryParamInvalidValueValidation(t *testing.T) {
 
 	e1 := errorsClient.Send().
 		Message(message.Get().
-			BaseUrl("http://localhost:8083").
+			BaseUrl("http://localhost:8085").
 			QueryParam("param1", "value1").
 			QueryParam("param2", "value2"))
 
@@ -187,7 +187,7 @@ func TestQueryParamInvalidMultiValueValidation(t *testing.T) {
 
 	e1 := errorsClient.Send().
 		Message(message.Get().
-			BaseUrl("http://localhost:8083").
+			BaseUrl("http://localhost:8085").
 			QueryParam("param1", "value1").
 			QueryParam("param2", "value2", "value4"))
 
@@ -210,7 +210,7 @@ func TestMissingTextRequestPayloadValidation(t *testing.T) {
 	}
 
 	e1 := errorsClient.Send().
-		Message(message.Post().BaseUrl("http://localhost:8083"))
+		Message(message.Post().BaseUrl("http://localhost:8085"))
 
 	_, e2 := errorsServer.Receive().Message(message.Post().
 		Payload("expected payload"))
@@ -230,7 +230,7 @@ func TestWrongTextRequestPayloadValidation(t *testing.T) {
 	}
 
 	e1 := errorsClient.Send().
-		Message(message.Post().BaseUrl("http://localhost:8083").
+		Message(message.Post().BaseUrl("http://localhost:8085").
 			Payload("wrong payload"))
 
 	_, e2 := errorsServer.Receive().Message(message.Post().
@@ -4,8 +4,10 @@ import (
 	"errors"
 	clarumcore "github.com/go-clarum/clarum-core"
 	clarumhttp "github.com/go-clarum/clarum-http"
+	"github.com/go-clarum/clarum-http/openapi"
 
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -18,7 +20,39 @@ var errorsClient = clarumhttp.Http().Client().
 
 var errorsServer = clarumhttp.Http().Server().
 	Name("errorsServer").
-	Port(8083).
+	Port(8085).
+	Build()
+
+var errorsWsClient = clarumhttp.WebSocket().Client().
+	Name("errorsWsClient").
+	BaseUrl("ws://localhost:8088").
+	Build()
+
+var errorsWsServer = clarumhttp.WebSocket().Server().
+	Name("errorsWsServer").
+	Port(8088).
+	Build()
+
+// the upstream is never started
+var errorsProxy = clarumhttp.Http().Proxy().
+	Name("errorsProxy").
+	Port(8093).
+	Upstream("http://localhost:8099").
+	Build()
+
+var errorsUsersSpec = openapi.MustLoad("../testdata/users.yaml").
+	ReportDir(filepath.Join(os.TempDir(), "clarum-http-itests", "errors", "openapi"))
+
+var errorsContractClient = clarumhttp.Http().Client().
+	Name("errorsContractClient").
+	BaseUrl("http://localhost:8098/v1").
+	OpenApi(errorsUsersSpec).
+	Build()
+
+var errorsContractServer = clarumhttp.Http().Server().
+	Name("errorsContractServer").
+	Port(8098).
+	OpenApi(errorsUsersSpec).
 	Build()
 
 func TestMain(m *testing.M) {
@@ -26,7 +60,10 @@ func TestMain(m *testing.M) {
 
 	result := m.Run()
 
-	clarumcore.Finish()
+	// exchanges that were not handled by a test fail the run
+	if err := clarumhttp.Finish(); err != nil && result == 0 {
+		result = 1
+	}
 
 	os.Exit(result)
 }
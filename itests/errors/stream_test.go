@@ -0,0 +1,52 @@
+package errors
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+)
+
+// The following tests check actions on streams that were already closed.
+
+func TestEventAfterClose(t *testing.T) {
+	expectedErrors := []string{
+		"errorsServer: could not send event - stream has already ended",
+	}
+
+	e1 := errorsClient.Send().
+		Message(message.Get().BaseUrl("http://localhost:8085"))
+
+	_, e2 := errorsServer.Receive().Message(message.Get())
+	serverStream, e3 := errorsServer.Send().
+		EventStream(message.Response(http.StatusOK))
+	clientStream, e4 := errorsClient.Receive().
+		EventStream(message.Response(http.StatusOK))
+
+	serverStream.Close()
+	e5 := serverStream.Event(message.Event().Data("too late"))
+	clientStream.Close()
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4, e5)
+}
+
+func TestChunkAfterClose(t *testing.T) {
+	expectedErrors := []string{
+		"errorsServer: could not send chunk - stream has already ended",
+	}
+
+	e1 := errorsClient.Send().
+		Message(message.Get().BaseUrl("http://localhost:8085"))
+
+	_, e2 := errorsServer.Receive().Message(message.Get())
+	serverStream, e3 := errorsServer.Send().
+		Stream(message.Response(http.StatusOK).ContentType("application/x-ndjson"))
+	clientStream, e4 := errorsClient.Receive().
+		Stream(message.Response(http.StatusOK).ContentType("application/x-ndjson"))
+
+	e5 := serverStream.Close()
+	e6 := serverStream.Chunk("too late")
+	e7 := serverStream.Record("too late")
+	clientStream.Close()
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4, e5, e6, e7)
+}
@@ -0,0 +1,49 @@
+package errors
+
+import (
+	clarumhttp "github.com/go-clarum/clarum-http"
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+)
+
+// WebSocket validation errors: frame type, payload & close code mismatch
+func TestWebSocketFrameValidation(t *testing.T) {
+	expectedErrors := []string{
+		"validation error - frame type mismatch - expected [text] but received [binary]",
+		"validation error - payload mismatch - expected [hello] but received [goodbye]",
+		"validation error - close code mismatch - expected [1000] but received [1001]",
+	}
+
+	e1 := errorsWsClient.Send().Upgrade(message.Get())
+	e2 := errorsWsServer.Receive().Upgrade(message.Get())
+	e3 := errorsWsServer.Send().Upgrade(message.Response(http.StatusSwitchingProtocols))
+	e4 := errorsWsClient.Receive().Upgrade(message.Response(http.StatusSwitchingProtocols))
+
+	e5 := errorsWsClient.Send().Frame(message.BinaryFrame([]byte("hello")))
+	e6 := errorsWsServer.Receive().Frame(message.TextFrame("hello"))
+
+	e7 := errorsWsServer.Send().Frame(message.TextFrame("goodbye"))
+	e8 := errorsWsClient.Receive().Frame(message.TextFrame("hello"))
+
+	e9 := errorsWsServer.Send().Frame(message.CloseFrame(1001, "going away"))
+	e10 := errorsWsClient.Receive().Frame(message.CloseFrame(1000, ""))
+
+	checkErrors(t, expectedErrors, e1, e2, e3, e4, e5, e6, e7, e8, e9, e10)
+}
+
+// WebSocket error: frame sent before the upgrade
+func TestWebSocketNoConnection(t *testing.T) {
+	expectedErrors := []string{
+		"unconnectedWsClient: no open connection - an upgrade response must be received first",
+	}
+
+	unconnectedClient := clarumhttp.WebSocket().Client().
+		Name("unconnectedWsClient").
+		Build()
+	defer unconnectedClient.Close()
+
+	e1 := unconnectedClient.Send().Frame(message.TextFrame("hello"))
+
+	checkErrors(t, expectedErrors, e1)
+}
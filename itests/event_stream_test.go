@@ -0,0 +1,68 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Server-Sent Events
+// + events are pushed & validated one by one
+// + JSON validation of event data
+// + reconnection with Last-Event-ID
+func TestEventStream(t *testing.T) {
+	testClient.In(t).Send().
+		Message(message.Get("events"))
+
+	firstTestServer.In(t).Receive().
+		Message(message.Get("myApp", "events"))
+	serverStream := firstTestServer.In(t).Send().
+		EventStream(message.Response(http.StatusOK))
+
+	clientStream := testClient.In(t).Receive().
+		Json().
+		EventStream(message.Response(http.StatusOK).
+			ContentType("text/event-stream"))
+
+	serverStream.Event(message.Event().
+		Id("1").
+		Name("created").
+		Retry(time.Second).
+		Data("{\"name\": \"Bruce Wayne\", \"timestamp\": 1234}"))
+	clientStream.Event(message.Event().
+		Id("1").
+		Name("created").
+		Retry(time.Second).
+		Data("{\"name\": \"Bruce Wayne\", \"timestamp\": \"@ignore@\"}"))
+
+	serverStream.Event(message.Event().
+		Id("2").
+		Name("updated").
+		Data("{\"name\": \"Batman\"}"))
+	clientStream.Event(message.Event().
+		Name("updated").
+		Data("{\"name\": \"Batman\"}"))
+
+	serverStream.Close()
+	clientStream.Reconnect()
+
+	firstTestServer.In(t).Receive().
+		Message(message.Get("myApp", "events").
+			Header("Last-Event-ID", "2"))
+	secondServerStream := firstTestServer.In(t).Send().
+		EventStream(message.Response(http.StatusOK))
+
+	secondClientStream := testClient.In(t).Receive().
+		EventStream(message.Response(http.StatusOK))
+
+	secondServerStream.Event(message.Event().
+		Id("3").
+		Data("multi\nline"))
+	secondClientStream.Event(message.Event().
+		Id("3").
+		Data("multi\nline"))
+
+	secondServerStream.Close()
+	secondClientStream.Close()
+}
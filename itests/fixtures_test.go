@@ -0,0 +1,48 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+)
+
+// Record & replay
+// + the proxy records the exchange with the real backend, with redaction
+// + the replay server answers the same request from the recorded fixture
+// + a request without fixture is answered with 404
+func TestRecordAndReplay(t *testing.T) {
+	transportClient.In(t).Send().
+		Message(message.Get("users", "1").
+			BaseUrl("http://localhost:8094").
+			QueryParam("details", "true").
+			Authorization("Bearer secret-token"))
+
+	backendServer.In(t).Receive().
+		Message(message.Get("backend", "users", "1"))
+	backendServer.In(t).Send().
+		Message(message.Response(http.StatusOK).
+			ContentType("application/json").
+			Payload("{\"id\": 1, \"apiKey\": \"secret-4711\"}"))
+
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusOK).
+			Payload("{\"id\": 1, \"apiKey\": \"secret-4711\"}"))
+	fixtureRecordingProxy.In(t).Receive().
+		Request(message.Get("users", "1"))
+
+	transportClient.In(t).Send().
+		Message(message.Get("users", "1").
+			BaseUrl("http://localhost:8095").
+			QueryParam("details", "true"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusOK).
+			ContentType("application/json").
+			Payload("{\"id\": 1, \"apiKey\": \"[REDACTED]\"}"))
+
+	// the query is part of the match rules
+	transportClient.In(t).Send().
+		Message(message.Get("users", "1").
+			BaseUrl("http://localhost:8095"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusNotFound))
+}
@@ -0,0 +1,72 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/har"
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"path/filepath"
+	"testing"
+)
+
+// HAR export & import
+// + client & server record every exchange, with timings
+// + the exported HAR file is loaded & its entries drive a new exchange
+func TestHarExportAndImport(t *testing.T) {
+	recorded := len(harRecorder.Entries())
+
+	harClient.In(t).Send().
+		Message(message.Post("orders").
+			QueryParam("priority", "high").
+			ContentType("application/json").
+			Payload("{\"item\": \"batarang\"}"))
+
+	harServer.In(t).Receive().
+		Message(message.Post("orders"))
+	harServer.In(t).Send().
+		Message(message.Response(http.StatusCreated).
+			ContentType("application/json").
+			Payload("{\"id\": 7}"))
+
+	harClient.In(t).Receive().
+		Message(message.Response(http.StatusCreated))
+
+	filePath := filepath.Join(t.TempDir(), "exchanges.har")
+	if err := harRecorder.Save(filePath); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	exported, err := har.Load(filePath)
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	// one entry from the client & one from the server
+	entries := exported.Log.Entries[recorded:]
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 HAR entries, but got %d", len(entries))
+	}
+
+	for _, entry := range entries {
+		if entry.Request.Url != "http://localhost:8096/orders?priority=high" {
+			t.Errorf("Unexpected request url %s", entry.Request.Url)
+		}
+		if entry.Response.Status != http.StatusCreated || entry.Response.Content.Text != "{\"id\": 7}" {
+			t.Errorf("Unexpected response %+v", entry.Response)
+		}
+		if entry.Time <= 0 || entry.Timings.Wait < 0 {
+			t.Errorf("Timings were not recorded %+v", entry.Timings)
+		}
+	}
+
+	entry := entries[0]
+	requestMessage, _ := entry.RequestMessage()
+	responseMessage, _ := entry.ResponseMessage()
+
+	harClient.In(t).Send().
+		Message(requestMessage)
+	harServer.In(t).Receive().
+		Message(requestMessage)
+	harServer.In(t).Send().
+		Message(responseMessage)
+	harClient.In(t).Receive().
+		Message(responseMessage)
+}
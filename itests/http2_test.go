@@ -0,0 +1,65 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+)
+
+// HTTP/2 over cleartext with prior knowledge
+// + protocol validation on both sides
+func TestH2CExchange(t *testing.T) {
+	h2cClient.In(t).Send().
+		Message(message.Get("status"))
+
+	h2cServer.In(t).Receive().
+		Proto("HTTP/2.0").
+		Message(message.Get("status"))
+	h2cServer.In(t).Send().
+		Message(message.Response(http.StatusOK).Payload("up"))
+
+	h2cClient.In(t).Receive().
+		Proto("HTTP/2.0").
+		Message(message.Response(http.StatusOK).Payload("up"))
+}
+
+// HTTP/2 over TLS, negotiated with ALPN
+func TestHttp2TlsExchange(t *testing.T) {
+	tlsClient.In(t).Send().
+		Message(message.Get("status"))
+
+	tlsServer.In(t).Receive().
+		Proto("HTTP/2.0").
+		Message(message.Get("status"))
+	tlsServer.In(t).Send().
+		Message(message.Response(http.StatusOK).Payload("up"))
+
+	tlsClient.In(t).Receive().
+		Proto("HTTP/2.0").
+		Message(message.Response(http.StatusOK).Payload("up"))
+}
+
+// Concurrent streams on the same connection
+// + each response is sent to the request received first that was not answered yet
+func TestHttp2ConcurrentExchanges(t *testing.T) {
+	h2cClient.In(t).Send().
+		Message(message.Get("first"))
+	h2cServer.In(t).Receive().
+		Message(message.Get("first"))
+
+	// the first request is still open while the second one arrives
+	h2cClient.In(t).Send().
+		Message(message.Get("second"))
+	h2cServer.In(t).Receive().
+		Message(message.Get("second"))
+
+	h2cServer.In(t).Send().
+		Message(message.Response(http.StatusOK).Payload("first"))
+	h2cClient.In(t).Receive().
+		Message(message.Response(http.StatusOK).Payload("first"))
+
+	h2cServer.In(t).Send().
+		Message(message.Response(http.StatusOK).Payload("second"))
+	h2cClient.In(t).Receive().
+		Message(message.Response(http.StatusOK).Payload("second"))
+}
@@ -0,0 +1,92 @@
+package itests
+
+import (
+	clarumhttp "github.com/go-clarum/clarum-http"
+	"github.com/go-clarum/clarum-http/constants"
+	"github.com/go-clarum/clarum-http/message"
+	"github.com/go-clarum/clarum-http/server"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// In-process client & server
+// + no socket is opened by any of the endpoints
+func TestInProcessEndpoints(t *testing.T) {
+	inProcessClient.In(t).Send().
+		Message(message.Post("orders").
+			QueryParam("type", "express").
+			Payload("my order"))
+
+	inProcessServer.In(t).Receive().
+		Message(message.Post("orders").
+			QueryParam("type", "express").
+			Payload("my order"))
+	inProcessServer.In(t).Send().
+		Message(message.Response(http.StatusCreated).
+			Payload("created"))
+
+	inProcessClient.In(t).Receive().
+		Message(message.Response(http.StatusCreated).
+			Payload("created"))
+}
+
+// In-process client calling a handler of the application under test
+// + JSON validation works the same as over the network
+func TestInProcessHandler(t *testing.T) {
+	handlerClient := clarumhttp.Http().Client().
+		Name("handlerClient").
+		InProcess(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+			body, _ := io.ReadAll(request.Body)
+
+			writer.Header().Set(constants.ContentTypeHeaderName, constants.ContentTypeJsonHeader)
+			writer.WriteHeader(http.StatusOK)
+			_, _ = writer.Write([]byte("{\"method\": \"" + request.Method + "\", \"received\": " + string(body) + "}"))
+		})).
+		Build()
+	// the name is registered & must be freed for the next run of the test
+	defer handlerClient.Close()
+
+	handlerClient.In(t).Send().
+		Message(message.Put("items", "1").
+			Payload("{\"name\": \"Bruce Wayne\"}"))
+
+	handlerClient.In(t).Receive().
+		Json().
+		Message(message.Response(http.StatusOK).
+			ContentType(constants.ContentTypeJsonHeader).
+			Payload("{\"method\": \"PUT\", \"received\": {\"name\": \"Bruce Wayne\"}}"))
+}
+
+// In-process server mounted into an httptest.Server
+func TestInProcessServerMounted(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.Handle("/mounted/", inProcessServer)
+	testServer := httptest.NewServer(mux)
+	defer testServer.Close()
+
+	testClient.In(t).Send().
+		Message(message.Get("mounted", "resource").
+			BaseUrl(testServer.URL))
+
+	inProcessServer.In(t).Receive().
+		Message(message.Get("mounted", "resource"))
+	inProcessServer.In(t).Send().
+		Message(message.Response(http.StatusNoContent))
+
+	testClient.In(t).Receive().
+		Message(message.Response(http.StatusNoContent))
+}
+
+// An injected abort fault is a transport error for the in-process client, like over the network
+func TestInProcessAbortFault(t *testing.T) {
+	inProcessServer.InjectFault(&server.Fault{Abort: true})
+	t.Cleanup(inProcessServer.ClearFault)
+
+	inProcessClient.In(t).Send().
+		Message(message.Get("orders"))
+	inProcessClient.In(t).Receive().
+		ExpectError().
+		EOF()
+}
@@ -0,0 +1,48 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"sync/atomic"
+	"testing"
+)
+
+// transport that counts all requests before delegating to the default transport
+type roundTripCounter struct {
+	count atomic.Int32
+}
+
+func (counter *roundTripCounter) RoundTrip(request *http.Request) (*http.Response, error) {
+	counter.count.Add(1)
+	return http.DefaultTransport.RoundTrip(request)
+}
+
+var countingTransport = &roundTripCounter{}
+
+// Interceptors on both endpoints
+// + client injects a header before sending
+// + server validates the injected header & its own after-receive header
+// + server injects a header in the response before sending
+// + custom transport is used by the client
+func TestInterceptors(t *testing.T) {
+	before := countingTransport.count.Load()
+
+	interceptedClient.In(t).Send().
+		Message(message.Get("intercepted"))
+
+	interceptedServer.In(t).Receive().
+		Message(message.Get("intercepted").
+			Header("X-Client-Interceptor", "injected").
+			Header("X-Server-Received", "true"))
+	interceptedServer.In(t).Send().
+		Message(message.Response(http.StatusOK))
+
+	interceptedClient.In(t).Receive().
+		Message(message.Response(http.StatusOK).
+			Header("X-Server-Interceptor", "injected").
+			Header("X-Client-Received", "true"))
+
+	if countingTransport.count.Load() != before+1 {
+		t.Errorf("custom transport was not used")
+	}
+}
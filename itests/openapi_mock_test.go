@@ -0,0 +1,75 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+)
+
+// OpenAPI mock server
+// + operations are answered with data generated from the spec
+// + invalid requests are answered with 400 and the violations
+// + overridden operations are handled by the test, only for the duration of the test
+func TestOpenApiMock(t *testing.T) {
+	transportClient.In(t).Send().
+		Message(message.Get("v1", "users", "1").
+			BaseUrl("http://localhost:8100"))
+	transportClient.In(t).Receive().
+		Json().
+		Message(message.Response(http.StatusOK).
+			ContentType("application/json").
+			Payload("{\"id\": 1, \"name\": \"string\"}"))
+
+	transportClient.In(t).Send().
+		Message(message.Post("v1", "users").
+			BaseUrl("http://localhost:8100").
+			ContentType("application/json").
+			Payload("{\"id\": 1}"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusBadRequest).
+			ContentType("text/plain"))
+
+	t.Run("override", func(t *testing.T) {
+		mockServer.In(t).Override("getUser")
+
+		transportClient.In(t).Send().
+			Message(message.Get("v1", "users", "2").
+				BaseUrl("http://localhost:8100"))
+
+		mockServer.In(t).Receive().
+			Message(message.Get("v1", "users", "2"))
+		mockServer.In(t).Send().
+			Message(message.Response(http.StatusNotFound))
+
+		transportClient.In(t).Receive().
+			Message(message.Response(http.StatusNotFound))
+	})
+
+	// the override ended with the subtest
+	transportClient.In(t).Send().
+		Message(message.Get("v1", "users", "2").
+			BaseUrl("http://localhost:8100"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusOK))
+}
+
+// OpenAPI mock server
+// + the end of a test does not remove an override of the endpoint
+func TestOpenApiMockEndpointOverride(t *testing.T) {
+	mockServer.Override("getUser")
+	defer mockServer.RemoveOverrides("getUser")
+
+	t.Run("override", func(t *testing.T) {
+		mockServer.In(t).Override("getUser")
+	})
+
+	transportClient.In(t).Send().
+		Message(message.Get("v1", "users", "3").
+			BaseUrl("http://localhost:8100"))
+	mockServer.In(t).Receive().
+		Message(message.Get("v1", "users", "3"))
+	mockServer.In(t).Send().
+		Message(message.Response(http.StatusNotFound))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusNotFound))
+}
@@ -0,0 +1,47 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+)
+
+// OpenAPI contract validation
+// + the server validates the received request against the spec
+// + the client validates the received response against the spec
+// + the exercised operations & status codes are part of the coverage
+func TestOpenApiContract(t *testing.T) {
+	contractClient.In(t).Send().
+		Message(message.Post("users").
+			ContentType("application/json").
+			Payload("{\"id\": 1, \"name\": \"bruce\"}"))
+
+	contractServer.In(t).Receive().
+		Message(message.Post("v1", "users"))
+	contractServer.In(t).Send().
+		Message(message.Response(http.StatusCreated).
+			ContentType("application/json").
+			Payload("{\"id\": 1, \"name\": \"bruce\"}"))
+
+	contractClient.In(t).Receive().
+		Json().
+		Message(message.Response(http.StatusCreated).
+			Payload("{\"id\": 1, \"name\": \"bruce\"}"))
+
+	contractClient.In(t).Send().
+		Message(message.Get("users", "1").
+			QueryParam("details", "true"))
+
+	contractServer.In(t).Receive().
+		Message(message.Get("v1", "users", "1"))
+	contractServer.In(t).Send().
+		Message(message.Response(http.StatusNotFound))
+
+	contractClient.In(t).Receive().
+		Message(message.Response(http.StatusNotFound))
+
+	coverage := usersSpec.Coverage()
+	if coverage.OperationsCovered != 2 || coverage.StatusCodesCovered != 2 || coverage.StatusCodesTotal != 3 {
+		t.Errorf("Unexpected coverage %s", coverage)
+	}
+}
@@ -0,0 +1,37 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/constants"
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Parallel tests on shared endpoints
+// + every test only receives its own requests on the server
+// + every test only receives the responses to its own requests on the client
+// + the test header names the test that sent the request
+func TestParallelTests(t *testing.T) {
+	for _, user := range []string{"bruce", "alfred", "selina", "dick"} {
+		t.Run(user, func(t *testing.T) {
+			t.Parallel()
+
+			isolatedClient.In(t).Send().
+				Message(message.Get("users", user))
+			// the requests of all tests arrive before any of them is received
+			time.Sleep(100 * time.Millisecond)
+
+			isolatedServer.In(t).Receive().
+				Message(message.Get("users", user).
+					Header(constants.TestHeaderName, t.Name()))
+			isolatedServer.In(t).Send().
+				Message(message.Response(http.StatusOK).
+					Payload(user))
+
+			isolatedClient.In(t).Receive().
+				Message(message.Response(http.StatusOK).
+					Payload(user))
+		})
+	}
+}
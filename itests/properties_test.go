@@ -0,0 +1,27 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+)
+
+// Endpoints defined in clarum-properties.yaml
+// + base URL & port are substituted from env vars or their defaults
+// + the client sends the configured basic auth credentials
+func TestEndpointsFromConfig(t *testing.T) {
+	configClient.In(t).Send().
+		Message(message.Get("users", "1"))
+
+	configServer.In(t).Receive().
+		Message(message.Get("myApp", "users", "1").
+			Authorization("Basic YnJ1Y2U6YWxmcmVk").
+			ContentType("text/plain"))
+	configServer.In(t).Send().
+		Message(message.Response(http.StatusOK).Payload("bruce"))
+
+	configClient.In(t).Receive().
+		Message(message.Response(http.StatusOK).
+			ContentType("text/plain").
+			Payload("bruce"))
+}
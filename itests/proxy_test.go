@@ -0,0 +1,89 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Recording proxy
+// + the request is forwarded to the upstream without any action of the test
+// + both legs of the exchange are validated afterward
+func TestProxyRecording(t *testing.T) {
+	proxiedClient.In(t).Send().
+		Message(message.Post("orders").
+			QueryParam("dryRun", "true").
+			Payload("{\"item\": \"book\"}"))
+
+	backendServer.In(t).Receive().
+		Json().
+		Message(message.Post("backend", "orders").
+			QueryParam("dryRun", "true").
+			Payload("{\"item\": \"book\"}"))
+	backendServer.In(t).Send().
+		Message(message.Response(http.StatusCreated).
+			Header("Location", "/orders/1"))
+
+	proxiedClient.In(t).Receive().
+		Message(message.Response(http.StatusCreated).
+			Header("Location", "/orders/1"))
+
+	recordingProxy.In(t).Receive().
+		Json().
+		Request(message.Post("orders").
+			QueryParam("dryRun", "true").
+			Payload("{\"item\": \"@ignore@\"}"))
+	recordingProxy.In(t).Receive().
+		Response(message.Response(http.StatusCreated).
+			Header("Location", "/orders/1"))
+}
+
+// Recording proxy with a response override
+// + the system under test receives the override, the upstream response is still recorded
+func TestProxyOverride(t *testing.T) {
+	recordingProxy.In(t).Send().
+		Response(message.Response(http.StatusServiceUnavailable))
+
+	proxiedClient.In(t).Send().
+		Message(message.Get("orders"))
+
+	backendServer.In(t).Receive().
+		Message(message.Get("backend", "orders"))
+	backendServer.In(t).Send().
+		Message(message.Response(http.StatusOK).Payload("[]"))
+
+	proxiedClient.In(t).Receive().
+		Message(message.Response(http.StatusServiceUnavailable))
+
+	recordingProxy.In(t).Receive().
+		Request(message.Get("orders"))
+	recordingProxy.In(t).Receive().
+		Response(message.Response(http.StatusOK).Payload("[]"))
+}
+
+// Recording proxy with a delayed response
+func TestProxyDelay(t *testing.T) {
+	recordingProxy.In(t).Send().
+		Delay(100 * time.Millisecond).
+		Upstream()
+
+	start := time.Now()
+	proxiedClient.In(t).Send().
+		Message(message.Get("orders"))
+
+	backendServer.In(t).Receive().
+		Message(message.Get("backend", "orders"))
+	backendServer.In(t).Send().
+		Message(message.Response(http.StatusOK))
+
+	proxiedClient.In(t).Receive().
+		Message(message.Response(http.StatusOK))
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("Response was expected to be delayed, but was received after %s", elapsed)
+	}
+
+	recordingProxy.In(t).Receive().
+		Response(message.Response(http.StatusOK))
+}
@@ -0,0 +1,64 @@
+package itests
+
+import (
+	clarumhttp "github.com/go-clarum/clarum-http"
+	"strings"
+	"testing"
+)
+
+// Endpoint registry
+// + endpoints built through Http() & WebSocket() are found by name
+// + duplicate names & ports are rejected before a server starts
+// + an invalid name is rejected
+func TestRegistry(t *testing.T) {
+	if clarumhttp.Http().ClientNamed("testClient") != testClient {
+		t.Errorf("Lookup did not return the testClient endpoint")
+	}
+	if clarumhttp.Http().ServerNamed("stubServer") != stubServer {
+		t.Errorf("Lookup did not return the stubServer endpoint")
+	}
+	if clarumhttp.WebSocket().ClientNamed("wsClient") != wsClient {
+		t.Errorf("Lookup did not return the wsClient endpoint")
+	}
+	if clarumhttp.WebSocket().ServerNamed("wsServer") != wsServer {
+		t.Errorf("Lookup did not return the wsServer endpoint")
+	}
+
+	expectPanic(t, "no client endpoint [unknownClient] registered", func() {
+		clarumhttp.Http().ClientNamed("unknownClient")
+	})
+	expectPanic(t, "endpoint [stubServer] is a server endpoint, not a client endpoint", func() {
+		clarumhttp.Http().ClientNamed("stubServer")
+	})
+	expectPanic(t, "invalid client endpoint - name [testClient] is already used by a client endpoint", func() {
+		clarumhttp.Http().Client().Name("testClient").Build()
+	})
+	expectPanic(t, "invalid server endpoint [otherServer] - port [8102] is already used by server endpoint [stubServer]",
+		func() {
+			clarumhttp.Http().Server().Name("otherServer").Port(8102).Build()
+		})
+	expectPanic(t, "invalid websocket server endpoint [otherWsServer] - port [8102] is already used by server endpoint [stubServer]",
+		func() {
+			clarumhttp.WebSocket().Server().Name("otherWsServer").Port(8102).Build()
+		})
+	expectPanic(t, "invalid server endpoint [otherServer] - port [8087] is already used by websocket server endpoint [wsServer]",
+		func() {
+			clarumhttp.Http().Server().Name("otherServer").Port(8087).Build()
+		})
+	expectPanic(t, "invalid client endpoint - name is empty", func() {
+		clarumhttp.Http().Client().Build()
+	})
+}
+
+func expectPanic(t *testing.T, expected string, action func()) {
+	t.Helper()
+	defer func() {
+		t.Helper()
+		recovered := recover()
+		err, isError := recovered.(error)
+		if !isError || !strings.Contains(err.Error(), expected) {
+			t.Errorf("Expected panic [%s], but got [%v]", expected, recovered)
+		}
+	}()
+	action()
+}
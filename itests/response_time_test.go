@@ -0,0 +1,24 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Response time validation
+// + server answers right away
+func TestResponseWithin(t *testing.T) {
+	testClient.In(t).Send().
+		Message(message.Get("fast"))
+
+	firstTestServer.In(t).Receive().
+		Message(message.Get("myApp", "fast"))
+	firstTestServer.In(t).Send().
+		Message(message.Response(http.StatusOK))
+
+	testClient.In(t).Receive().
+		Within(time.Second).
+		Message(message.Response(http.StatusOK))
+}
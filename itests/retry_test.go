@@ -0,0 +1,66 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/constants"
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+)
+
+// Retry on configured status
+// + server answers twice with 503
+// + Retry-After is honoured
+func TestRetryOnStatus(t *testing.T) {
+	retryClient.In(t).Send().
+		Message(message.Get("resource"))
+
+	for i := 0; i < 2; i++ {
+		firstTestServer.In(t).Receive().
+			Message(message.Get("myApp", "resource"))
+		firstTestServer.In(t).Send().
+			Message(message.Response(http.StatusServiceUnavailable).
+				Header(constants.RetryAfterHeaderName, "0"))
+	}
+
+	firstTestServer.In(t).Receive().
+		Message(message.Get("myApp", "resource"))
+	firstTestServer.In(t).Send().
+		Message(message.Response(http.StatusOK))
+
+	retryClient.In(t).Receive().
+		Attempts(3).
+		Message(message.Response(http.StatusOK))
+}
+
+// Retries exhausted
+// + last response is the one validated
+func TestRetriesExhausted(t *testing.T) {
+	retryClient.In(t).Send().
+		Message(message.Get("resource"))
+
+	for i := 0; i < 3; i++ {
+		firstTestServer.In(t).Receive().
+			Message(message.Get("myApp", "resource"))
+		firstTestServer.In(t).Send().
+			Message(message.Response(http.StatusServiceUnavailable))
+	}
+
+	retryClient.In(t).Receive().
+		Attempts(3).
+		Message(message.Response(http.StatusServiceUnavailable))
+}
+
+// No retry on status which is not configured
+func TestNoRetryOnOtherStatus(t *testing.T) {
+	retryClient.In(t).Send().
+		Message(message.Get("resource"))
+
+	firstTestServer.In(t).Receive().
+		Message(message.Get("myApp", "resource"))
+	firstTestServer.In(t).Send().
+		Message(message.Response(http.StatusInternalServerError))
+
+	retryClient.In(t).Receive().
+		Attempts(1).
+		Message(message.Response(http.StatusInternalServerError))
+}
@@ -1,9 +1,20 @@
 package itests
 
 import (
+	"crypto/tls"
 	clarumcore "github.com/go-clarum/clarum-core"
 	clarumhttp "github.com/go-clarum/clarum-http"
+	"github.com/go-clarum/clarum-http/contract"
+	"github.com/go-clarum/clarum-http/fixtures"
+	"github.com/go-clarum/clarum-http/har"
+	"github.com/go-clarum/clarum-http/message"
+	"github.com/go-clarum/clarum-http/openapi"
+	"github.com/go-clarum/clarum-http/server"
+	"io"
+	"log"
+	"net/http"
 	"os"
+	"path/filepath"
 	"testing"
 	"time"
 )
@@ -14,6 +25,33 @@ var testClient = clarumhttp.Http().Client().
 	Timeout(2000 * time.Millisecond).
 	Build()
 
+var retryClient = clarumhttp.Http().Client().
+	Name("retryClient").
+	BaseUrl("http://localhost:8083/myApp").
+	Retries(2).
+	RetryOnStatus(http.StatusServiceUnavailable).
+	RetryBackoff(10 * time.Millisecond).
+	Build()
+
+var interceptedClient = clarumhttp.Http().Client().
+	Name("interceptedClient").
+	BaseUrl("http://localhost:8086").
+	Transport(countingTransport).
+	BeforeSend(func(request *http.Request) error {
+		request.Header.Set("X-Client-Interceptor", "injected")
+		return nil
+	}).
+	AfterReceive(func(response *http.Response) error {
+		response.Header.Set("X-Client-Received", "true")
+		return nil
+	}).
+	Build()
+
+var transportClient = clarumhttp.Http().Client().
+	Name("transportClient").
+	Timeout(200 * time.Millisecond).
+	Build()
+
 var firstTestServer = clarumhttp.Http().Server().
 	Name("firstTestServer").
 	Port(8083).
@@ -24,12 +62,207 @@ var secondTestServer = clarumhttp.Http().Server().
 	Port(8084).
 	Build()
 
+var interceptedServer = clarumhttp.Http().Server().
+	Name("interceptedServer").
+	Port(8086).
+	AfterReceive(func(request *http.Request) error {
+		request.Header.Set("X-Server-Received", "true")
+		return nil
+	}).
+	BeforeSend(func(request *http.Request, response *message.ResponseMessage) error {
+		response.Header("X-Server-Interceptor", request.Header.Get("X-Client-Interceptor"))
+		return nil
+	}).
+	Build()
+
+var inProcessServer = clarumhttp.Http().Server().
+	Name("inProcessServer").
+	InProcess().
+	Build()
+
+var inProcessClient = clarumhttp.Http().Client().
+	Name("inProcessClient").
+	InProcess(inProcessServer).
+	Build()
+
+var h2cClient = clarumhttp.Http().Client().
+	Name("h2cClient").
+	BaseUrl("http://localhost:8089").
+	H2C().
+	Build()
+
+var h2cServer = clarumhttp.Http().Server().
+	Name("h2cServer").
+	Port(8089).
+	H2C().
+	Build()
+
+var tlsClient = clarumhttp.Http().Client().
+	Name("tlsClient").
+	BaseUrl("https://localhost:8090").
+	Http2().
+	TLSConfig(&tls.Config{InsecureSkipVerify: true}).
+	Build()
+
+// uses a generated self-signed certificate
+var tlsServer = clarumhttp.Http().Server().
+	Name("tlsServer").
+	Port(8090).
+	Http2().
+	Build()
+
+var proxiedClient = clarumhttp.Http().Client().
+	Name("proxiedClient").
+	BaseUrl("http://localhost:8091").
+	Build()
+
+var recordingProxy = clarumhttp.Http().Proxy().
+	Name("recordingProxy").
+	Port(8091).
+	Upstream("http://localhost:8092/backend").
+	Build()
+
+// plays the role of the real backend behind the proxy
+var backendServer = clarumhttp.Http().Server().
+	Name("backendServer").
+	Port(8092).
+	Build()
+
+var recordingsDir = filepath.Join(os.TempDir(), "clarum-http-itests", "recordings")
+
+var fixtureRecordingProxy = clarumhttp.Http().Proxy().
+	Name("fixtureRecordingProxy").
+	Port(8094).
+	Upstream("http://localhost:8092/backend").
+	Record(recordingsDir).
+	RedactHeaders("Authorization").
+	RedactBody("secret-[a-z0-9]+").
+	Build()
+
+var replayServer = clarumhttp.Http().Server().
+	Name("replayServer").
+	Port(8095).
+	Replay(recordingsDir).
+	MatchOn(fixtures.Method, fixtures.Path, fixtures.Query).
+	Build()
+
+// shared by client & server, so that both sides of every exchange are exported
+var harRecorder = har.NewRecorder()
+
+var harClient = clarumhttp.Http().Client().
+	Name("harClient").
+	BaseUrl("http://localhost:8096").
+	Har(harRecorder).
+	Build()
+
+var harServer = clarumhttp.Http().Server().
+	Name("harServer").
+	Port(8096).
+	Har(harRecorder).
+	Build()
+
+var usersSpec = openapi.MustLoad("testdata/users.yaml").
+	ReportDir(filepath.Join(os.TempDir(), "clarum-http-itests", "openapi"))
+
+var contractClient = clarumhttp.Http().Client().
+	Name("contractClient").
+	BaseUrl("http://localhost:8097/v1").
+	OpenApi(usersSpec).
+	Build()
+
+var contractServer = clarumhttp.Http().Server().
+	Name("contractServer").
+	Port(8097).
+	OpenApi(usersSpec).
+	Build()
+
+var mockServer = clarumhttp.Http().Server().
+	Name("mockServer").
+	Port(8100).
+	Mock(openapi.MustLoad("testdata/users.yaml")).
+	Build()
+
+var usersPact = contract.NewPact("itests", "users")
+
+// plays the provider for the consumer tests & writes the contract
+var pactServer = clarumhttp.Http().Server().
+	Name("pactServer").
+	Port(8101).
+	Contract(usersPact).
+	Build()
+
+// verifies the contract against the mock server, which plays the real provider
+var verifyingClient = clarumhttp.Http().Client().
+	Name("verifyingClient").
+	BaseUrl("http://localhost:8100").
+	Build()
+
+var stubServer = clarumhttp.Http().Server().
+	Name("stubServer").
+	Port(8102).
+	Stubs(&server.Stub{
+		Name:     "health",
+		Request:  message.Get("health"),
+		Response: message.Response(http.StatusOK).Payload("UP"),
+	}).
+	Build()
+
+var managedServer = clarumhttp.Http().Server().
+	Name("managedServer").
+	Port(8103).
+	StubsOnly().
+	Build()
+
+var adminApi = clarumhttp.Http().Admin().
+	Port(8106).
+	Endpoints(managedServer).
+	Build()
+
+var configClient = clarumhttp.Http().ClientFromConfig("configClient").Build()
+
+var configServer = clarumhttp.Http().ServerFromConfig("configServer").Build()
+
+// shared by the parallel tests
+var isolatedClient = clarumhttp.Http().Client().
+	Name("isolatedClient").
+	BaseUrl("http://localhost:8108").
+	IsolateTests().
+	Build()
+
+var isolatedServer = clarumhttp.Http().Server().
+	Name("isolatedServer").
+	Port(8108).
+	IsolateTests().
+	Build()
+
+var wsClient = clarumhttp.WebSocket().Client().
+	Name("wsClient").
+	BaseUrl("ws://localhost:8087").
+	Subprotocols("chat.v2", "chat.v1").
+	Build()
+
+var wsServer = clarumhttp.WebSocket().Server().
+	Name("wsServer").
+	Port(8087).
+	Subprotocols("chat.v1").
+	Build()
+
 func TestMain(m *testing.M) {
 	clarumcore.Setup()
+	// fixtures of earlier runs must not be replayed
+	if err := os.RemoveAll(recordingsDir); err != nil {
+		log.Fatalf("could not clean recordings - %s", err)
+	}
 
 	result := m.Run()
 
-	clarumcore.Finish()
+	// exchanges that were not handled by a test fail the run
+	if err := clarumhttp.Finish(); err != nil && result == 0 {
+		result = 1
+	}
 
 	os.Exit(result)
 }
+
+// used for test servers that are expected to log errors
+var discardLogger = log.New(io.Discard, "", 0)
@@ -0,0 +1,102 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/constants"
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Chunked streaming
+// + chunks with explicit flushes & pauses
+// + chunks split on a delimiter, independent of the reads on the body
+// + trailers after the body
+func TestChunkedStream(t *testing.T) {
+	testClient.In(t).Send().
+		Message(message.Get("download"))
+
+	firstTestServer.In(t).Receive().
+		Message(message.Get("myApp", "download"))
+	serverStream := firstTestServer.In(t).Send().
+		Stream(message.Response(http.StatusOK).
+			ContentType(constants.ContentTypeNdJsonHeader).
+			Header("Trailer", "X-Checksum"))
+
+	clientStream := testClient.In(t).Receive().
+		ChunkDelimiter(";").
+		Stream(message.Response(http.StatusOK).
+			ContentType(constants.ContentTypeNdJsonHeader))
+
+	serverStream.Chunk("first part;")
+	clientStream.Chunk("first part")
+
+	serverStream.Chunk("second part;")
+	serverStream.Pause(50 * time.Millisecond)
+	serverStream.Chunk("third ")
+	serverStream.Chunk("part;")
+
+	clientStream.Chunk("second part")
+	clientStream.Chunk("third part")
+
+	serverStream.Trailer("X-Checksum", "abcd")
+	serverStream.Close()
+
+	clientStream.End()
+	clientStream.Trailer("X-Checksum", "abcd")
+}
+
+// Closing a stream with trailers twice
+// + the second close does nothing
+func TestStreamClosedTwice(t *testing.T) {
+	testClient.In(t).Send().
+		Message(message.Get("download"))
+
+	firstTestServer.In(t).Receive().
+		Message(message.Get("myApp", "download"))
+	serverStream := firstTestServer.In(t).Send().
+		Stream(message.Response(http.StatusOK).
+			ContentType(constants.ContentTypeNdJsonHeader).
+			Header("Trailer", "X-Checksum"))
+
+	clientStream := testClient.In(t).Receive().
+		Stream(message.Response(http.StatusOK).
+			ContentType(constants.ContentTypeNdJsonHeader))
+
+	serverStream.Record("only part")
+	clientStream.Chunk("only part")
+
+	serverStream.Trailer("X-Checksum", "abcd")
+	serverStream.Close()
+	serverStream.Close()
+
+	clientStream.End()
+	clientStream.Trailer("X-Checksum", "abcd")
+}
+
+// NDJSON streaming
+// + records are validated one by one, independent of chunk boundaries
+func TestNdJsonStream(t *testing.T) {
+	testClient.In(t).Send().
+		Message(message.Get("items"))
+
+	firstTestServer.In(t).Receive().
+		Message(message.Get("myApp", "items"))
+	serverStream := firstTestServer.In(t).Send().
+		Stream(message.Response(http.StatusOK).
+			ContentType(constants.ContentTypeNdJsonHeader))
+
+	clientStream := testClient.In(t).Receive().
+		Json().
+		Stream(message.Response(http.StatusOK))
+
+	serverStream.Record("{\"id\": 1, \"name\": \"Bruce Wayne\"}")
+	serverStream.Chunk("{\"id\": 2, \"name\": \"Batman\"}\n{\"id\": 3,")
+	serverStream.Chunk(" \"name\": \"The Dark Knight\"}")
+	serverStream.Close()
+
+	clientStream.Record("{\"id\": 1, \"name\": \"Bruce Wayne\"}")
+	clientStream.Record("{\"id\": 2, \"name\": \"@ignore@\"}")
+	clientStream.Record("{\"id\": 3, \"name\": \"The Dark Knight\"}")
+	clientStream.End()
+}
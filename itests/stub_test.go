@@ -0,0 +1,105 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"github.com/go-clarum/clarum-http/server"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// Server stubs
+// + matching requests are answered by the stub, without actions in the test
+// + requests matching no stub are passed to the receive actions
+// + payloads can be matched as JSON & responses delayed
+func TestStubs(t *testing.T) {
+	transportClient.In(t).Send().
+		Message(message.Get("health").BaseUrl("http://localhost:8102"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusOK).Payload("UP"))
+
+	stubServer.AddStubs(&server.Stub{
+		Name: "create-order",
+		Request: message.Post("orders").
+			Payload("{\"item\": \"batarang\", \"count\": 2}"),
+		Json:     true,
+		Response: message.Response(http.StatusCreated),
+		Delay:    50 * time.Millisecond,
+	})
+	t.Cleanup(func() {
+		stubServer.RemoveStub("create-order")
+	})
+
+	start := time.Now()
+	transportClient.In(t).Send().
+		Message(message.Post("orders").
+			BaseUrl("http://localhost:8102").
+			Payload("{\"count\": 2, \"item\": \"batarang\"}"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusCreated))
+	if time.Since(start) < 50*time.Millisecond {
+		t.Errorf("Stub response was not delayed")
+	}
+
+	// a different payload does not match the stub
+	transportClient.In(t).Send().
+		Message(message.Post("orders").
+			BaseUrl("http://localhost:8102").
+			Payload("{\"item\": \"grapple gun\", \"count\": 1}"))
+	stubServer.In(t).Receive().
+		Message(message.Post("orders"))
+	stubServer.In(t).Send().
+		Message(message.Response(http.StatusConflict))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusConflict))
+}
+
+// Scenario stubs
+// + the first request is answered as pending & moves the scenario into the next state
+// + later requests are answered by the stub of the new state
+// + the scenario can be inspected & reset
+func TestScenarioStubs(t *testing.T) {
+	stubServer.AddStubs(
+		&server.Stub{
+			Name:          "export-pending",
+			Request:       message.Get("exports", "1"),
+			Response:      message.Response(http.StatusAccepted).Payload("pending"),
+			Scenario:      "export",
+			RequiredState: server.ScenarioStarted,
+			NewState:      "done",
+		},
+		&server.Stub{
+			Name:          "export-done",
+			Request:       message.Get("exports", "1"),
+			Response:      message.Response(http.StatusOK).Payload("done"),
+			Scenario:      "export",
+			RequiredState: "done",
+		})
+	t.Cleanup(func() {
+		stubServer.RemoveStub("export-pending")
+		stubServer.RemoveStub("export-done")
+		stubServer.ResetScenario("export")
+	})
+
+	transportClient.In(t).Send().
+		Message(message.Get("exports", "1").BaseUrl("http://localhost:8102"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusAccepted).Payload("pending"))
+
+	if state := stubServer.ScenarioState("export"); state != "done" {
+		t.Errorf("Unexpected scenario state [%s]", state)
+	}
+
+	for range 2 {
+		transportClient.In(t).Send().
+			Message(message.Get("exports", "1").BaseUrl("http://localhost:8102"))
+		transportClient.In(t).Receive().
+			Message(message.Response(http.StatusOK).Payload("done"))
+	}
+
+	stubServer.ResetScenario("export")
+	transportClient.In(t).Send().
+		Message(message.Get("exports", "1").BaseUrl("http://localhost:8102"))
+	transportClient.In(t).Receive().
+		Message(message.Response(http.StatusAccepted).Payload("pending"))
+}
@@ -0,0 +1,95 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Nothing listens on the port
+func TestExpectConnectionRefused(t *testing.T) {
+	transportClient.In(t).Send().
+		Message(message.Get().BaseUrl("http://localhost:8099"))
+
+	transportClient.In(t).Receive().
+		ExpectError().
+		ConnectionRefused()
+}
+
+// Connection refused after all retries
+func TestExpectConnectionRefusedWithRetries(t *testing.T) {
+	retryClient.In(t).Send().
+		Message(message.Get().BaseUrl("http://localhost:8099"))
+
+	retryClient.In(t).Receive().
+		Attempts(3).
+		ExpectError().
+		ConnectionRefused()
+}
+
+// Server answers after the client timeout
+func TestExpectTimeout(t *testing.T) {
+	release := make(chan struct{})
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		<-release
+	}))
+	defer testServer.Close()
+	defer close(release)
+
+	transportClient.In(t).Send().
+		Message(message.Get().BaseUrl(testServer.URL))
+
+	transportClient.In(t).Receive().
+		ExpectError().
+		Timeout()
+}
+
+// Server certificate is not trusted by the client
+func TestExpectTLSHandshake(t *testing.T) {
+	testServer := httptest.NewTLSServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {}))
+	testServer.Config.ErrorLog = discardLogger
+	defer testServer.Close()
+
+	transportClient.In(t).Send().
+		Message(message.Get().BaseUrl(testServer.URL))
+
+	transportClient.In(t).Receive().
+		ExpectError().
+		TLSHandshake()
+}
+
+// Server closes the connection without answering
+func TestExpectEOF(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		conn, _, _ := writer.(http.Hijacker).Hijack()
+		_ = conn.Close()
+	}))
+	defer testServer.Close()
+
+	transportClient.In(t).Send().
+		Message(message.Get().BaseUrl(testServer.URL))
+
+	transportClient.In(t).Receive().
+		ExpectError().
+		EOF()
+}
+
+// Server resets the connection without answering
+func TestExpectConnectionReset(t *testing.T) {
+	testServer := httptest.NewServer(http.HandlerFunc(func(writer http.ResponseWriter, request *http.Request) {
+		conn, _, _ := writer.(http.Hijacker).Hijack()
+		// linger 0 makes close() send a RST instead of a FIN
+		_ = conn.(*net.TCPConn).SetLinger(0)
+		_ = conn.Close()
+	}))
+	defer testServer.Close()
+
+	transportClient.In(t).Send().
+		Message(message.Get().BaseUrl(testServer.URL))
+
+	transportClient.In(t).Receive().
+		ExpectError().
+		ConnectionReset()
+}
@@ -0,0 +1,69 @@
+package itests
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"testing"
+)
+
+// WebSocket exchange
+// + upgrade validation with subprotocol negotiation
+// + text frames with JSON validation, binary frames
+// + ping/pong & closing handshake
+func TestWebSocketExchange(t *testing.T) {
+	wsClient.In(t).Send().
+		Upgrade(message.Get("chat").
+			Header("Origin", "http://localhost"))
+
+	wsServer.In(t).Receive().
+		Upgrade(message.Get("chat").
+			Header("Origin", "http://localhost").
+			Header("Sec-WebSocket-Protocol", "chat.v2, chat.v1"))
+	wsServer.In(t).Send().
+		Upgrade(message.Response(http.StatusSwitchingProtocols))
+
+	wsClient.In(t).Receive().
+		Upgrade(message.Response(http.StatusSwitchingProtocols).
+			Header("Sec-WebSocket-Protocol", "chat.v1"))
+
+	wsClient.In(t).Send().
+		Frame(message.TextFrame("{\"text\": \"hello\", \"id\": 1}"))
+	wsServer.In(t).Receive().
+		Json().
+		Frame(message.TextFrame("{\"text\": \"hello\", \"id\": \"@ignore@\"}"))
+
+	wsServer.In(t).Send().
+		Frame(message.BinaryFrame([]byte{0x01, 0x02, 0x03}))
+	wsClient.In(t).Receive().
+		Frame(message.BinaryFrame([]byte{0x01, 0x02, 0x03}))
+
+	// pings are answered automatically
+	wsServer.In(t).Send().
+		Frame(message.PingFrame("heartbeat"))
+	wsClient.In(t).Receive().
+		Frame(message.PingFrame("heartbeat"))
+	wsServer.In(t).Receive().
+		Frame(message.PongFrame("heartbeat"))
+
+	// the close frame is echoed back by the peer
+	wsClient.In(t).Send().
+		Frame(message.CloseFrame(1000, "bye"))
+	wsServer.In(t).Receive().
+		Frame(message.CloseFrame(1000, "bye"))
+	wsClient.In(t).Receive().
+		Frame(message.CloseFrame(1000, ""))
+}
+
+// WebSocket upgrade rejected by the server
+func TestWebSocketRejectedUpgrade(t *testing.T) {
+	wsClient.In(t).Send().
+		Upgrade(message.Get("chat"))
+
+	wsServer.In(t).Receive().
+		Upgrade(message.Get("chat"))
+	wsServer.In(t).Send().
+		Upgrade(message.Response(http.StatusForbidden))
+
+	wsClient.In(t).Receive().
+		Upgrade(message.Response(http.StatusForbidden))
+}
@@ -0,0 +1,73 @@
+package message
+
+import (
+	"fmt"
+	"time"
+)
+
+// EventMessage is a single Server-Sent Event, as sent over a text/event-stream response
+type EventMessage struct {
+	EventId    string
+	EventName  string
+	EventData  string
+	EventRetry time.Duration
+}
+
+func Event() *EventMessage {
+	return &EventMessage{}
+}
+
+func (event *EventMessage) Id(id string) *EventMessage {
+	event.EventId = id
+	return event
+}
+
+// Name sets the event type, sent in the 'event' field
+func (event *EventMessage) Name(name string) *EventMessage {
+	event.EventName = name
+	return event
+}
+
+func (event *EventMessage) Data(data string) *EventMessage {
+	event.EventData = data
+	return event
+}
+
+// Retry sets the reconnection time the client should use, sent in the 'retry' field
+func (event *EventMessage) Retry(retry time.Duration) *EventMessage {
+	event.EventRetry = retry
+	return event
+}
+
+func (event *EventMessage) Clone() *EventMessage {
+	return &EventMessage{
+		EventId:    event.EventId,
+		EventName:  event.EventName,
+		EventData:  event.EventData,
+		EventRetry: event.EventRetry,
+	}
+}
+
+func (event *EventMessage) Equals(other *EventMessage) bool {
+	if event.EventId != other.EventId {
+		return false
+	} else if event.EventName != other.EventName {
+		return false
+	} else if event.EventData != other.EventData {
+		return false
+	} else if event.EventRetry != other.EventRetry {
+		return false
+	}
+	return true
+}
+
+func (event *EventMessage) ToString() string {
+	return fmt.Sprintf(
+		"["+
+			"Id: %s, "+
+			"Name: %s, "+
+			"Data: %s, "+
+			"Retry: %s"+
+			"]",
+		event.EventId, event.EventName, event.EventData, event.EventRetry)
+}
@@ -0,0 +1,41 @@
+package message
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEventBuilder(t *testing.T) {
+	actual := Event().
+		Id("42").
+		Name("update").
+		Data("batman!").
+		Retry(3 * time.Second)
+
+	expected := EventMessage{
+		EventId:    "42",
+		EventName:  "update",
+		EventData:  "batman!",
+		EventRetry: 3 * time.Second,
+	}
+
+	if !actual.Equals(&expected) {
+		t.Errorf("Message is not as expected.")
+	}
+}
+
+func TestEventClone(t *testing.T) {
+	message := Event().
+		Id("42").
+		Data("my data")
+
+	clonedMessage := message.Clone()
+
+	if clonedMessage == message {
+		t.Errorf("Message has not been cloned.")
+	}
+
+	if !clonedMessage.Equals(message) {
+		t.Errorf("Messages are not equal.")
+	}
+}
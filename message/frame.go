@@ -0,0 +1,109 @@
+package message
+
+import (
+	"fmt"
+)
+
+type FrameType int
+
+const (
+	TextFrameType FrameType = iota + 1
+	BinaryFrameType
+	PingFrameType
+	PongFrameType
+	CloseFrameType
+)
+
+// FrameMessage is a single WebSocket frame. Fragmented messages are seen as one frame.
+type FrameMessage struct {
+	FrameType    FrameType
+	FramePayload string
+	CloseCode    int
+	CloseReason  string
+}
+
+func TextFrame(payload string) *FrameMessage {
+	return &FrameMessage{FrameType: TextFrameType, FramePayload: payload}
+}
+
+func BinaryFrame(payload []byte) *FrameMessage {
+	return &FrameMessage{FrameType: BinaryFrameType, FramePayload: string(payload)}
+}
+
+func PingFrame(payload string) *FrameMessage {
+	return &FrameMessage{FrameType: PingFrameType, FramePayload: payload}
+}
+
+func PongFrame(payload string) *FrameMessage {
+	return &FrameMessage{FrameType: PongFrameType, FramePayload: payload}
+}
+
+// CloseFrame creates a close frame with a status code as defined by RFC 6455, section 7.4.
+// When validating a received close frame, an empty reason is not validated.
+func CloseFrame(code int, reason string) *FrameMessage {
+	return &FrameMessage{FrameType: CloseFrameType, CloseCode: code, CloseReason: reason}
+}
+
+func (frame *FrameMessage) Clone() *FrameMessage {
+	return &FrameMessage{
+		FrameType:    frame.FrameType,
+		FramePayload: frame.FramePayload,
+		CloseCode:    frame.CloseCode,
+		CloseReason:  frame.CloseReason,
+	}
+}
+
+func (frame *FrameMessage) Equals(other *FrameMessage) bool {
+	if frame.FrameType != other.FrameType {
+		return false
+	} else if frame.FramePayload != other.FramePayload {
+		return false
+	} else if frame.CloseCode != other.CloseCode {
+		return false
+	} else if frame.CloseReason != other.CloseReason {
+		return false
+	}
+	return true
+}
+
+func (frame *FrameMessage) ToString() string {
+	if frame.FrameType == CloseFrameType {
+		return fmt.Sprintf(
+			"["+
+				"Type: %s, "+
+				"Code: %d, "+
+				"Reason: %s"+
+				"]",
+			frame.FrameType, frame.CloseCode, frame.CloseReason)
+	}
+
+	// binary payloads are not printable as they are
+	payloadFormat := "%s"
+	if frame.FrameType == BinaryFrameType {
+		payloadFormat = "%x"
+	}
+
+	return fmt.Sprintf(
+		"["+
+			"Type: %s, "+
+			"Payload: "+payloadFormat+
+			"]",
+		frame.FrameType, frame.FramePayload)
+}
+
+func (frameType FrameType) String() string {
+	switch frameType {
+	case TextFrameType:
+		return "text"
+	case BinaryFrameType:
+		return "binary"
+	case PingFrameType:
+		return "ping"
+	case PongFrameType:
+		return "pong"
+	case CloseFrameType:
+		return "close"
+	default:
+		return "unknown"
+	}
+}
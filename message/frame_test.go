@@ -0,0 +1,33 @@
+package message
+
+import (
+	"testing"
+)
+
+func TestFrameBuilder(t *testing.T) {
+	actual := CloseFrame(1000, "bye")
+
+	expected := FrameMessage{
+		FrameType:   CloseFrameType,
+		CloseCode:   1000,
+		CloseReason: "bye",
+	}
+
+	if !actual.Equals(&expected) {
+		t.Errorf("Message is not as expected.")
+	}
+}
+
+func TestFrameClone(t *testing.T) {
+	message := BinaryFrame([]byte{0x01, 0x02})
+
+	clonedMessage := message.Clone()
+
+	if clonedMessage == message {
+		t.Errorf("Message has not been cloned.")
+	}
+
+	if !clonedMessage.Equals(message) {
+		t.Errorf("Messages are not equal.")
+	}
+}
@@ -0,0 +1,243 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/go-clarum/clarum-core/config"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/internal/lifecycle"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const defaultReportDir = "reports/openapi"
+
+// Coverage lists the operations of a spec and their response status codes, and whether they were exercised
+type Coverage struct {
+	Spec               string              `json:"spec"`
+	Operations         []OperationCoverage `json:"operations"`
+	OperationsTotal    int                 `json:"operationsTotal"`
+	OperationsCovered  int                 `json:"operationsCovered"`
+	StatusCodesTotal   int                 `json:"statusCodesTotal"`
+	StatusCodesCovered int                 `json:"statusCodesCovered"`
+}
+
+type OperationCoverage struct {
+	Method      string           `json:"method"`
+	Path        string           `json:"path"`
+	OperationId string           `json:"operationId,omitempty"`
+	Exercised   bool             `json:"exercised"`
+	StatusCodes []StatusCoverage `json:"statusCodes"`
+}
+
+// StatusCoverage is a response of an operation as defined in the spec, like '200', '4XX' or 'default'
+type StatusCoverage struct {
+	Status    string `json:"status"`
+	Exercised bool   `json:"exercised"`
+}
+
+// ReportDir sets the directory the coverage reports are written to. The default is 'reports/openapi'
+// inside the clarum base directory.
+func (spec *Spec) ReportDir(dir string) *Spec {
+	spec.coverageLock.Lock()
+	defer spec.coverageLock.Unlock()
+
+	spec.reportDir = dir
+	return spec
+}
+
+// TrackCoverage makes the spec report its coverage when the test run finishes. It is called by every client
+// endpoint the spec is attached to; the report is written only once.
+func (spec *Spec) TrackCoverage() {
+	spec.trackOnce.Do(func() {
+		lifecycle.OnFinish(spec.reportCoverage)
+	})
+}
+
+// Record marks the operation matching the request, and the response to the status code, as exercised.
+// Requests without a matching operation are not recorded.
+func (spec *Spec) Record(request *http.Request, statusCode int) {
+	route, _, err := spec.findRoute(request)
+	if err != nil {
+		return
+	}
+
+	spec.coverageLock.Lock()
+	defer spec.coverageLock.Unlock()
+
+	key := operationKey(route.Method, route.Path)
+	if spec.exercised[key] == nil {
+		spec.exercised[key] = make(map[string]bool)
+	}
+	if status := matchingStatus(route.Operation.Responses.Map(), statusCode); status != "" {
+		spec.exercised[key][status] = true
+	}
+	spec.coverageChanged = true
+}
+
+// Coverage returns the coverage of all operations in the spec, sorted by path & method
+func (spec *Spec) Coverage() *Coverage {
+	spec.coverageLock.Lock()
+	defer spec.coverageLock.Unlock()
+
+	coverage := &Coverage{Spec: spec.name}
+
+	for _, path := range spec.document.Paths.InMatchingOrder() {
+		for method, operation := range spec.document.Paths.Value(path).Operations() {
+			exercised, operationExercised := spec.exercised[operationKey(method, path)]
+			operationCoverage := OperationCoverage{
+				Method:      method,
+				Path:        path,
+				OperationId: operation.OperationID,
+				Exercised:   operationExercised,
+			}
+
+			for status := range operation.Responses.Map() {
+				operationCoverage.StatusCodes = append(operationCoverage.StatusCodes,
+					StatusCoverage{Status: status, Exercised: exercised[status]})
+				coverage.StatusCodesTotal++
+				if exercised[status] {
+					coverage.StatusCodesCovered++
+				}
+			}
+			sort.Slice(operationCoverage.StatusCodes, func(i, j int) bool {
+				return operationCoverage.StatusCodes[i].Status < operationCoverage.StatusCodes[j].Status
+			})
+
+			coverage.Operations = append(coverage.Operations, operationCoverage)
+			coverage.OperationsTotal++
+			if operationExercised {
+				coverage.OperationsCovered++
+			}
+		}
+	}
+
+	sort.Slice(coverage.Operations, func(i, j int) bool {
+		if coverage.Operations[i].Path != coverage.Operations[j].Path {
+			return coverage.Operations[i].Path < coverage.Operations[j].Path
+		}
+		return coverage.Operations[i].Method < coverage.Operations[j].Method
+	})
+
+	return coverage
+}
+
+// String is the human-readable report. The untested operations, and the untested status codes
+// of the exercised operations, are listed at the end.
+func (coverage *Coverage) String() string {
+	var builder strings.Builder
+
+	fmt.Fprintf(&builder, "OpenAPI coverage [%s]: %d/%d operations, %d/%d status codes\n", coverage.Spec,
+		coverage.OperationsCovered, coverage.OperationsTotal, coverage.StatusCodesCovered, coverage.StatusCodesTotal)
+
+	var untested []string
+	for _, operation := range coverage.Operations {
+		fmt.Fprintf(&builder, "  %s %s %s%s\n", checkbox(operation.Exercised), operation.Method, operation.Path,
+			operationIdSuffix(operation.OperationId))
+		if !operation.Exercised {
+			untested = append(untested, fmt.Sprintf("%s %s", operation.Method, operation.Path))
+		}
+		for _, status := range operation.StatusCodes {
+			fmt.Fprintf(&builder, "      %s %s\n", checkbox(status.Exercised), status.Status)
+			if operation.Exercised && !status.Exercised {
+				untested = append(untested, fmt.Sprintf("%s %s -> %s", operation.Method, operation.Path, status.Status))
+			}
+		}
+	}
+
+	if len(untested) > 0 {
+		builder.WriteString("untested:\n")
+		for _, line := range untested {
+			fmt.Fprintf(&builder, "  %s\n", line)
+		}
+	}
+
+	return builder.String()
+}
+
+// SaveCoverage writes the reports into the report dir, if exchanges were recorded since they were last written.
+// Client endpoints call it at the end of every test, so that the reports are up-to-date even if the test run
+// does not end with clarumhttp.Finish().
+func (spec *Spec) SaveCoverage() error {
+	spec.coverageLock.Lock()
+	changed := spec.coverageChanged
+	spec.coverageChanged = false
+	spec.coverageLock.Unlock()
+	if !changed {
+		return nil
+	}
+
+	return spec.writeCoverage(spec.Coverage())
+}
+
+// reportCoverage logs the human-readable report & writes it, together with the JSON report, into the report dir
+func (spec *Spec) reportCoverage() error {
+	coverage := spec.Coverage()
+	logging.Info(coverage.String())
+
+	return spec.writeCoverage(coverage)
+}
+
+func (spec *Spec) writeCoverage(coverage *Coverage) error {
+	report := coverage.String()
+
+	spec.coverageLock.Lock()
+	reportDir := spec.reportDir
+	spec.coverageLock.Unlock()
+	if reportDir == "" {
+		reportDir = filepath.Join(config.BaseDir(), defaultReportDir)
+	}
+
+	jsonReport, _ := json.MarshalIndent(coverage, "", "  ")
+	if err := os.MkdirAll(reportDir, 0755); err != nil {
+		return fmt.Errorf("could not create OpenAPI coverage report dir - %w", err)
+	}
+	for fileName, data := range map[string][]byte{
+		spec.name + "-coverage.json": jsonReport,
+		spec.name + "-coverage.txt":  []byte(report),
+	} {
+		if err := os.WriteFile(filepath.Join(reportDir, fileName), data, 0644); err != nil {
+			return fmt.Errorf("could not write OpenAPI coverage report - %w", err)
+		}
+	}
+	return nil
+}
+
+// matchingStatus finds the response defined for the status code: the exact code, a range like '2XX' or the default
+func matchingStatus(responses map[string]*openapi3.ResponseRef, statusCode int) string {
+	exact := strconv.Itoa(statusCode)
+	if _, exists := responses[exact]; exists {
+		return exact
+	}
+	statusRange := exact[:1] + "XX"
+	if _, exists := responses[statusRange]; exists {
+		return statusRange
+	}
+	if _, exists := responses["default"]; exists {
+		return "default"
+	}
+	return ""
+}
+
+func operationKey(method string, path string) string {
+	return method + " " + path
+}
+
+func checkbox(checked bool) string {
+	if checked {
+		return "[x]"
+	}
+	return "[ ]"
+}
+
+func operationIdSuffix(operationId string) string {
+	if operationId == "" {
+		return ""
+	}
+	return " (" + operationId + ")"
+}
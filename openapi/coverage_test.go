@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"encoding/json"
+	"github.com/getkin/kin-openapi/openapi3"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCoverage(t *testing.T) {
+	coveredSpec := MustLoad("testdata/users.yaml")
+
+	getUser, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/v1/users/1", nil)
+	coveredSpec.Record(getUser, http.StatusNotFound)
+	coveredSpec.Record(getUser, http.StatusNotFound)
+	// not part of the spec
+	unknown, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/v1/orders", nil)
+	coveredSpec.Record(unknown, http.StatusOK)
+
+	coverage := coveredSpec.Coverage()
+	if coverage.OperationsTotal != 2 || coverage.OperationsCovered != 1 ||
+		coverage.StatusCodesTotal != 3 || coverage.StatusCodesCovered != 1 {
+		t.Errorf("Unexpected coverage %+v", coverage)
+	}
+
+	report := coverage.String()
+	expectedLines := []string{
+		"OpenAPI coverage [users]: 1/2 operations, 1/3 status codes",
+		"  [ ] POST /users (createUser)",
+		"  [x] GET /users/{id} (getUser)",
+		"      [x] 404",
+		"untested:\n  POST /users\n  GET /users/{id} -> 200\n",
+	}
+	for _, line := range expectedLines {
+		if !strings.Contains(report, line) {
+			t.Errorf("Report does not contain [%s]:\n%s", line, report)
+		}
+	}
+}
+
+func TestCoverageStatusRanges(t *testing.T) {
+	responses := map[string]*openapi3.ResponseRef{"200": {}, "4XX": {}, "default": {}}
+
+	expected := map[int]string{
+		http.StatusOK:                  "200",
+		http.StatusNotFound:            "4XX",
+		http.StatusInternalServerError: "default",
+	}
+	for statusCode, expectedStatus := range expected {
+		if status := matchingStatus(responses, statusCode); status != expectedStatus {
+			t.Errorf("Expected [%s] for %d, but got [%s]", expectedStatus, statusCode, status)
+		}
+	}
+
+	if status := matchingStatus(map[string]*openapi3.ResponseRef{"200": {}}, http.StatusCreated); status != "" {
+		t.Errorf("Expected no status, but got [%s]", status)
+	}
+}
+
+func TestCoverageReport(t *testing.T) {
+	reportDir := t.TempDir()
+	reportedSpec := MustLoad("testdata/users.yaml").ReportDir(reportDir)
+
+	reportedSpec.reportCoverage()
+
+	data, err := os.ReadFile(filepath.Join(reportDir, "users-coverage.json"))
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	coverage := &Coverage{}
+	if err := json.Unmarshal(data, coverage); err != nil || coverage.OperationsTotal != 2 {
+		t.Errorf("Unexpected JSON report %s", data)
+	}
+
+	if _, err := os.Stat(filepath.Join(reportDir, "users-coverage.txt")); err != nil {
+		t.Errorf("Human-readable report expected, but got %s", err)
+	}
+}
+
+func TestSaveCoverageOnlyWhenChanged(t *testing.T) {
+	reportDir := t.TempDir()
+	savedSpec := MustLoad("testdata/users.yaml").ReportDir(reportDir)
+	reportFile := filepath.Join(reportDir, "users-coverage.json")
+
+	if err := savedSpec.SaveCoverage(); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if _, err := os.Stat(reportFile); err == nil {
+		t.Errorf("No report expected before an exchange was recorded")
+	}
+
+	getUser, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/v1/users/1", nil)
+	savedSpec.Record(getUser, http.StatusNotFound)
+	if err := savedSpec.SaveCoverage(); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	data, err := os.ReadFile(reportFile)
+	if err != nil {
+		t.Fatalf("Report expected, but got %s", err)
+	}
+	coverage := &Coverage{}
+	if err := json.Unmarshal(data, coverage); err != nil || coverage.OperationsCovered != 1 {
+		t.Errorf("Unexpected JSON report %s", data)
+	}
+}
@@ -0,0 +1,267 @@
+package openapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	jsonMediaType = "application/json"
+	// generated data stops at this depth, so that recursive schemas end
+	maxGeneratedDepth = 8
+)
+
+// OperationId returns the id of the operation matching the request. Operations without an id are identified
+// by method & path, like 'GET /users/{id}'. An empty id means that no operation matches.
+func (spec *Spec) OperationId(request *http.Request) string {
+	route, _, err := spec.findRoute(request)
+	if err != nil {
+		return ""
+	}
+
+	if route.Operation.OperationID != "" {
+		return route.Operation.OperationID
+	}
+	return operationKey(route.Method, route.Path)
+}
+
+// MockResponse answers the request as defined by its operation. The response is the first successful response
+// of the operation, with the body taken from the examples of the spec or generated from the schema.
+// A request that violates the spec is answered with 400 (Bad Request), a request without a matching operation
+// with 404 (Not Found) or 405 (Method Not Allowed). The violation is the payload of the response.
+func (spec *Spec) MockResponse(request *http.Request) *message.ResponseMessage {
+	route, _, err := spec.findRoute(request)
+	if errors.Is(err, routers.ErrMethodNotAllowed) {
+		return errorResponse(http.StatusMethodNotAllowed, err)
+	} else if err != nil {
+		return errorResponse(http.StatusNotFound, err)
+	}
+
+	if err := spec.ValidateRequest(request); err != nil {
+		return errorResponse(http.StatusBadRequest, err)
+	}
+
+	status, responseRef := successfulResponse(route.Operation.Responses)
+	response := message.Response(status)
+	if responseRef == nil || responseRef.Value == nil {
+		return response
+	}
+
+	for name, header := range responseRef.Value.Headers {
+		if header.Value != nil && header.Value.Required && header.Value.Schema != nil {
+			response.Header(name, fmt.Sprint(generate(header.Value.Schema, 0)))
+		}
+	}
+
+	if mediaType, content := preferredContent(responseRef.Value.Content); content != nil {
+		payload, err := examplePayload(mediaType, content)
+		if err != nil {
+			return errorResponse(http.StatusInternalServerError, err)
+		}
+		response.ContentType(mediaType).Payload(payload)
+	}
+
+	return response
+}
+
+func errorResponse(status int, err error) *message.ResponseMessage {
+	return message.Response(status).
+		ContentType("text/plain").
+		Payload(err.Error())
+}
+
+// successfulResponse picks the lowest 2XX response; without one, the default response is sent as 200 (OK)
+// and otherwise the lowest response defined
+func successfulResponse(responses *openapi3.Responses) (int, *openapi3.ResponseRef) {
+	statuses := make([]string, 0, responses.Len())
+	for status := range responses.Map() {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	for _, status := range statuses {
+		if strings.HasPrefix(status, "2") {
+			return statusCode(status), responses.Value(status)
+		}
+	}
+	if defaultResponse := responses.Default(); defaultResponse != nil {
+		return http.StatusOK, defaultResponse
+	}
+	for _, status := range statuses {
+		return statusCode(status), responses.Value(status)
+	}
+	return http.StatusOK, nil
+}
+
+// ranges like '2XX' are answered with the first code of the range
+func statusCode(status string) int {
+	code, err := strconv.Atoi(strings.ReplaceAll(strings.ToUpper(status), "XX", "00"))
+	if err != nil {
+		return http.StatusOK
+	}
+	return code
+}
+
+func preferredContent(content openapi3.Content) (string, *openapi3.MediaType) {
+	if mediaType := content.Get(jsonMediaType); mediaType != nil {
+		return jsonMediaType, mediaType
+	}
+
+	mediaTypes := make([]string, 0, len(content))
+	for mediaType := range content {
+		mediaTypes = append(mediaTypes, mediaType)
+	}
+	sort.Strings(mediaTypes)
+
+	if len(mediaTypes) == 0 {
+		return "", nil
+	}
+	return mediaTypes[0], content[mediaTypes[0]]
+}
+
+// examplePayload uses the example of the media type, the first of its named examples, the example of the schema
+// or, as the last resort, data generated from the schema
+func examplePayload(mediaType string, content *openapi3.MediaType) (string, error) {
+	var value any
+
+	if content.Example != nil {
+		value = content.Example
+	} else if len(content.Examples) > 0 {
+		names := make([]string, 0, len(content.Examples))
+		for name := range content.Examples {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		if example := content.Examples[names[0]]; example.Value != nil {
+			value = example.Value.Value
+		}
+	}
+	if value == nil && content.Schema != nil {
+		value = generate(content.Schema, 0)
+	}
+
+	if text, isText := value.(string); isText && !strings.Contains(mediaType, "json") {
+		return text, nil
+	}
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("could not create example payload - %w", err)
+	}
+	return string(payload), nil
+}
+
+// generate creates a value that is valid for the schema
+func generate(schemaRef *openapi3.SchemaRef, depth int) any {
+	if schemaRef == nil || schemaRef.Value == nil || depth > maxGeneratedDepth {
+		return nil
+	}
+	schema := schemaRef.Value
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	case len(schema.AllOf) > 0:
+		merged := make(map[string]any)
+		for _, part := range schema.AllOf {
+			if object, isObject := generate(part, depth+1).(map[string]any); isObject {
+				for key, value := range object {
+					merged[key] = value
+				}
+			}
+		}
+		return merged
+	case len(schema.OneOf) > 0:
+		return generate(schema.OneOf[0], depth+1)
+	case len(schema.AnyOf) > 0:
+		return generate(schema.AnyOf[0], depth+1)
+	}
+
+	switch {
+	case schema.Type.Includes("object") || (schema.Type == nil && len(schema.Properties) > 0):
+		object := make(map[string]any)
+		for name, property := range schema.Properties {
+			if value := generate(property, depth+1); value != nil {
+				object[name] = value
+			}
+		}
+		return object
+	case schema.Type.Includes("array"):
+		items := make([]any, 0)
+		count := max(schema.MinItems, 1)
+		if schema.MaxItems != nil {
+			count = min(count, *schema.MaxItems)
+		}
+		for i := uint64(0); i < count; i++ {
+			if item := generate(schema.Items, depth+1); item != nil {
+				items = append(items, item)
+			}
+		}
+		return items
+	case schema.Type.Includes("integer"):
+		return int64(generatedNumber(schema))
+	case schema.Type.Includes("number"):
+		return generatedNumber(schema)
+	case schema.Type.Includes("boolean"):
+		return true
+	case schema.Type.Includes("string"):
+		return generatedString(schema)
+	}
+
+	return nil
+}
+
+func generatedNumber(schema *openapi3.Schema) float64 {
+	if schema.Min != nil {
+		if schema.ExclusiveMin {
+			return *schema.Min + 1
+		}
+		return *schema.Min
+	}
+	if schema.Max != nil && *schema.Max < 1 {
+		return *schema.Max - 1
+	}
+	return 1
+}
+
+func generatedString(schema *openapi3.Schema) string {
+	var value string
+
+	switch schema.Format {
+	case "date":
+		value = "2024-01-01"
+	case "date-time":
+		value = "2024-01-01T00:00:00Z"
+	case "email":
+		value = "user@example.com"
+	case "uuid":
+		value = "3fa85f64-5717-4562-b3fc-2c963f66afa6"
+	case "uri", "url":
+		value = "https://example.com"
+	case "ipv4":
+		value = "127.0.0.1"
+	case "byte":
+		value = "c3RyaW5n"
+	default:
+		value = "string"
+	}
+
+	for uint64(len(value)) < schema.MinLength {
+		value += value
+	}
+	if schema.MaxLength != nil && uint64(len(value)) > *schema.MaxLength {
+		value = value[:*schema.MaxLength]
+	}
+	return value
+}
@@ -0,0 +1,86 @@
+package openapi
+
+import (
+	"github.com/getkin/kin-openapi/openapi3"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestMockResponseFromExample(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/v1/users/1", nil)
+
+	response := spec.MockResponse(request)
+
+	if response.StatusCode != http.StatusOK || response.MessagePayload != "{\"id\":1,\"name\":\"bruce\"}" ||
+		response.Headers["Content-Type"] != "application/json" {
+		t.Errorf("Unexpected response %s", response.ToString())
+	}
+}
+
+func TestMockResponseFromSchema(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/v1/users",
+		strings.NewReader("{\"id\": 1, \"name\": \"bruce\"}"))
+	request.Header.Set("Content-Type", "application/json")
+
+	response := spec.MockResponse(request)
+
+	if response.StatusCode != http.StatusCreated || response.MessagePayload != "{\"id\":1,\"name\":\"string\"}" {
+		t.Errorf("Unexpected response %s", response.ToString())
+	}
+}
+
+func TestMockResponseErrors(t *testing.T) {
+	invalid, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/v1/users", strings.NewReader("{}"))
+	invalid.Header.Set("Content-Type", "application/json")
+	unknownPath, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/v1/orders", nil)
+	unknownMethod, _ := http.NewRequest(http.MethodDelete, "http://localhost:8080/v1/users", nil)
+
+	expected := map[*http.Request]int{
+		invalid:       http.StatusBadRequest,
+		unknownPath:   http.StatusNotFound,
+		unknownMethod: http.StatusMethodNotAllowed,
+	}
+	for request, status := range expected {
+		response := spec.MockResponse(request)
+		if response.StatusCode != status || response.MessagePayload == "" {
+			t.Errorf("Expected status %d with violation, but got %s", status, response.ToString())
+		}
+	}
+}
+
+func TestOperationId(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/v1/users/1", nil)
+	if operationId := spec.OperationId(request); operationId != "getUser" {
+		t.Errorf("Expected [getUser], but got [%s]", operationId)
+	}
+
+	request, _ = http.NewRequest(http.MethodGet, "http://localhost:8080/v1/orders", nil)
+	if operationId := spec.OperationId(request); operationId != "" {
+		t.Errorf("Expected no operation, but got [%s]", operationId)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	minimum := 5.0
+	maxItems := uint64(5)
+	schema := openapi3.NewObjectSchema().
+		WithProperty("created", openapi3.NewDateTimeSchema()).
+		WithProperty("count", &openapi3.Schema{Type: &openapi3.Types{"integer"}, Min: &minimum}).
+		WithProperty("tags", &openapi3.Schema{
+			Type:     &openapi3.Types{"array"},
+			MinItems: 2,
+			MaxItems: &maxItems,
+			Items:    openapi3.NewStringSchema().WithEnum("a", "b").NewRef(),
+		})
+
+	generated := generate(schema.NewRef(), 0).(map[string]any)
+
+	if generated["created"] != "2024-01-01T00:00:00Z" || generated["count"] != int64(5) ||
+		len(generated["tags"].([]any)) != 2 || generated["tags"].([]any)[0] != "a" {
+		t.Errorf("Unexpected generated value %v", generated)
+	}
+	if err := schema.VisitJSON(map[string]any{"created": generated["created"], "count": 5.0, "tags": generated["tags"]}); err != nil {
+		t.Errorf("Generated value does not match the schema - %s", err)
+	}
+}
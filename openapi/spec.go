@@ -0,0 +1,199 @@
+package openapi
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/getkin/kin-openapi/openapi3"
+	"github.com/getkin/kin-openapi/openapi3filter"
+	"github.com/getkin/kin-openapi/routers"
+	"github.com/getkin/kin-openapi/routers/gorillamux"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// Spec is an OpenAPI 3 document that exchanges are validated against. Operations are matched by method & path only;
+// the hosts of the servers defined in the document are ignored, so that the same spec can be used in any environment.
+type Spec struct {
+	name      string
+	document  *openapi3.T
+	router    routers.Router
+	exercised map[string]map[string]bool
+	reportDir string
+	// coverageChanged is set when an exchange was recorded since the reports were last written
+	coverageChanged bool
+	coverageLock    sync.Mutex
+	trackOnce       sync.Once
+}
+
+// Load reads & validates an OpenAPI 3 document, in YAML or JSON format.
+func Load(filePath string) (*Spec, error) {
+	loader := openapi3.NewLoader()
+	loader.IsExternalRefsAllowed = true
+
+	document, err := loader.LoadFromFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not load OpenAPI spec [%s] - %w", filePath, err)
+	}
+	if err := document.Validate(loader.Context); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI spec [%s] - %w", filePath, err)
+	}
+
+	if err := relativeServers(document); err != nil {
+		return nil, fmt.Errorf("invalid servers in OpenAPI spec [%s] - %w", filePath, err)
+	}
+	router, err := gorillamux.NewRouter(document)
+	if err != nil {
+		return nil, fmt.Errorf("could not route OpenAPI spec [%s] - %w", filePath, err)
+	}
+
+	return &Spec{
+		name:      strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath)),
+		document:  document,
+		router:    router,
+		exercised: make(map[string]map[string]bool),
+	}, nil
+}
+
+// MustLoad is like Load but panics if the spec cannot be loaded. It simplifies the initialization of
+// global variables holding endpoints.
+func MustLoad(filePath string) *Spec {
+	spec, err := Load(filePath)
+	if err != nil {
+		panic(err)
+	}
+	return spec
+}
+
+// ValidateRequest validates the request against its operation: path & query parameters, headers and body.
+// The body is read and put back into the request, so that it can be read downstream again.
+func (spec *Spec) ValidateRequest(request *http.Request) error {
+	route, pathParams, err := spec.findRoute(request)
+	if err != nil {
+		return err
+	}
+
+	body, err := readBody(&request.Body)
+	if err != nil {
+		return err
+	}
+
+	validationRequest := request.Clone(request.Context())
+	validationRequest.Body = io.NopCloser(bytes.NewReader(body))
+
+	err = openapi3filter.ValidateRequest(context.Background(), &openapi3filter.RequestValidationInput{
+		Request:    validationRequest,
+		PathParams: pathParams,
+		Route:      route,
+		Options:    validationOptions(),
+	})
+	if err != nil {
+		return fmt.Errorf("request [%s %s] - %w", request.Method, request.URL.Path, err)
+	}
+	return nil
+}
+
+// ValidateResponse validates the response against the operation of the request that was answered: status code,
+// headers and body. The body is read and put back into the response, so that it can be read downstream again.
+func (spec *Spec) ValidateResponse(response *http.Response) error {
+	route, pathParams, err := spec.findRoute(response.Request)
+	if err != nil {
+		return err
+	}
+
+	body, err := readBody(&response.Body)
+	if err != nil {
+		return err
+	}
+
+	options := validationOptions()
+	err = openapi3filter.ValidateResponse(context.Background(), &openapi3filter.ResponseValidationInput{
+		RequestValidationInput: &openapi3filter.RequestValidationInput{
+			Request:    response.Request,
+			PathParams: pathParams,
+			Route:      route,
+			Options:    options,
+		},
+		Status:  response.StatusCode,
+		Header:  response.Header,
+		Body:    io.NopCloser(bytes.NewReader(body)),
+		Options: options,
+	})
+	if err != nil {
+		return fmt.Errorf("response with status [%d] to [%s %s] - %w",
+			response.StatusCode, response.Request.Method, response.Request.URL.Path, err)
+	}
+	return nil
+}
+
+func (spec *Spec) findRoute(request *http.Request) (*routers.Route, map[string]string, error) {
+	if request == nil {
+		return nil, nil, errors.New("no request to match an operation")
+	}
+
+	route, pathParams, err := spec.router.FindRoute(request)
+	if err != nil {
+		return nil, nil, fmt.Errorf("no operation found for [%s %s] - %w", request.Method, request.URL.Path, err)
+	}
+	return route, pathParams, nil
+}
+
+// all violations are reported, not just the first one; authentication is not part of the contract validation
+func validationOptions() *openapi3filter.Options {
+	return &openapi3filter.Options{
+		MultiError:            true,
+		IncludeResponseStatus: true,
+		AuthenticationFunc:    openapi3filter.NoopAuthenticationFunc,
+	}
+}
+
+func readBody(body *io.ReadCloser) ([]byte, error) {
+	if *body == nil || *body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(*body)
+	if err != nil {
+		return nil, fmt.Errorf("could not read body - %w", err)
+	}
+	*body = io.NopCloser(bytes.NewReader(data))
+
+	return data, nil
+}
+
+// relativeServers keeps only the base paths of the servers, with the default values of their variables
+func relativeServers(document *openapi3.T) error {
+	servers, err := basePaths(document.Servers)
+	if err != nil {
+		return err
+	}
+	document.Servers = servers
+
+	for _, pathItem := range document.Paths.Map() {
+		if pathItem.Servers, err = basePaths(pathItem.Servers); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func basePaths(servers openapi3.Servers) (openapi3.Servers, error) {
+	var result openapi3.Servers
+	seen := make(map[string]bool)
+
+	for _, server := range servers {
+		basePath, err := server.BasePath()
+		if err != nil {
+			return nil, err
+		}
+		if !seen[basePath] {
+			seen[basePath] = true
+			result = append(result, &openapi3.Server{URL: basePath})
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,80 @@
+package openapi
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var spec = MustLoad("testdata/users.yaml")
+
+func TestValidRequest(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/v1/users",
+		strings.NewReader("{\"id\": 1, \"name\": \"bruce\"}"))
+	request.Header.Set("Content-Type", "application/json")
+
+	if err := spec.ValidateRequest(request); err != nil {
+		t.Errorf("No error expected, but got %s", err)
+	}
+
+	body, _ := io.ReadAll(request.Body)
+	if string(body) != "{\"id\": 1, \"name\": \"bruce\"}" {
+		t.Errorf("Request body was not restored: %s", body)
+	}
+}
+
+func TestInvalidRequest(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodPost, "http://localhost:8080/v1/users",
+		strings.NewReader("{\"id\": \"one\"}"))
+	request.Header.Set("Content-Type", "application/json")
+
+	err := spec.ValidateRequest(request)
+	if err == nil {
+		t.Fatalf("Error expected, but got none")
+	}
+	if !strings.Contains(err.Error(), "name") || !strings.Contains(err.Error(), "id") {
+		t.Errorf("All violations expected, but got %s", err)
+	}
+}
+
+func TestInvalidPathParam(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/v1/users/bruce?details=true", nil)
+
+	err := spec.ValidateRequest(request)
+	if err == nil || !strings.Contains(err.Error(), "parameter \"id\" in path") {
+		t.Errorf("Path parameter error expected, but got %s", err)
+	}
+}
+
+func TestUnknownOperation(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodDelete, "http://localhost:8080/v1/users", nil)
+
+	err := spec.ValidateRequest(request)
+	if err == nil || !strings.HasPrefix(err.Error(), "no operation found for [DELETE /v1/users]") {
+		t.Errorf("Unknown operation error expected, but got %s", err)
+	}
+}
+
+func TestValidateResponse(t *testing.T) {
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost:8080/v1/users/1", nil)
+	response := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader("{\"id\": 1, \"name\": \"bruce\"}")),
+		Request:    request,
+	}
+	if err := spec.ValidateResponse(response); err != nil {
+		t.Errorf("No error expected, but got %s", err)
+	}
+
+	response.Body = io.NopCloser(strings.NewReader("{\"id\": 1}"))
+	if err := spec.ValidateResponse(response); err == nil || !strings.Contains(err.Error(), "name") {
+		t.Errorf("Missing property error expected, but got %s", err)
+	}
+
+	response.StatusCode = http.StatusInternalServerError
+	if err := spec.ValidateResponse(response); err == nil || !strings.Contains(err.Error(), "status [500]") {
+		t.Errorf("Undefined status error expected, but got %s", err)
+	}
+}
@@ -0,0 +1,193 @@
+package properties
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-http/client"
+	"github.com/go-clarum/clarum-http/constants"
+	"github.com/go-clarum/clarum-http/server"
+	"net/http"
+	"os"
+	"path"
+	"time"
+)
+
+// ClientDefinition describes a client endpoint, with the timeout as a duration like '2s'
+type ClientDefinition struct {
+	BaseUrl     string          `yaml:"baseUrl"`
+	ContentType string          `yaml:"contentType"`
+	Timeout     string          `yaml:"timeout"`
+	Retries     uint            `yaml:"retries"`
+	Tls         *TlsDefinition  `yaml:"tls"`
+	Auth        *AuthDefinition `yaml:"auth"`
+}
+
+// ServerDefinition describes a server endpoint, with the timeout as a duration like '2s'
+type ServerDefinition struct {
+	Port        uint           `yaml:"port"`
+	ContentType string         `yaml:"contentType"`
+	Timeout     string         `yaml:"timeout"`
+	Tls         *TlsDefinition `yaml:"tls"`
+}
+
+// TlsDefinition configures TLS. Clients trust the CA file and present the certificate to servers requiring
+// client certificates. Servers serve the certificate, or a self-signed certificate for localhost if there is none.
+type TlsDefinition struct {
+	CaFile             string `yaml:"caFile"`
+	CertFile           string `yaml:"certFile"`
+	KeyFile            string `yaml:"keyFile"`
+	InsecureSkipVerify bool   `yaml:"insecureSkipVerify"`
+}
+
+// AuthDefinition sets the Authorization header of every request a client sends, unless the request
+// already has one: basic authentication with a username & password, or a bearer token
+type AuthDefinition struct {
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	Token    string `yaml:"token"`
+}
+
+func (definition *ClientDefinition) builder(name string, dir string) (*client.EndpointBuilder, error) {
+	builder := client.NewEndpointBuilder().
+		Name(name).
+		BaseUrl(definition.BaseUrl).
+		ContentType(definition.ContentType)
+
+	timeout, err := parseTimeout(definition.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	builder.Timeout(timeout)
+
+	if definition.Retries > 0 {
+		builder.Retries(definition.Retries)
+	}
+	if definition.Tls != nil {
+		tlsConfig, err := definition.Tls.clientConfig(dir)
+		if err != nil {
+			return nil, err
+		}
+		builder.TLSConfig(tlsConfig)
+	}
+	if definition.Auth != nil {
+		interceptor, err := definition.Auth.interceptor()
+		if err != nil {
+			return nil, err
+		}
+		builder.BeforeSend(interceptor)
+	}
+
+	return builder, nil
+}
+
+func (definition *ServerDefinition) builder(name string, dir string) (*server.EndpointBuilder, error) {
+	builder := server.NewEndpointBuilder().
+		Name(name).
+		Port(definition.Port).
+		ContentType(definition.ContentType)
+
+	timeout, err := parseTimeout(definition.Timeout)
+	if err != nil {
+		return nil, err
+	}
+	builder.Timeout(timeout)
+
+	if definition.Tls != nil {
+		tlsConfig, err := definition.Tls.serverConfig(dir)
+		if err != nil {
+			return nil, err
+		}
+		builder.TLSConfig(tlsConfig)
+	}
+
+	return builder, nil
+}
+
+func (definition *TlsDefinition) clientConfig(dir string) (*tls.Config, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: definition.InsecureSkipVerify}
+
+	if definition.CaFile != "" {
+		caCert, err := os.ReadFile(resolve(dir, definition.CaFile))
+		if err != nil {
+			return nil, fmt.Errorf("unable to read CA file - %w", err)
+		}
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCert) {
+			return nil, fmt.Errorf("no certificates found in CA file [%s]", definition.CaFile)
+		}
+	}
+
+	certificates, err := definition.certificates(dir)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig.Certificates = certificates
+
+	return tlsConfig, nil
+}
+
+func (definition *TlsDefinition) serverConfig(dir string) (*tls.Config, error) {
+	certificates, err := definition.certificates(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &tls.Config{Certificates: certificates}, nil
+}
+
+func (definition *TlsDefinition) certificates(dir string) ([]tls.Certificate, error) {
+	if definition.CertFile == "" && definition.KeyFile == "" {
+		return nil, nil
+	}
+	if definition.CertFile == "" || definition.KeyFile == "" {
+		return nil, errors.New("certFile & keyFile must be set together")
+	}
+
+	certificate, err := tls.LoadX509KeyPair(resolve(dir, definition.CertFile), resolve(dir, definition.KeyFile))
+	if err != nil {
+		return nil, fmt.Errorf("unable to load certificate - %w", err)
+	}
+	return []tls.Certificate{certificate}, nil
+}
+
+func (definition *AuthDefinition) interceptor() (client.RequestInterceptor, error) {
+	var authorization string
+	switch {
+	case definition.Token != "" && definition.Username != "":
+		return nil, errors.New("auth must have either a token or a username, not both")
+	case definition.Token != "":
+		authorization = "Bearer " + definition.Token
+	case definition.Username != "":
+		credentials := definition.Username + ":" + definition.Password
+		authorization = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+	default:
+		return nil, errors.New("auth must have a token or a username")
+	}
+
+	return func(request *http.Request) error {
+		if request.Header.Get(constants.AuthorizationHeaderName) == "" {
+			request.Header.Set(constants.AuthorizationHeaderName, authorization)
+		}
+		return nil
+	}, nil
+}
+
+func parseTimeout(timeout string) (time.Duration, error) {
+	if timeout == "" {
+		return 0, nil
+	}
+	duration, err := time.ParseDuration(timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid timeout - %w", err)
+	}
+	return duration, nil
+}
+
+func resolve(dir string, file string) string {
+	if path.IsAbs(file) {
+		return file
+	}
+	return path.Join(dir, file)
+}
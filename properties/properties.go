@@ -0,0 +1,281 @@
+package properties
+
+import (
+	"flag"
+	"fmt"
+	"github.com/go-clarum/clarum-core/config"
+	"github.com/go-clarum/clarum-http/client"
+	"github.com/go-clarum/clarum-http/server"
+	"gopkg.in/yaml.v3"
+	"os"
+	"path"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// ProfileEnvVar selects the active profile when the '-clm-profile' flag of clarum-core is not passed.
+// Without both, the 'profile' of the file is active.
+const ProfileEnvVar = "CLARUM_PROFILE"
+
+const defaultConfigFile = "clarum-properties.yaml"
+
+// ${NAME} or ${NAME:default}
+var envVarPattern = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::([^}]*))?}`)
+
+var loadOnce = sync.OnceValues(loadDefault)
+
+// Properties are the endpoint definitions of the 'http' section of the config file,
+// with the overrides of the active profile applied
+type Properties struct {
+	Profile string `yaml:"-"`
+	// relative files, like certificates, are resolved against the directory of the config file
+	dir     string
+	Clients map[string]ClientDefinition `yaml:"clients"`
+	Servers map[string]ServerDefinition `yaml:"servers"`
+}
+
+// fileContent is the part of the config file relevant for HTTP endpoints
+type fileContent struct {
+	Profile string         `yaml:"profile"`
+	Http    map[string]any `yaml:"http"`
+}
+
+// Default returns the properties of the config file loaded by clarum-core: 'clarum-properties.yaml' in the
+// base directory, unless specified otherwise by the core flags. Endpoints are usually built while global variables
+// are initialized, before the flags are parsed; until then the core flags are read from the command line arguments
+// and the file is read on every call. Once the flags are parsed, the file is only read once.
+func Default() (*Properties, error) {
+	if !flag.Parsed() {
+		return loadDefault()
+	}
+	return loadOnce()
+}
+
+func loadDefault() (*Properties, error) {
+	return Load(configFilePath())
+}
+
+// Load reads the endpoint definitions from a config file. Environment variables written as ${NAME} or
+// ${NAME:default} are substituted in the values of the file, after it is parsed; a variable that is neither set
+// nor has a default is an error. Unquoted values keep their type, so '${PORT:8083}' can be used as a port.
+// The definitions under 'http.profiles.<active profile>' override the base definitions.
+func Load(filePath string) (*Properties, error) {
+	content, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read config file [%s] - %w", filePath, err)
+	}
+
+	document := yaml.Node{}
+	if err := yaml.Unmarshal(content, &document); err != nil {
+		return nil, fmt.Errorf("invalid config file [%s] - %w", filePath, err)
+	}
+
+	var missing []string
+	substituteNode(&document, &missing)
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("invalid config file [%s] - %w", filePath, missingEnvVars(missing))
+	}
+
+	file := fileContent{}
+	if err := document.Decode(&file); err != nil {
+		return nil, fmt.Errorf("invalid config file [%s] - %w", filePath, err)
+	}
+
+	profile := activeProfile(file.Profile)
+	definitions, err := applyProfile(file.Http, profile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid profile [%s] in config file [%s] - %w", profile, filePath, err)
+	}
+
+	// the merged definitions are decoded again into the typed structs
+	merged, err := yaml.Marshal(definitions)
+	if err != nil {
+		return nil, fmt.Errorf("invalid config file [%s] - %w", filePath, err)
+	}
+	properties := &Properties{}
+	if err := yaml.Unmarshal(merged, properties); err != nil {
+		return nil, fmt.Errorf("invalid endpoint definitions in config file [%s] - %w", filePath, err)
+	}
+	properties.Profile = profile
+	properties.dir = path.Dir(filePath)
+
+	return properties, nil
+}
+
+// Client returns a builder of the client with the given name, configured from its definition.
+// The builder can be used to set options that cannot be defined in the file, like interceptors.
+func (properties *Properties) Client(name string) (*client.EndpointBuilder, error) {
+	definition, exists := properties.Clients[name]
+	if !exists {
+		return nil, fmt.Errorf("no client [%s] defined for profile [%s]", name, properties.Profile)
+	}
+
+	builder, err := definition.builder(name, properties.dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid definition of client [%s] - %w", name, err)
+	}
+	return builder, nil
+}
+
+// Server returns a builder of the server with the given name, configured from its definition
+func (properties *Properties) Server(name string) (*server.EndpointBuilder, error) {
+	definition, exists := properties.Servers[name]
+	if !exists {
+		return nil, fmt.Errorf("no server [%s] defined for profile [%s]", name, properties.Profile)
+	}
+
+	builder, err := definition.builder(name, properties.dir)
+	if err != nil {
+		return nil, fmt.Errorf("invalid definition of server [%s] - %w", name, err)
+	}
+	return builder, nil
+}
+
+// substituteNode substitutes the environment variables in all scalar values of the node. The tag of a substituted
+// value is resolved again, so that an unquoted '${PORT}' becomes a number; quoted values stay strings.
+func substituteNode(node *yaml.Node, missing *[]string) {
+	if node.Kind == yaml.ScalarNode {
+		if substituted := substitute(node.Value, missing); substituted != node.Value {
+			node.Value = substituted
+			node.Tag = ""
+		}
+		return
+	}
+	for _, child := range node.Content {
+		substituteNode(child, missing)
+	}
+}
+
+func substituteEnvVars(value string) (string, error) {
+	var missing []string
+	result := substitute(value, &missing)
+	if len(missing) > 0 {
+		return "", missingEnvVars(missing)
+	}
+	return result, nil
+}
+
+func substitute(value string, missing *[]string) string {
+	return envVarPattern.ReplaceAllStringFunc(value, func(match string) string {
+		groups := envVarPattern.FindStringSubmatchIndex(match)
+		name := match[groups[2]:groups[3]]
+		if value, exists := os.LookupEnv(name); exists {
+			return value
+		}
+		// the default may be empty, as in ${NAME:}
+		if groups[4] != -1 {
+			return match[groups[4]:groups[5]]
+		}
+		*missing = append(*missing, name)
+		return match
+	})
+}
+
+func missingEnvVars(missing []string) error {
+	return fmt.Errorf("environment variables %v are not set", missing)
+}
+
+func activeProfile(fileProfile string) string {
+	if profile := profileFlag(); profile != "" {
+		return profile
+	}
+	if profile := os.Getenv(ProfileEnvVar); profile != "" {
+		return profile
+	}
+	if fileProfile != "" {
+		return fileProfile
+	}
+	return "dev"
+}
+
+// profileFlag returns the '-clm-profile' flag of clarum-core, only when it was passed
+func profileFlag() string {
+	profile, _ := coreFlag("clm-profile")
+	return profile
+}
+
+// coreFlag returns the value of a flag of clarum-core and whether it was passed. Before the flags are parsed,
+// the value is read from the command line arguments.
+func coreFlag(name string) (string, bool) {
+	if !flag.Parsed() {
+		return commandLineFlag(os.Args[1:], name)
+	}
+
+	value, passed := "", false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			value, passed = f.Value.String(), true
+		}
+	})
+	return value, passed
+}
+
+// commandLineFlag finds a flag in the arguments, written as -name=value, -name value or with two dashes
+func commandLineFlag(args []string, name string) (string, bool) {
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		trimmed := strings.TrimPrefix(strings.TrimPrefix(arg, "-"), "-")
+		if trimmed == arg {
+			continue
+		}
+		if value, found := strings.CutPrefix(trimmed, name+"="); found {
+			return value, true
+		}
+		if trimmed == name && i+1 < len(args) {
+			return args[i+1], true
+		}
+	}
+	return "", false
+}
+
+// applyProfile merges the definitions of the profile into the base definitions
+func applyProfile(definitions map[string]any, profile string) (map[string]any, error) {
+	profiles, _ := definitions["profiles"].(map[string]any)
+	delete(definitions, "profiles")
+
+	overrides, exists := profiles[profile]
+	if !exists || overrides == nil {
+		return definitions, nil
+	}
+	overridesMap, ok := overrides.(map[string]any)
+	if !ok {
+		return nil, fmt.Errorf("profile must be a mapping of clients & servers")
+	}
+
+	return merge(definitions, overridesMap), nil
+}
+
+// merge overrides the values of base recursively; values that are not mappings are replaced
+func merge(base map[string]any, overrides map[string]any) map[string]any {
+	if base == nil {
+		base = make(map[string]any)
+	}
+	for key, override := range overrides {
+		baseMap, baseIsMap := base[key].(map[string]any)
+		overrideMap, overrideIsMap := override.(map[string]any)
+		if baseIsMap && overrideIsMap {
+			base[key] = merge(baseMap, overrideMap)
+		} else {
+			base[key] = override
+		}
+	}
+	return base
+}
+
+func configFilePath() string {
+	configFile := defaultConfigFile
+	if passed, exists := coreFlag("clm-config"); exists {
+		configFile = passed
+	} else if configFlag := flag.Lookup("clm-config"); configFlag != nil {
+		configFile = configFlag.Value.String()
+	}
+
+	baseDir := config.BaseDir()
+	if passed, exists := coreFlag("clm-basedir"); exists {
+		baseDir = passed
+	}
+	return path.Join(baseDir, configFile)
+}
@@ -0,0 +1,177 @@
+package properties
+
+import (
+	"flag"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLoad(t *testing.T) {
+	// the value is substituted after parsing, so YAML syntax in it is kept as is
+	t.Setenv("USERS_PASSWORD", "alfred # butler: yes")
+
+	properties, err := Load("testdata/clarum-properties.yaml")
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	if properties.Profile != "dev" {
+		t.Errorf("Unexpected profile [%s]", properties.Profile)
+	}
+	userClient := properties.Clients["userClient"]
+	if userClient.BaseUrl != "http://localhost:8083/myApp" || userClient.Timeout != "2s" ||
+		userClient.Auth.Password != "alfred # butler: yes" {
+		t.Errorf("Unexpected client definition %+v", userClient)
+	}
+	if properties.Servers["userService"].Port != 8083 {
+		t.Errorf("Unexpected server definition %+v", properties.Servers["userService"])
+	}
+}
+
+func TestProfile(t *testing.T) {
+	t.Setenv("USERS_PASSWORD", "alfred")
+	t.Setenv("USERS_HOST", "users.local")
+	t.Setenv(ProfileEnvVar, "ci")
+
+	properties, err := Load("testdata/clarum-properties.yaml")
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	userClient := properties.Clients["userClient"]
+	if userClient.BaseUrl != "http://users:8080/myApp" || userClient.ContentType != "application/json" {
+		t.Errorf("Profile was not merged into client definition %+v", userClient)
+	}
+	if properties.Servers["userService"].Port != 9083 || properties.Servers["userService"].Timeout != "1s" {
+		t.Errorf("Profile was not merged into server definition %+v", properties.Servers["userService"])
+	}
+}
+
+func TestProfileFlag(t *testing.T) {
+	t.Setenv("USERS_PASSWORD", "alfred")
+	t.Setenv("USERS_HOST", "users.local")
+	t.Setenv(ProfileEnvVar, "unknown")
+
+	commandLine := flag.CommandLine
+	t.Cleanup(func() { flag.CommandLine = commandLine })
+	flag.CommandLine = flag.NewFlagSet("test", flag.ContinueOnError)
+	flag.String("clm-profile", "dev", "")
+	if err := flag.CommandLine.Parse([]string{"-clm-profile", "ci"}); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	properties, err := Load("testdata/clarum-properties.yaml")
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if properties.Profile != "ci" || properties.Servers["userService"].Port != 9083 {
+		t.Errorf("Profile flag was not applied %+v", properties)
+	}
+}
+
+func TestDefault(t *testing.T) {
+	t.Setenv("USERS_PASSWORD", "alfred")
+	t.Setenv(ProfileEnvVar, "")
+
+	commandLine, args := flag.CommandLine, os.Args
+	t.Cleanup(func() {
+		flag.CommandLine, os.Args = commandLine, args
+		loadOnce = sync.OnceValues(loadDefault)
+	})
+	loadOnce = sync.OnceValues(loadDefault)
+	flag.CommandLine = flag.NewFlagSet("test", flag.ContinueOnError)
+	flag.String("clm-basedir", ".", "")
+	flag.String("clm-config", defaultConfigFile, "")
+	flag.String("clm-profile", "dev", "")
+
+	// before the flags are parsed, like when global variables are initialized
+	os.Args = []string{"test", "-test.v", "-clm-basedir", "testdata", "--clm-profile=ci"}
+	properties, err := Default()
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if properties.Profile != "ci" || properties.Servers["userService"].Port != 9083 {
+		t.Errorf("Flags of the command line were not applied %+v", properties)
+	}
+
+	if err := flag.CommandLine.Parse([]string{"-clm-basedir", "testdata"}); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	properties, err = Default()
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if properties.Profile != "dev" || properties.Servers["userService"].Port != 8083 {
+		t.Errorf("Parsed flags were not applied %+v", properties)
+	}
+	if cached, _ := Default(); cached != properties {
+		t.Errorf("Properties were not cached once the flags were parsed")
+	}
+}
+
+func TestMissingEnvVar(t *testing.T) {
+	_, err := Load("testdata/clarum-properties.yaml")
+	if err == nil || !strings.Contains(err.Error(), "environment variables [USERS_PASSWORD] are not set") {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
+
+func TestSubstituteEnvVars(t *testing.T) {
+	t.Setenv("CLARUM_TEST_PORT", "8090")
+
+	result, err := substituteEnvVars("${CLARUM_TEST_PORT} ${CLARUM_TEST_MISSING:8091} [${CLARUM_TEST_MISSING:}] $HOME")
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if result != "8090 8091 [] $HOME" {
+		t.Errorf("Unexpected result [%s]", result)
+	}
+}
+
+func TestAuth(t *testing.T) {
+	basic, _ := (&AuthDefinition{Username: "bruce", Password: "alfred"}).interceptor()
+	request, _ := http.NewRequest(http.MethodGet, "http://localhost", nil)
+	_ = basic(request)
+	if username, password, ok := request.BasicAuth(); !ok || username != "bruce" || password != "alfred" {
+		t.Errorf("Unexpected basic auth header [%s]", request.Header.Get("Authorization"))
+	}
+
+	bearer, _ := (&AuthDefinition{Token: "secret"}).interceptor()
+	request.Header.Set("Authorization", "Bearer other")
+	_ = bearer(request)
+	if request.Header.Get("Authorization") != "Bearer other" {
+		t.Errorf("Existing authorization header was overwritten")
+	}
+
+	if _, err := (&AuthDefinition{Token: "secret", Username: "bruce"}).interceptor(); err == nil {
+		t.Errorf("Error expected for token & username")
+	}
+}
+
+func TestUnknownEndpoint(t *testing.T) {
+	t.Setenv("USERS_PASSWORD", "alfred")
+	properties, _ := Load("testdata/clarum-properties.yaml")
+
+	if _, err := properties.Client("paymentClient"); err == nil ||
+		err.Error() != "no client [paymentClient] defined for profile [dev]" {
+		t.Errorf("Unexpected error %v", err)
+	}
+	if _, err := properties.Client("orderClient"); err != nil {
+		t.Errorf("No error expected, but got %s", err)
+	}
+}
+
+func TestInvalidDefinition(t *testing.T) {
+	properties := &Properties{
+		Profile: "dev",
+		Servers: map[string]ServerDefinition{"userService": {Port: 8083, Timeout: "soon"}},
+	}
+
+	if _, err := properties.Server("userService"); err == nil ||
+		!strings.HasPrefix(err.Error(), "invalid definition of server [userService] - invalid timeout") {
+		t.Errorf("Unexpected error %v", err)
+	}
+}
@@ -0,0 +1,108 @@
+package proxy
+
+import (
+	"fmt"
+	"github.com/go-clarum/clarum-http/fixtures"
+	"github.com/go-clarum/clarum-http/internal/lifecycle"
+	"github.com/go-clarum/clarum-http/registry"
+	"regexp"
+	"time"
+)
+
+type EndpointBuilder struct {
+	port      uint
+	name      string
+	upstream  string
+	timeout   time.Duration
+	recordDir string
+	redaction fixtures.Redaction
+	// bodyPatterns are compiled when the endpoint is built
+	bodyPatterns []string
+	registry     *registry.Registry
+}
+
+func NewEndpointBuilder() *EndpointBuilder {
+	return &EndpointBuilder{}
+}
+
+func (builder *EndpointBuilder) Name(name string) *EndpointBuilder {
+	builder.name = name
+	return builder
+}
+
+// Port on which the system under test reaches the proxy
+func (builder *EndpointBuilder) Port(port uint) *EndpointBuilder {
+	builder.port = port
+	return builder
+}
+
+// Upstream is the base url of the real backend, to which all requests are forwarded
+func (builder *EndpointBuilder) Upstream(upstream string) *EndpointBuilder {
+	builder.upstream = upstream
+	return builder
+}
+
+// Timeout of a request to the upstream
+func (builder *EndpointBuilder) Timeout(timeout time.Duration) *EndpointBuilder {
+	builder.timeout = timeout
+	return builder
+}
+
+// Record saves every exchange with the upstream as a fixture file in the given directory, to be replayed
+// later by a server endpoint. The files are numbered, so the directory should be empty before recording.
+func (builder *EndpointBuilder) Record(dir string) *EndpointBuilder {
+	builder.recordDir = dir
+	return builder
+}
+
+// RedactHeaders replaces the values of the given request & response headers in the recorded fixtures.
+func (builder *EndpointBuilder) RedactHeaders(headers ...string) *EndpointBuilder {
+	builder.redaction.Headers = append(builder.redaction.Headers, headers...)
+	return builder
+}
+
+// RedactBody replaces every match of the given regular expressions in the recorded request & response bodies
+// and in the raw query of the recorded request.
+// Build() panics if a pattern is not a valid regular expression.
+func (builder *EndpointBuilder) RedactBody(patterns ...string) *EndpointBuilder {
+	builder.bodyPatterns = append(builder.bodyPatterns, patterns...)
+	return builder
+}
+
+// Registry registers the endpoint when it is built, before it starts listening. Its name & port must not be used
+// by other endpoints. Endpoints built through clarumhttp.Http() are registered automatically.
+// A registered endpoint is unregistered when it is closed.
+func (builder *EndpointBuilder) Registry(registry *registry.Registry) *EndpointBuilder {
+	builder.registry = registry
+	return builder
+}
+
+// Build starts the proxy. It is closed when the test run finishes.
+// Build panics if a body pattern is invalid or if the endpoint cannot be registered, so that such errors are found
+// when global variables are initialized
+func (builder *EndpointBuilder) Build() *Endpoint {
+	redaction := builder.redaction
+	for _, pattern := range builder.bodyPatterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			panic(fmt.Errorf("invalid proxy endpoint [%s] - invalid body pattern [%s] - %w", builder.name, pattern, err))
+		}
+		redaction.BodyPatterns = append(redaction.BodyPatterns, compiled)
+	}
+
+	endpoint := newEndpoint(builder.name, builder.port, builder.upstream, builder.timeout)
+	if builder.recordDir != "" {
+		endpoint.recorder = fixtures.NewRecorder(builder.recordDir, redaction)
+	}
+	if builder.registry != nil {
+		if err := builder.registry.Register(registry.Proxy, builder.name, builder.port, endpoint, endpoint.Close); err != nil {
+			panic(err)
+		}
+		endpoint.registry = builder.registry
+	}
+
+	lifecycle.OnFinish(endpoint.Close)
+	endpoint.start()
+
+	return endpoint
+}
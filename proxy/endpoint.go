@@ -0,0 +1,350 @@
+package proxy
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-core/config"
+	"github.com/go-clarum/clarum-core/control"
+	"github.com/go-clarum/clarum-core/durations"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/fixtures"
+	"github.com/go-clarum/clarum-http/internal/utils"
+	"github.com/go-clarum/clarum-http/internal/validators"
+	"github.com/go-clarum/clarum-http/message"
+	"github.com/go-clarum/clarum-http/registry"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const recordedExchangesBuffer = 64
+const closeTimeout = time.Second
+
+// headers that only apply to a single connection and must not be forwarded, see RFC 9110, section 7.6.1
+var hopByHopHeaders = []string{
+	"Connection",
+	"Keep-Alive",
+	"Proxy-Authenticate",
+	"Proxy-Authorization",
+	"Proxy-Connection",
+	"Te",
+	"Trailer",
+	"Transfer-Encoding",
+	"Upgrade",
+}
+
+// Endpoint is a recording proxy between the system under test and a real backend. Unlike a server endpoint,
+// the proxy never waits for the test: every request is forwarded to the upstream right away.
+// Both legs of each exchange are recorded and can be validated afterward, in the order the exchanges happened.
+type Endpoint struct {
+	name      string
+	port      uint
+	upstream  string
+	client    *http.Client
+	server    *http.Server
+	exchanges chan *recordedExchange
+	current   *recordedExchange
+	rules     []*responseRule
+	rulesLock sync.Mutex
+	recorder  *fixtures.Recorder
+	closeOnce sync.Once
+	closeErr  error
+	registry  *registry.Registry
+	logger    *logging.Logger
+}
+
+type recordedExchange struct {
+	request         *http.Request
+	requestPayload  []byte
+	response        *http.Response
+	responsePayload []byte
+	error           error
+}
+
+// responseRule changes how the response of the next exchange is sent back to the system under test
+type responseRule struct {
+	delay    time.Duration
+	response *message.ResponseMessage
+}
+
+func newEndpoint(name string, port uint, upstream string, timeout time.Duration) *Endpoint {
+	return &Endpoint{
+		name:     name,
+		port:     port,
+		upstream: upstream,
+		client: &http.Client{
+			Timeout: durations.GetDurationWithDefault(timeout, 10*time.Second),
+			// redirects are the business of the system under test
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		exchanges: make(chan *recordedExchange, recordedExchangesBuffer),
+		logger:    logging.NewLogger(config.LoggingLevel(), proxyLogPrefix(name)),
+	}
+}
+
+func (endpoint *Endpoint) start() {
+	mux := http.NewServeMux()
+	mux.Handle("/", endpoint)
+
+	endpoint.server = &http.Server{
+		Addr:    fmt.Sprintf(":%d", endpoint.port),
+		Handler: mux,
+	}
+
+	listener, err := net.Listen("tcp", endpoint.server.Addr)
+	if err != nil {
+		endpoint.logger.Errorf("error - %s", err)
+		return
+	}
+
+	go func() {
+		if err := endpoint.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			endpoint.logger.Errorf("error - %s", err)
+		} else {
+			endpoint.logger.Info("closed server")
+		}
+	}()
+}
+
+// Close stops the proxy and frees its name & port. Only the first call closes the endpoint, later calls return its result.
+func (endpoint *Endpoint) Close() error {
+	endpoint.closeOnce.Do(func() {
+		if endpoint.registry != nil {
+			endpoint.registry.Unregister(endpoint.name)
+		}
+		if endpoint.server == nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+		defer cancel()
+
+		endpoint.closeErr = endpoint.server.Shutdown(ctx)
+	})
+	return endpoint.closeErr
+}
+
+// ServeHTTP forwards the request to the upstream, records the exchange and sends the response back.
+// If the upstream cannot be reached, the system under test receives a 502 (Bad Gateway).
+func (endpoint *Endpoint) ServeHTTP(resWriter http.ResponseWriter, request *http.Request) {
+	control.RunningActions.Add(1)
+	defer finishOrRecover(endpoint.logger)
+
+	requestPayload, _ := io.ReadAll(request.Body)
+	endpoint.logger.Infof("received HTTP request [method: %s, url: %s, headers: %s, payload: %s]",
+		request.Method, request.URL.String(), request.Header, requestPayload)
+
+	exchange := &recordedExchange{request: request, requestPayload: requestPayload}
+	exchange.response, exchange.responsePayload, exchange.error = endpoint.forward(request, requestPayload)
+	endpoint.record(exchange)
+	endpoint.saveFixture(exchange)
+
+	rule := endpoint.nextRule()
+	if rule.delay > 0 {
+		endpoint.logger.Infof("delaying response by %s", rule.delay)
+		select {
+		case <-time.After(rule.delay):
+		case <-request.Context().Done():
+			endpoint.logger.Warn("response delay canceled - the request was canceled")
+			return
+		}
+	}
+
+	if rule.response != nil {
+		endpoint.logger.Infof("overriding upstream response with %s", rule.response.ToString())
+		for header, value := range rule.response.Headers {
+			resWriter.Header().Set(header, value)
+		}
+		resWriter.WriteHeader(rule.response.StatusCode)
+		writePayload(endpoint.logger, resWriter, []byte(rule.response.MessagePayload))
+	} else if exchange.error != nil {
+		resWriter.WriteHeader(http.StatusBadGateway)
+	} else {
+		copyHeaders(resWriter.Header(), exchange.response.Header)
+		resWriter.WriteHeader(exchange.response.StatusCode)
+		writePayload(endpoint.logger, resWriter, exchange.responsePayload)
+	}
+}
+
+func (endpoint *Endpoint) forward(request *http.Request, payload []byte) (*http.Response, []byte, error) {
+	target := utils.BuildPath(endpoint.upstream, request.URL.Path)
+	if request.URL.RawQuery != "" {
+		target = target + "?" + request.URL.RawQuery
+	}
+
+	upstreamRequest, err := http.NewRequest(request.Method, target, bytes.NewReader(payload))
+	if err != nil {
+		endpoint.logger.Errorf("could not build upstream request - %s", err)
+		return nil, nil, err
+	}
+	copyHeaders(upstreamRequest.Header, request.Header)
+
+	endpoint.logger.Infof("forwarding HTTP request [method: %s, url: %s]", upstreamRequest.Method, target)
+	response, err := endpoint.client.Do(upstreamRequest)
+	if err != nil {
+		endpoint.logger.Errorf("error on upstream request - %s", err)
+		return nil, nil, err
+	}
+	defer response.Body.Close()
+
+	responsePayload, err := io.ReadAll(response.Body)
+	if err != nil {
+		endpoint.logger.Errorf("could not read upstream response body - %s", err)
+		return nil, nil, err
+	}
+
+	endpoint.logger.Infof("received upstream response [status: %s, headers: %s, payload: %s]",
+		response.Status, response.Header, responsePayload)
+	return response, responsePayload, nil
+}
+
+func (endpoint *Endpoint) record(exchange *recordedExchange) {
+	select {
+	case endpoint.exchanges <- exchange:
+	default:
+		endpoint.logger.Warn("exchange was not recorded - too many exchanges were not validated")
+	}
+}
+
+// saveFixture writes the exchange to disk, if the endpoint records. Failed exchanges are not saved.
+func (endpoint *Endpoint) saveFixture(exchange *recordedExchange) {
+	if endpoint.recorder == nil || exchange.error != nil {
+		return
+	}
+
+	filePath, err := endpoint.recorder.Record(exchange.request, exchange.requestPayload,
+		exchange.response, exchange.responsePayload)
+	if err != nil {
+		endpoint.logger.Errorf("could not record fixture - %s", err)
+		return
+	}
+	endpoint.logger.Infof("recorded fixture [%s]", filePath)
+}
+
+// addRule queues a rule for the next exchange that does not have one yet
+func (endpoint *Endpoint) addRule(rule *responseRule) {
+	endpoint.rulesLock.Lock()
+	defer endpoint.rulesLock.Unlock()
+
+	endpoint.rules = append(endpoint.rules, rule)
+}
+
+func (endpoint *Endpoint) nextRule() *responseRule {
+	endpoint.rulesLock.Lock()
+	defer endpoint.rulesLock.Unlock()
+
+	if len(endpoint.rules) == 0 {
+		return &responseRule{}
+	}
+
+	rule := endpoint.rules[0]
+	endpoint.rules = endpoint.rules[1:]
+	return rule
+}
+
+func (endpoint *Endpoint) send(rule *responseRule) error {
+	if rule.response != nil && (rule.response.StatusCode < 100 || rule.response.StatusCode > 999) {
+		return endpoint.handleError(fmt.Sprintf("message to send is invalid - unsupported status code [%d]",
+			rule.response.StatusCode), nil)
+	}
+	if rule.response != nil {
+		rule.response = rule.response.Clone()
+	}
+
+	endpoint.addRule(rule)
+	return nil
+}
+
+// receiveRequest validates the request leg of the next recorded exchange
+func (endpoint *Endpoint) receiveRequest(message *message.RequestMessage, validationOptions receiveOptions) error {
+	exchange, err := endpoint.nextExchange()
+	if err != nil {
+		return err
+	}
+	endpoint.current = exchange
+
+	return errors.Join(
+		validators.ValidatePath(message, exchange.request.URL, endpoint.logger),
+		validators.ValidateHttpMethod(message, exchange.request.Method, endpoint.logger),
+		validators.ValidateHttpHeaders(&message.Message, exchange.request.Header, endpoint.logger),
+		validators.ValidateHttpQueryParams(message, exchange.request.URL, endpoint.logger),
+		validators.ValidateHttpPayload(&message.Message, io.NopCloser(bytes.NewReader(exchange.requestPayload)),
+			validationOptions.expectedPayloadType, endpoint.logger))
+}
+
+// receiveResponse validates the upstream leg of the exchange whose request was validated last.
+// If the request was not validated, the next recorded exchange is used.
+func (endpoint *Endpoint) receiveResponse(message *message.ResponseMessage, validationOptions receiveOptions) error {
+	exchange := endpoint.current
+	endpoint.current = nil
+
+	if exchange == nil {
+		var err error
+		if exchange, err = endpoint.nextExchange(); err != nil {
+			return err
+		}
+	}
+
+	if exchange.error != nil {
+		return endpoint.handleError("upstream did not respond", exchange.error)
+	}
+
+	return errors.Join(
+		validators.ValidateHttpStatusCode(message, exchange.response.StatusCode, endpoint.logger),
+		validators.ValidateHttpHeaders(&message.Message, exchange.response.Header, endpoint.logger),
+		validators.ValidateHttpPayload(&message.Message, io.NopCloser(bytes.NewReader(exchange.responsePayload)),
+			validationOptions.expectedPayloadType, endpoint.logger))
+}
+
+func (endpoint *Endpoint) nextExchange() (*recordedExchange, error) {
+	select {
+	case exchange := <-endpoint.exchanges:
+		return exchange, nil
+	case <-time.After(config.ActionTimeout()):
+		return nil, endpoint.handleError("receive action timed out - no exchange recorded for validation", nil)
+	}
+}
+
+func (endpoint *Endpoint) handleError(message string, err error) error {
+	var errorMessage string
+	if err != nil {
+		errorMessage = message + " - " + err.Error()
+	} else {
+		errorMessage = message
+	}
+	endpoint.logger.Error(errorMessage)
+	return errors.New(endpoint.logger.Prefix() + errorMessage)
+}
+
+func copyHeaders(destination http.Header, source http.Header) {
+	for header, values := range source {
+		destination[header] = append([]string(nil), values...)
+	}
+	for _, header := range hopByHopHeaders {
+		destination.Del(header)
+	}
+}
+
+func writePayload(logger *logging.Logger, resWriter http.ResponseWriter, payload []byte) {
+	if _, err := resWriter.Write(payload); err != nil {
+		logger.Errorf("could not write response body - %s", err)
+	}
+}
+
+func finishOrRecover(logger *logging.Logger) {
+	control.RunningActions.Done()
+
+	if r := recover(); r != nil {
+		logger.Errorf("endpoint panicked: error - %s", r)
+	}
+}
+
+func proxyLogPrefix(endpointName string) string {
+	return fmt.Sprintf("%s: ", endpointName)
+}
@@ -0,0 +1,112 @@
+package proxy
+
+import (
+	"context"
+	"github.com/go-clarum/clarum-http/internal/lifecycle"
+	"github.com/go-clarum/clarum-http/message"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCopyHeadersWithoutHopByHop(t *testing.T) {
+	source := http.Header{}
+	source.Set("Content-Type", "application/json")
+	source.Set("Connection", "keep-alive")
+	source.Set("Transfer-Encoding", "chunked")
+
+	destination := http.Header{}
+	copyHeaders(destination, source)
+
+	if destination.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type header must be copied")
+	}
+	if destination.Get("Connection") != "" || destination.Get("Transfer-Encoding") != "" {
+		t.Errorf("Hop-by-hop headers must not be copied, but got %s", destination)
+	}
+}
+
+func TestRulesAreAppliedInOrder(t *testing.T) {
+	endpoint := newEndpoint("proxy", 0, "http://localhost", 0)
+
+	endpoint.addRule(&responseRule{delay: time.Second})
+	endpoint.addRule(&responseRule{response: message.Response(http.StatusServiceUnavailable)})
+
+	if rule := endpoint.nextRule(); rule.delay != time.Second {
+		t.Errorf("First rule expected, but got %+v", rule)
+	}
+	if rule := endpoint.nextRule(); rule.response == nil || rule.response.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("Second rule expected, but got %+v", rule)
+	}
+	if rule := endpoint.nextRule(); rule.delay != 0 || rule.response != nil {
+		t.Errorf("Empty rule expected, but got %+v", rule)
+	}
+}
+
+func TestBuildRejectsInvalidBodyPattern(t *testing.T) {
+	defer func() {
+		err, isError := recover().(error)
+		if !isError || !strings.Contains(err.Error(), "invalid proxy endpoint [typoProxy] - invalid body pattern [secret-[a-z+]") {
+			t.Errorf("Expected panic for the invalid body pattern, but got [%v]", err)
+		}
+	}()
+
+	NewEndpointBuilder().
+		Name("typoProxy").
+		Upstream("http://localhost:8099").
+		RedactBody("secret-[a-z+").
+		Build()
+}
+
+func TestDelayEndsWithTheRequest(t *testing.T) {
+	endpoint := newEndpoint("proxy", 0, "http://localhost:8110", 0)
+	endpoint.addRule(&responseRule{delay: time.Minute})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	request := httptest.NewRequest(http.MethodGet, "/users", nil).WithContext(ctx)
+
+	handlerReturned := make(chan struct{})
+	go func() {
+		defer close(handlerReturned)
+		endpoint.ServeHTTP(httptest.NewRecorder(), request)
+	}()
+
+	select {
+	case <-handlerReturned:
+	case <-time.After(time.Second):
+		t.Errorf("response delay was not canceled with the request")
+	}
+}
+
+func TestFinishClosesProxy(t *testing.T) {
+	endpoint := NewEndpointBuilder().
+		Name("finishedProxy").
+		Port(8109).
+		Upstream("http://localhost:8110").
+		Build()
+
+	// the proxy is serving once it answers, with a 502 since the upstream is never started
+	if response, err := http.Get("http://localhost:8109/users"); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	} else {
+		response.Body.Close()
+	}
+
+	if err := lifecycle.Finish(); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	// closing a closed proxy does nothing
+	if err := endpoint.Close(); err != nil {
+		t.Errorf("No error expected for the second close, but got %s", err)
+	}
+
+	listener, err := net.Listen("tcp", ":8109")
+	if err != nil {
+		t.Fatalf("port was not freed - %s", err)
+	}
+	listener.Close()
+}
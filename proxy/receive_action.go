@@ -0,0 +1,61 @@
+package proxy
+
+import (
+	"github.com/go-clarum/clarum-http/internal"
+	"github.com/go-clarum/clarum-http/message"
+	"testing"
+)
+
+type receiveOptions struct {
+	expectedPayloadType internal.PayloadType
+}
+
+// ReceiveActionBuilder used to validate a recorded exchange of a proxy endpoint without the context of a test
+// the method chain will end with the .Request() or .Response() method which will return an error.
+// The error will be a problem encountered during receiving or a validation error.
+type ReceiveActionBuilder struct {
+	endpoint *Endpoint
+	options  *receiveOptions
+}
+
+// TestReceiveActionBuilder used to validate a recorded exchange of a proxy endpoint with the context of a test
+// the method chain will end with the .Request() or .Response() method which will not return anything.
+// Any error encountered during receiving or validating will fail the test by calling t.Error().
+type TestReceiveActionBuilder struct {
+	test *testing.T
+	ReceiveActionBuilder
+}
+
+func (testBuilder *TestReceiveActionBuilder) Json() *TestReceiveActionBuilder {
+	testBuilder.options.expectedPayloadType = internal.Json
+	return testBuilder
+}
+
+func (builder *ReceiveActionBuilder) Json() *ReceiveActionBuilder {
+	builder.options.expectedPayloadType = internal.Json
+	return builder
+}
+
+// Request validates the request sent by the system under test, of the next recorded exchange
+func (testBuilder *TestReceiveActionBuilder) Request(message *message.RequestMessage) {
+	if err := testBuilder.endpoint.receiveRequest(message, *testBuilder.options); err != nil {
+		testBuilder.test.Error(err)
+	}
+}
+
+// Request validates the request sent by the system under test, of the next recorded exchange
+func (builder *ReceiveActionBuilder) Request(message *message.RequestMessage) error {
+	return builder.endpoint.receiveRequest(message, *builder.options)
+}
+
+// Response validates the response of the upstream, of the exchange whose request was validated last
+func (testBuilder *TestReceiveActionBuilder) Response(message *message.ResponseMessage) {
+	if err := testBuilder.endpoint.receiveResponse(message, *testBuilder.options); err != nil {
+		testBuilder.test.Error(err)
+	}
+}
+
+// Response validates the response of the upstream, of the exchange whose request was validated last
+func (builder *ReceiveActionBuilder) Response(message *message.ResponseMessage) error {
+	return builder.endpoint.receiveResponse(message, *builder.options)
+}
@@ -0,0 +1,63 @@
+package proxy
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"testing"
+	"time"
+)
+
+// SendActionBuilder used to configure how the proxy answers the next exchange, without the context of a test.
+// The method chain will end with the .Response() or .Upstream() method which will return an error.
+// Send actions do not block: they are queued and applied to the next exchanges, in order.
+type SendActionBuilder struct {
+	endpoint *Endpoint
+	rule     *responseRule
+}
+
+// TestSendActionBuilder used to configure how the proxy answers the next exchange, with the context of a test.
+// The method chain will end with the .Response() or .Upstream() method which will not return anything.
+// Any error encountered will fail the test by calling t.Error().
+type TestSendActionBuilder struct {
+	test *testing.T
+	SendActionBuilder
+}
+
+// Delay holds back the response to the system under test. The upstream is still called right away.
+func (testBuilder *TestSendActionBuilder) Delay(delay time.Duration) *TestSendActionBuilder {
+	testBuilder.rule.delay = delay
+	return testBuilder
+}
+
+// Delay holds back the response to the system under test. The upstream is still called right away.
+func (builder *SendActionBuilder) Delay(delay time.Duration) *SendActionBuilder {
+	builder.rule.delay = delay
+	return builder
+}
+
+// Response overrides the upstream response sent to the system under test. The upstream is still called
+// and its response is recorded for validation.
+func (testBuilder *TestSendActionBuilder) Response(message *message.ResponseMessage) {
+	testBuilder.rule.response = message
+	if err := testBuilder.endpoint.send(testBuilder.rule); err != nil {
+		testBuilder.test.Error(err)
+	}
+}
+
+// Response overrides the upstream response sent to the system under test. The upstream is still called
+// and its response is recorded for validation.
+func (builder *SendActionBuilder) Response(message *message.ResponseMessage) error {
+	builder.rule.response = message
+	return builder.endpoint.send(builder.rule)
+}
+
+// Upstream sends the upstream response to the system under test as it is. Only useful together with Delay().
+func (testBuilder *TestSendActionBuilder) Upstream() {
+	if err := testBuilder.endpoint.send(testBuilder.rule); err != nil {
+		testBuilder.test.Error(err)
+	}
+}
+
+// Upstream sends the upstream response to the system under test as it is. Only useful together with Delay().
+func (builder *SendActionBuilder) Upstream() error {
+	return builder.endpoint.send(builder.rule)
+}
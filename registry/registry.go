@@ -0,0 +1,158 @@
+package registry
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// kinds of endpoints
+const (
+	Client = "client"
+	Server = "server"
+	Proxy  = "proxy"
+	Admin  = "admin"
+
+	WebSocketClient = "websocket client"
+	WebSocketServer = "websocket server"
+)
+
+// names are used in logs, report files & admin API paths
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Registry keeps track of the endpoints of a test run. Every name & port may only be used by one endpoint,
+// so that logs and reports can be attributed unambiguously and port conflicts are reported before a server starts.
+type Registry struct {
+	entries map[string]*entry
+	lock    sync.Mutex
+}
+
+type entry struct {
+	kind     string
+	name     string
+	port     uint
+	endpoint any
+	close    func() error
+}
+
+func New() *Registry {
+	return &Registry{entries: make(map[string]*entry)}
+}
+
+// Register adds an endpoint. A port of 0 means the endpoint does not listen on a port, like clients
+// & in-process servers. The close function is called by Close() and may be nil.
+func (registry *Registry) Register(kind string, name string, port uint, endpoint any, close func() error) error {
+	if err := ValidateName(name); err != nil {
+		return fmt.Errorf("invalid %s endpoint - %w", kind, err)
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	if existing, exists := registry.entries[name]; exists {
+		return fmt.Errorf("invalid %s endpoint - name [%s] is already used by a %s endpoint", kind, name, existing.kind)
+	}
+	if err := registry.portConflict(kind, name, port); err != nil {
+		return err
+	}
+
+	registry.entries[name] = &entry{kind: kind, name: name, port: port, endpoint: endpoint, close: close}
+	return nil
+}
+
+// Check reports whether an endpoint could be registered, without registering it. A registered endpoint of the same
+// kind & name is treated as the one being replaced, so that it can keep running until its replacement is checked.
+func (registry *Registry) Check(kind string, name string, port uint) error {
+	if err := ValidateName(name); err != nil {
+		return fmt.Errorf("invalid %s endpoint - %w", kind, err)
+	}
+
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	if existing, exists := registry.entries[name]; exists && existing.kind != kind {
+		return fmt.Errorf("invalid %s endpoint - name [%s] is already used by a %s endpoint", kind, name, existing.kind)
+	}
+	return registry.portConflict(kind, name, port)
+}
+
+func (registry *Registry) portConflict(kind string, name string, port uint) error {
+	if port == 0 {
+		return nil
+	}
+	for _, existing := range registry.entries {
+		if existing.port == port && existing.name != name {
+			return fmt.Errorf("invalid %s endpoint [%s] - port [%d] is already used by %s endpoint [%s]",
+				kind, name, port, existing.kind, existing.name)
+		}
+	}
+	return nil
+}
+
+// Lookup returns the endpoint of the given kind & name
+func (registry *Registry) Lookup(kind string, name string) (any, error) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	existing, exists := registry.entries[name]
+	if !exists {
+		return nil, fmt.Errorf("no %s endpoint [%s] registered", kind, name)
+	}
+	if existing.kind != kind {
+		return nil, fmt.Errorf("endpoint [%s] is a %s endpoint, not a %s endpoint", name, existing.kind, kind)
+	}
+	return existing.endpoint, nil
+}
+
+// Names returns the names of all registered endpoints, sorted
+func (registry *Registry) Names() []string {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	names := make([]string, 0, len(registry.entries))
+	for name := range registry.entries {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Unregister frees the name & port of an endpoint, for example after it was closed
+func (registry *Registry) Unregister(name string) {
+	registry.lock.Lock()
+	defer registry.lock.Unlock()
+
+	delete(registry.entries, name)
+}
+
+// Close closes all endpoints & empties the registry. All endpoints are closed, even if some of them fail.
+func (registry *Registry) Close() error {
+	registry.lock.Lock()
+	entries := registry.entries
+	registry.entries = make(map[string]*entry)
+	registry.lock.Unlock()
+
+	var errs []error
+	for _, existing := range entries {
+		if existing.close == nil {
+			continue
+		}
+		if err := existing.close(); err != nil {
+			errs = append(errs, fmt.Errorf("unable to close %s endpoint [%s] - %w", existing.kind, existing.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ValidateName checks that a name is not empty and only contains letters, digits, '.', '_' & '-'
+func ValidateName(name string) error {
+	if name == "" {
+		return errors.New("name is empty")
+	}
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("name [%s] may only contain letters, digits, '.', '_' & '-'", name)
+	}
+	return nil
+}
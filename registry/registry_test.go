@@ -0,0 +1,94 @@
+package registry
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	registry := New()
+
+	if err := registry.Register(Server, "userService", 8083, "server", nil); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if err := registry.Register(Client, "userClient", 0, "client", nil); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+
+	expectError(t, registry.Register(Client, "userService", 0, "client", nil),
+		"invalid client endpoint - name [userService] is already used by a server endpoint")
+	expectError(t, registry.Register(Proxy, "userProxy", 8083, "proxy", nil),
+		"invalid proxy endpoint [userProxy] - port [8083] is already used by server endpoint [userService]")
+	expectError(t, registry.Register(Client, "", 0, "client", nil),
+		"invalid client endpoint - name is empty")
+	expectError(t, registry.Register(Server, "user service", 8084, "server", nil),
+		"invalid server endpoint - name [user service] may only contain letters, digits, '.', '_' & '-'")
+
+	registry.Unregister("userService")
+	if err := registry.Register(Proxy, "userProxy", 8083, "proxy", nil); err != nil {
+		t.Errorf("Port was not freed - %s", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	registry := New()
+	_ = registry.Register(Server, "userService", 8083, "server", nil)
+
+	if err := registry.Check(Server, "userService", 8084); err != nil {
+		t.Errorf("No error expected for the replaced endpoint, but got %s", err)
+	}
+	if err := registry.Check(Server, "orderService", 8084); err != nil {
+		t.Errorf("No error expected, but got %s", err)
+	}
+	expectError(t, registry.Check(Server, "orderService", 8083),
+		"invalid server endpoint [orderService] - port [8083] is already used by server endpoint [userService]")
+	expectError(t, registry.Check(Admin, "userService", 9000),
+		"invalid admin endpoint - name [userService] is already used by a server endpoint")
+	expectError(t, registry.Check(Server, "", 8084),
+		"invalid server endpoint - name is empty")
+	if len(registry.Names()) != 1 {
+		t.Errorf("Check registered an endpoint: %v", registry.Names())
+	}
+}
+
+func TestLookup(t *testing.T) {
+	registry := New()
+	_ = registry.Register(Server, "userService", 8083, "server", nil)
+
+	if endpoint, err := registry.Lookup(Server, "userService"); err != nil || endpoint != "server" {
+		t.Errorf("Unexpected lookup result %v - %v", endpoint, err)
+	}
+	_, err := registry.Lookup(Client, "userService")
+	expectError(t, err, "endpoint [userService] is a server endpoint, not a client endpoint")
+	_, err = registry.Lookup(Client, "userClient")
+	expectError(t, err, "no client endpoint [userClient] registered")
+}
+
+func TestClose(t *testing.T) {
+	registry := New()
+	closed := 0
+	_ = registry.Register(Server, "userService", 8083, "server", func() error {
+		closed++
+		return nil
+	})
+	_ = registry.Register(Server, "orderService", 8084, "server", func() error {
+		closed++
+		return errors.New("timeout")
+	})
+	_ = registry.Register(Client, "userClient", 0, "client", nil)
+
+	expectError(t, registry.Close(), "unable to close server endpoint [orderService] - timeout")
+	if closed != 2 {
+		t.Errorf("Expected 2 closed endpoints, but got %d", closed)
+	}
+	if len(registry.Names()) != 0 {
+		t.Errorf("Registry was not emptied: %v", registry.Names())
+	}
+}
+
+func expectError(t *testing.T, err error, expected string) {
+	t.Helper()
+	if err == nil || err.Error() != expected {
+		t.Errorf("Expected error [%s], but got [%v]", expected, err)
+	}
+}
@@ -1,14 +1,36 @@
 package server
 
 import (
+	"crypto/tls"
+	"github.com/go-clarum/clarum-http/contract"
+	"github.com/go-clarum/clarum-http/fixtures"
+	"github.com/go-clarum/clarum-http/har"
+	"github.com/go-clarum/clarum-http/internal/lifecycle"
+	"github.com/go-clarum/clarum-http/openapi"
+	"github.com/go-clarum/clarum-http/registry"
+	"net/http"
 	"time"
 )
 
 type EndpointBuilder struct {
-	contentType string
-	port        uint
-	name        string
-	timeout     time.Duration
+	contentType  string
+	port         uint
+	name         string
+	timeout      time.Duration
+	interceptors interceptors
+	inProcess    bool
+	protocols    *http.Protocols
+	tlsConfig    *tls.Config
+	replayDir    string
+	matchRules   []fixtures.MatchRule
+	harRecorder  *har.Recorder
+	openApiSpec  *openapi.Spec
+	mockSpec     *openapi.Spec
+	pact         *contract.Pact
+	stubs        []*Stub
+	stubsOnly    bool
+	isolateTests bool
+	registry     *registry.Registry
 }
 
 func NewEndpointBuilder() *EndpointBuilder {
@@ -35,6 +57,154 @@ func (builder *EndpointBuilder) ContentType(contentType string) *EndpointBuilder
 	return builder
 }
 
+// AfterReceive adds interceptors that are called for every incoming request, in the order they were added.
+func (builder *EndpointBuilder) AfterReceive(interceptors ...RequestInterceptor) *EndpointBuilder {
+	builder.interceptors.afterReceive = append(builder.interceptors.afterReceive, interceptors...)
+	return builder
+}
+
+// BeforeSend adds interceptors that are called for every outgoing response, in the order they were added.
+func (builder *EndpointBuilder) BeforeSend(interceptors ...ResponseInterceptor) *EndpointBuilder {
+	builder.interceptors.beforeSend = append(builder.interceptors.beforeSend, interceptors...)
+	return builder
+}
+
+// Http2 makes the endpoint serve only HTTP/2 over TLS. If no TLS configuration with a certificate is set,
+// a self-signed certificate for localhost is generated.
+func (builder *EndpointBuilder) Http2() *EndpointBuilder {
+	builder.protocols = &http.Protocols{}
+	builder.protocols.SetHTTP2(true)
+	if builder.tlsConfig == nil {
+		builder.tlsConfig = &tls.Config{}
+	}
+	return builder
+}
+
+// H2C makes the endpoint accept HTTP/2 over cleartext with prior knowledge, next to HTTP/1.1.
+func (builder *EndpointBuilder) H2C() *EndpointBuilder {
+	builder.protocols = &http.Protocols{}
+	builder.protocols.SetHTTP1(true)
+	builder.protocols.SetUnencryptedHTTP2(true)
+	return builder
+}
+
+// TLSConfig makes the endpoint serve over TLS. If the configuration has no certificate,
+// a self-signed certificate for localhost is generated.
+func (builder *EndpointBuilder) TLSConfig(tlsConfig *tls.Config) *EndpointBuilder {
+	builder.tlsConfig = tlsConfig
+	return builder
+}
+
+// Replay makes the endpoint answer every request from the fixtures in the given directory, as recorded
+// by a proxy endpoint. The requests are not passed to receive actions. A request without a matching fixture
+// is answered with 404 (Not Found).
+func (builder *EndpointBuilder) Replay(dir string) *EndpointBuilder {
+	builder.replayDir = dir
+	return builder
+}
+
+// MatchOn sets the rules used to find the fixture for a request. The default rules are method & path.
+func (builder *EndpointBuilder) MatchOn(rules ...fixtures.MatchRule) *EndpointBuilder {
+	builder.matchRules = append(builder.matchRules, rules...)
+	return builder
+}
+
+// Har records every exchange of the endpoint into the given recorder.
+func (builder *EndpointBuilder) Har(recorder *har.Recorder) *EndpointBuilder {
+	builder.harRecorder = recorder
+	return builder
+}
+
+// OpenApi validates every received request against the matching operation of the spec,
+// in addition to the validation of the expected message.
+func (builder *EndpointBuilder) OpenApi(spec *openapi.Spec) *EndpointBuilder {
+	builder.openApiSpec = spec
+	return builder
+}
+
+// Mock makes the endpoint answer every operation of the spec, with the examples of the spec or with data generated
+// from the schemas. Invalid requests are answered with 400 (Bad Request) and the violations as payload.
+// The requests of overridden operations are passed to the receive actions & validated against the spec.
+func (builder *EndpointBuilder) Mock(spec *openapi.Spec) *EndpointBuilder {
+	builder.mockSpec = spec
+	builder.openApiSpec = spec
+	return builder
+}
+
+// Contract adds every validated request, together with the response sent for it, as an interaction to the pact.
+// The endpoint plays the role of the provider, the system under test is the consumer.
+func (builder *EndpointBuilder) Contract(pact *contract.Pact) *EndpointBuilder {
+	builder.pact = pact
+	return builder
+}
+
+// Stubs answer the requests they match, without involving the test. Requests that match no stub
+// are passed to the receive actions.
+func (builder *EndpointBuilder) Stubs(stubs ...*Stub) *EndpointBuilder {
+	builder.stubs = append(builder.stubs, stubs...)
+	return builder
+}
+
+// StubsOnly answers requests that match no stub with 404 (Not Found), instead of passing them to receive actions.
+// This is meant for endpoints that run without tests, like in a standalone mock server.
+func (builder *EndpointBuilder) StubsOnly() *EndpointBuilder {
+	builder.stubsOnly = true
+	return builder
+}
+
+// InProcess builds an endpoint that does not open a port. The endpoint is an http.Handler
+// and can be mounted into an existing mux, an httptest.Server or used by an in-process client endpoint.
+func (builder *EndpointBuilder) InProcess() *EndpointBuilder {
+	builder.inProcess = true
+	return builder
+}
+
+// IsolateTests lets parallel tests share the endpoint. Requests that carry the test header (X-Clarum-Test) are only
+// received by the test it names. Requests without the header are received by any test.
+func (builder *EndpointBuilder) IsolateTests() *EndpointBuilder {
+	builder.isolateTests = true
+	return builder
+}
+
+// Registry registers the endpoint when it is built, before it starts listening. Its name & port must not be used
+// by other endpoints. Endpoints built through clarumhttp.Http() are registered automatically.
+// A registered endpoint is unregistered when it is closed.
+func (builder *EndpointBuilder) Registry(registry *registry.Registry) *EndpointBuilder {
+	builder.registry = registry
+	return builder
+}
+
+// Build panics if the endpoint cannot be registered, so that conflicts are found when global variables are initialized
 func (builder *EndpointBuilder) Build() *Endpoint {
-	return newServerEndpoint(builder.name, builder.port, builder.contentType, builder.timeout)
+	endpoint := newServerEndpoint(builder.name, builder.port, builder.contentType, builder.interceptors)
+	if builder.replayDir != "" {
+		endpoint.replayer = fixtures.NewReplayer(builder.replayDir, builder.matchRules)
+	}
+	endpoint.harRecorder = builder.harRecorder
+	endpoint.openApiSpec = builder.openApiSpec
+	endpoint.mockSpec = builder.mockSpec
+	endpoint.pact = builder.pact
+	endpoint.AddStubs(builder.stubs...)
+	endpoint.stubsOnly = builder.stubsOnly
+	endpoint.isolateTests = builder.isolateTests
+
+	if builder.registry != nil {
+		port := builder.port
+		if builder.inProcess {
+			port = 0
+		}
+		if err := builder.registry.Register(registry.Server, builder.name, port, endpoint, endpoint.Close); err != nil {
+			panic(err)
+		}
+		endpoint.registry = builder.registry
+	}
+
+	endpoint.removeFinishHook = lifecycle.OnFinish(endpoint.finish)
+
+	// feature: start automatically = true/false; to simulate connection errors
+	if !builder.inProcess {
+		endpoint.start(builder.timeout, builder.protocols, builder.tlsConfig)
+	}
+
+	return endpoint
 }
@@ -3,6 +3,7 @@ package server
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"github.com/go-clarum/clarum-core/config"
@@ -10,58 +11,121 @@ import (
 	"github.com/go-clarum/clarum-core/logging"
 	clarumstrings "github.com/go-clarum/clarum-core/validators/strings"
 	"github.com/go-clarum/clarum-http/constants"
+	"github.com/go-clarum/clarum-http/contract"
+	"github.com/go-clarum/clarum-http/fixtures"
+	"github.com/go-clarum/clarum-http/har"
+	"github.com/go-clarum/clarum-http/internal"
+	"github.com/go-clarum/clarum-http/internal/certs"
+	"github.com/go-clarum/clarum-http/internal/exchanges"
 	"github.com/go-clarum/clarum-http/internal/validators"
 	"github.com/go-clarum/clarum-http/message"
+	"github.com/go-clarum/clarum-http/openapi"
+	"github.com/go-clarum/clarum-http/registry"
 	"io"
 	"net"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 )
 
-const contextNameKey = "endpointContext"
+const closeTimeout = time.Second
 
 type Endpoint struct {
 	name                     string
 	port                     uint
 	contentType              string
 	server                   *http.Server
+	interceptors             interceptors
 	context                  *context.Context
-	requestValidationChannel chan *http.Request
-	sendChannel              chan *sendPair
+	cancelCtx                context.CancelFunc
+	requestValidationChannel chan *exchange
+	isolateTests             bool
+	testChannels             map[string]*testChannel
+	testChannelsLock         sync.Mutex
+	awaitingResponse         []*exchange
+	awaitingLock             sync.Mutex
+	replayer                 *fixtures.Replayer
+	harRecorder              *har.Recorder
+	openApiSpec              *openapi.Spec
+	mockSpec                 *openapi.Spec
+	pact                     *contract.Pact
+	stubs                    []*Stub
+	stubsLock                sync.Mutex
+	stubsOnly                bool
+	scenarios                map[string]string
+	scenariosLock            sync.Mutex
+	registry                 *registry.Registry
+	tracker                  *exchanges.Tracker
+	closeOnce                sync.Once
+	closeErr                 error
+	removeFinishHook         func()
+	journal                  []JournalEntry
+	journalLock              sync.Mutex
+	fault                    *Fault
+	faultLock                sync.Mutex
+	overrides                map[string]bool
+	testOverrides            map[string]int
+	overridesLock            sync.Mutex
 	logger                   *logging.Logger
 }
 
-type endpointContext struct {
-	endpointName             string
-	requestValidationChannel chan *http.Request
-	sendChannel              chan *sendPair
-	logger                   *logging.Logger
+// exchange is a request being handled, waiting for the response provided by a send action
+type exchange struct {
+	request  *http.Request
+	expected *message.RequestMessage
+	response chan *sendPair
+	// tracked is updated by the actions handling the exchange
+	tracked *exchanges.Exchange
+	// test is the test whose receive action received the request
+	test string
+	// canceled is closed when the context of the receive action ends, to release the request handler
+	canceled chan struct{}
+	// handled is closed when the request handler returns, after which the exchange cannot be answered anymore
+	handled chan struct{}
+}
+
+// testChannel passes the requests sent by an isolated test to its receive actions
+type testChannel struct {
+	requests chan *exchange
+	// ended is closed when the test ends, to release the request handlers waiting for its receive actions
+	ended chan struct{}
+}
+
+// endedTestChannel is used by the requests of tests that are not running: they are released at once,
+// and receive actions only get the requests of no test
+var endedTestChannel = &testChannel{ended: closedChannel()}
+
+func closedChannel() chan struct{} {
+	ended := make(chan struct{})
+	close(ended)
+	return ended
 }
 
 type sendPair struct {
 	response *message.ResponseMessage
+	stream   *responseStream
 	error    error
 }
 
-func newServerEndpoint(name string, port uint, contentType string, timeout time.Duration) *Endpoint {
+func newServerEndpoint(name string, port uint, contentType string, interceptors interceptors) *Endpoint {
 	ctx, cancelCtx := context.WithCancel(context.Background())
-	sendChannel := make(chan *sendPair)
-	requestChannel := make(chan *http.Request)
 
-	se := &Endpoint{
+	return &Endpoint{
 		name:                     name,
 		port:                     port,
 		contentType:              contentType,
+		interceptors:             interceptors,
 		context:                  &ctx,
-		sendChannel:              sendChannel,
-		requestValidationChannel: requestChannel,
+		cancelCtx:                cancelCtx,
+		requestValidationChannel: make(chan *exchange),
+		testChannels:             make(map[string]*testChannel),
+		overrides:                make(map[string]bool),
+		testOverrides:            make(map[string]int),
+		scenarios:                make(map[string]string),
+		tracker:                  exchanges.NewTracker(),
 		logger:                   logging.NewLogger(config.LoggingLevel(), serverLogPrefix(name)),
 	}
-
-	// feature: start automatically = true/false; to simulate connection errors
-	se.start(ctx, cancelCtx, timeout)
-
-	return se
 }
 
 // this Method is blocking, until a request is received
@@ -69,23 +133,46 @@ func (endpoint *Endpoint) receive(message *message.RequestMessage, validationOpt
 	endpoint.logger.Debugf("message to receive %s", message.ToString())
 	messageToReceive := endpoint.getMessageToReceive(message)
 
+	// requests without the test header are received by any test, since the system under test may not forward it
+	ctx := internal.ActionContext(validationOptions.ctx)
+	var receivedExchange *exchange
 	select {
-	case receivedRequest := <-endpoint.requestValidationChannel:
-		endpoint.logger.Debugf("validation message %s", messageToReceive.ToString())
-
-		return receivedRequest, errors.Join(
-			validators.ValidatePath(messageToReceive, receivedRequest.URL, endpoint.logger),
-			validators.ValidateHttpMethod(messageToReceive, receivedRequest.Method, endpoint.logger),
-			validators.ValidateHttpHeaders(&messageToReceive.Message, receivedRequest.Header, endpoint.logger),
-			validators.ValidateHttpQueryParams(messageToReceive, receivedRequest.URL, endpoint.logger),
-			validators.ValidateHttpPayload(&messageToReceive.Message, receivedRequest.Body,
-				validationOptions.expectedPayloadType, endpoint.logger))
-	case <-time.After(config.ActionTimeout()):
+	case receivedExchange = <-endpoint.requestValidationChannel:
+	case receivedExchange = <-endpoint.requestChannel(validationOptions.test):
+	case <-time.After(internal.ActionTimeout(validationOptions.timeout)):
 		return nil, endpoint.handleError("receive action timed out - no request received for validation", nil)
+	case <-ctx.Done():
+		return nil, endpoint.handleError("receive action canceled", ctx.Err())
+	}
+
+	receivedExchange.test = validationOptions.test
+	canceled := receivedExchange.canceled
+	context.AfterFunc(ctx, func() { close(canceled) })
+	endpoint.tracker.Await(receivedExchange.tracked, "a server send action")
+	endpoint.logger.Debugf("validation message %s", messageToReceive.ToString())
+	receivedRequest := receivedExchange.request
+
+	err := errors.Join(
+		validators.ValidateHttpProto(validationOptions.expectedProto, receivedRequest.Proto, endpoint.logger),
+		validators.ValidatePath(messageToReceive, receivedRequest.URL, endpoint.logger),
+		validators.ValidateHttpMethod(messageToReceive, receivedRequest.Method, endpoint.logger),
+		validators.ValidateHttpHeaders(&messageToReceive.Message, receivedRequest.Header, endpoint.logger),
+		validators.ValidateHttpQueryParams(messageToReceive, receivedRequest.URL, endpoint.logger),
+		// the contract is validated before the payload, which closes the body
+		validators.ValidateOpenApiRequest(endpoint.openApiSpec, receivedRequest, endpoint.logger),
+		validators.ValidateHttpPayload(&messageToReceive.Message, receivedRequest.Body,
+			validationOptions.expectedPayloadType, endpoint.logger))
+
+	// only validated requests become part of the contract
+	if err == nil {
+		receivedExchange.expected = messageToReceive
 	}
+	endpoint.awaitResponse(receivedExchange)
+
+	return receivedRequest, err
 }
 
-func (endpoint *Endpoint) send(message *message.ResponseMessage) error {
+func (endpoint *Endpoint) send(message *message.ResponseMessage, options sendOptions) error {
 	messageToSend := endpoint.getMessageToSend(message)
 
 	err := endpoint.validateMessageToSend(messageToSend)
@@ -96,11 +183,111 @@ func (endpoint *Endpoint) send(message *message.ResponseMessage) error {
 		error:    err,
 	}
 
-	select {
-	case endpoint.sendChannel <- toSend:
-		return err
-	case <-time.After(config.ActionTimeout()):
-		return endpoint.handleError("send action timed out - no request received for validation", nil)
+	if dispatchErr := endpoint.dispatch(toSend, options); dispatchErr != nil {
+		return dispatchErr
+	}
+	return err
+}
+
+// awaitResponse queues an exchange whose request was received by a receive action
+func (endpoint *Endpoint) awaitResponse(exchange *exchange) {
+	endpoint.awaitingLock.Lock()
+	defer endpoint.awaitingLock.Unlock()
+
+	endpoint.awaitingResponse = append(endpoint.awaitingResponse, exchange)
+}
+
+// dispatch hands over the response to the oldest request received by the test that was not answered yet. This way
+// the responses are correlated with the receive actions, even when several requests are handled concurrently, like
+// HTTP/2 streams. If no request was received by a receive action, the next incoming request is answered.
+// Requests whose handler has already returned are skipped.
+func (endpoint *Endpoint) dispatch(toSend *sendPair, options sendOptions) error {
+	skipped := false
+	for {
+		next := endpoint.nextAwaiting(options.test)
+		if next == nil && skipped {
+			return endpoint.handleError("send action failed - the handler of the received request has already returned", nil)
+		}
+
+		if next == nil {
+			ctx := internal.ActionContext(options.ctx)
+			select {
+			case next = <-endpoint.requestValidationChannel:
+			case next = <-endpoint.requestChannel(options.test):
+			case <-time.After(internal.ActionTimeout(options.timeout)):
+				return endpoint.handleError("send action timed out - no request received for validation", nil)
+			case <-ctx.Done():
+				return endpoint.handleError("send action canceled", ctx.Err())
+			}
+		}
+
+		select {
+		case next.response <- toSend:
+			if endpoint.pact != nil && next.expected != nil && toSend.error == nil && toSend.stream == nil {
+				endpoint.pact.Add(next.expected, toSend.response)
+			}
+			endpoint.tracker.Done(next.tracked)
+			return nil
+		case <-next.handled:
+			endpoint.logger.Warnf("skipping request [%s %s] - its handler has already returned",
+				next.request.Method, next.request.URL)
+			skipped = true
+		}
+	}
+}
+
+// nextAwaiting removes & returns the oldest exchange of the test that waits for a response
+func (endpoint *Endpoint) nextAwaiting(test string) *exchange {
+	endpoint.awaitingLock.Lock()
+	defer endpoint.awaitingLock.Unlock()
+
+	for i, awaiting := range endpoint.awaitingResponse {
+		if awaiting.test == test {
+			endpoint.awaitingResponse = append(endpoint.awaitingResponse[:i], endpoint.awaitingResponse[i+1:]...)
+			return awaiting
+		}
+	}
+	return nil
+}
+
+// requestChannel is the channel of the requests sent by the test, or the shared channel for requests of no test
+func (endpoint *Endpoint) requestChannel(test string) chan *exchange {
+	return endpoint.testChannel(test).requests
+}
+
+// testChannel returns the channel of the test. The shared channel for requests of no test never ends.
+// A channel is only created while the test is running, so that requests naming an unknown or ended test
+// do not leave a channel behind.
+func (endpoint *Endpoint) testChannel(test string) *testChannel {
+	if test == "" {
+		return &testChannel{requests: endpoint.requestValidationChannel}
+	}
+
+	endpoint.testChannelsLock.Lock()
+	defer endpoint.testChannelsLock.Unlock()
+
+	channel, exists := endpoint.testChannels[test]
+	if !exists && !exchanges.Running(test) {
+		return endedTestChannel
+	}
+	if !exists {
+		channel = &testChannel{
+			requests: make(chan *exchange),
+			ended:    make(chan struct{}),
+		}
+		endpoint.testChannels[test] = channel
+	}
+	return channel
+}
+
+// removeTestChannel removes the channel of the requests sent by a test that has ended
+func (endpoint *Endpoint) removeTestChannel(test string) {
+	endpoint.testChannelsLock.Lock()
+	defer endpoint.testChannelsLock.Unlock()
+
+	if channel, exists := endpoint.testChannels[test]; exists {
+		close(channel.ended)
+		delete(endpoint.testChannels, test)
 	}
 }
 
@@ -129,78 +316,283 @@ func (endpoint *Endpoint) getMessageToSend(message *message.ResponseMessage) *me
 	return finalMessage
 }
 
-func (endpoint *Endpoint) start(ctx context.Context, cancelCtx context.CancelFunc, timeout time.Duration) {
+func (endpoint *Endpoint) start(timeout time.Duration, protocols *http.Protocols, tlsConfig *tls.Config) {
 	mux := http.NewServeMux()
-	mux.HandleFunc("/", requestHandler)
+	mux.Handle("/", endpoint)
 
 	server := &http.Server{
 		Addr:         fmt.Sprintf(":%d", endpoint.port),
 		Handler:      mux,
 		WriteTimeout: timeout,
+		Protocols:    protocols,
 		BaseContext: func(l net.Listener) context.Context {
-			endpointContext := &endpointContext{
-				endpointName:             endpoint.name,
-				requestValidationChannel: endpoint.requestValidationChannel,
-				sendChannel:              endpoint.sendChannel,
-				logger:                   endpoint.logger,
-			}
-			ctx = context.WithValue(ctx, contextNameKey, endpointContext)
-			return ctx
+			return *endpoint.context
 		},
 	}
+	endpoint.server = server
+
+	if tlsConfig != nil {
+		server.TLSConfig = tlsConfig.Clone()
+		if len(server.TLSConfig.Certificates) == 0 && server.TLSConfig.GetCertificate == nil {
+			certificate, err := certs.SelfSigned()
+			if err != nil {
+				endpoint.logger.Errorf("error - could not generate certificate - %s", err)
+				endpoint.cancelCtx()
+				return
+			}
+			server.TLSConfig.Certificates = []tls.Certificate{certificate}
+		}
+	}
+
+	// we bind the listener synchronously, so that requests sent right after the endpoint
+	// was built do not race against the server startup
+	listener, err := net.Listen("tcp", server.Addr)
+	if err != nil {
+		endpoint.logger.Errorf("error - %s", err)
+		endpoint.cancelCtx()
+		return
+	}
 
 	go func() {
-		if err := server.ListenAndServe(); err != nil {
+		var err error
+		if server.TLSConfig != nil {
+			// the certificates are already part of the TLS configuration
+			err = server.ServeTLS(listener, "", "")
+		} else {
+			err = server.Serve(listener)
+		}
+
+		// a closed server is cancelled by Close, once the requests being handled had time to finish
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
 			endpoint.logger.Errorf("error - %s", err)
+			endpoint.cancelCtx()
 		} else {
 			endpoint.logger.Info("closed server")
 		}
-
-		cancelCtx()
 	}()
+}
 
-	endpoint.server = server
+// Close stops the server of the endpoint and frees its port. Requests that are being handled get a grace period
+// of one second to finish, after which they are cancelled. Only the first call closes the endpoint,
+// later calls return its result.
+func (endpoint *Endpoint) Close() error {
+	endpoint.closeOnce.Do(func() {
+		defer endpoint.cancelCtx()
+		if endpoint.registry != nil {
+			endpoint.registry.Unregister(endpoint.name)
+		}
+		if endpoint.server == nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+		defer cancel()
+
+		err := endpoint.server.Shutdown(ctx)
+		if errors.Is(err, context.DeadlineExceeded) {
+			// the requests still waiting for the test are cancelled & their connections closed
+			endpoint.cancelCtx()
+			err = endpoint.server.Close()
+		}
+		endpoint.closeErr = err
+	})
+	return endpoint.closeErr
+}
+
+// Finish reports the exchanges that were not handled by the test and closes the endpoint, before the test run
+// finishes. The endpoint is then no longer finished with the test run.
+func (endpoint *Endpoint) Finish() error {
+	if endpoint.removeFinishHook != nil {
+		endpoint.removeFinishHook()
+	}
+	return endpoint.finish()
+}
+
+// finish is called when the test run finishes: the exchanges that were not handled by the test are reported
+// and the endpoint is closed
+func (endpoint *Endpoint) finish() error {
+	var unhandledErr error
+	if unhandled := endpoint.tracker.Unhandled(); len(unhandled) > 0 {
+		unhandledErr = endpoint.handleError("unhandled exchanges:\n"+strings.Join(unhandled, "\n"), nil)
+	}
+
+	var closeErr error
+	if err := endpoint.Close(); err != nil {
+		closeErr = endpoint.handleError("could not close server", err)
+	}
+
+	return errors.Join(unhandledErr, closeErr)
+}
+
+// Reset removes all stubs, the journal and the injected fault, and resets the scenarios
+func (endpoint *Endpoint) Reset() {
+	endpoint.ClearStubs()
+	endpoint.ResetScenarios()
+	endpoint.ClearJournal()
+	endpoint.ClearFault()
+}
+
+func (endpoint *Endpoint) Name() string {
+	return endpoint.name
 }
 
-// The requestHandler is started when the server receives a request.
+// ServeHTTP is called when the server receives a request. It also allows the endpoint to be used
+// as an http.Handler, for example by mounting it into an existing mux or an httptest.Server.
 // The request is sent to the requestValidationChannel to be picked up by a test action (validation).
 // After sending the request to the channel, the handler is blocked until the send() test action
 // provides a response message. This way we can control, inside the test, when a response will be sent.
 // The handler blocks until a timeout is triggered
-func requestHandler(resWriter http.ResponseWriter, request *http.Request) {
+func (endpoint *Endpoint) ServeHTTP(resWriter http.ResponseWriter, request *http.Request) {
 	control.RunningActions.Add(1)
-	ctx := request.Context().Value(contextNameKey).(*endpointContext)
-	defer finishOrRecover(ctx.logger)
+	defer finishOrRecover(endpoint.logger)
 
-	logIncomingRequest(ctx.logger, request)
+	requestArrival := time.Now()
+	requestPayload := logIncomingRequest(endpoint.logger, request)
+	endpoint.addToJournal(request, requestPayload, requestArrival)
 
+	if endpoint.harRecorder != nil {
+		writer := &harWriter{ResponseWriter: resWriter}
+		resWriter = writer
+		defer endpoint.recordHar(request, requestPayload, writer, requestArrival)
+	}
+
+	if endpoint.applyFault(request, resWriter) {
+		return
+	}
+
+	if err := endpoint.interceptors.interceptRequest(request); err != nil {
+		sendDefaultErrorResponse(endpoint.logger, "request interceptor failed - "+err.Error(), resWriter)
+		return
+	}
+
+	if endpoint.replayer != nil {
+		endpoint.replay(request, resWriter)
+		return
+	}
+	if endpoint.mockSpec != nil && !endpoint.isOverridden(request) {
+		endpoint.mock(request, resWriter)
+		return
+	}
+	if stub := endpoint.matchStub(request, requestPayload); stub != nil {
+		endpoint.respondWithStub(stub, request, resWriter)
+		return
+	} else if endpoint.stubsOnly {
+		endpoint.logger.Errorf("no stub matches request [method: %s, url: %s]", request.Method, request.URL)
+		resWriter.WriteHeader(http.StatusNotFound)
+		logOutgoingResponse(endpoint.logger, http.StatusNotFound, "", resWriter)
+		return
+	}
+
+	test := ""
+	if endpoint.isolateTests {
+		test = request.Header.Get(constants.TestHeaderName)
+	}
+	tracked := endpoint.tracker.Start(fmt.Sprintf("request [%s %s]", request.Method, request.URL),
+		"a server receive action", test)
+	// a send action only answers the exchange while the handler waits for the response
+	handledExchange := &exchange{
+		request:  request,
+		response: make(chan *sendPair),
+		tracked:  tracked,
+		canceled: make(chan struct{}),
+		handled:  make(chan struct{}),
+	}
+	defer close(handledExchange.handled)
+
+	// the handler is released when the test of the request ends or the endpoint is closed
+	channel := endpoint.testChannel(test)
 	select {
-	case ctx.requestValidationChannel <- request:
-		ctx.logger.Debug("received request was sent to validation channel")
+	case channel.requests <- handledExchange:
+		endpoint.logger.Debug("received request was sent to validation channel")
+	case <-channel.ended:
+		endpoint.logger.Warnf("request handling canceled - test [%s] is not running or ended without a server receive action", test)
+		endpoint.tracker.TimedOut(tracked)
+		return
+	case <-(*endpoint.context).Done():
+		endpoint.logger.Warn("request handling canceled - endpoint was closed")
+		endpoint.tracker.TimedOut(tracked)
+		return
 	case <-time.After(config.ActionTimeout()):
-		ctx.logger.Warn("request handling timed out - no server receive action called in test")
+		endpoint.logger.Warn("request handling timed out - no server receive action called in test")
+		endpoint.tracker.TimedOut(tracked)
+		return
 	}
 
 	select {
-	case sendPair := <-ctx.sendChannel:
+	case sendPair := <-handledExchange.response:
+		// a stream must always be released, regardless of how the response ends
+		if sendPair.stream != nil {
+			defer close(sendPair.stream.done)
+		}
+
 		// error from upstream - we send a response to close the HTTP cycle
 		if sendPair.error != nil {
-			sendDefaultErrorResponse(ctx.logger, "request handler received error from upstream", resWriter)
+			sendDefaultErrorResponse(endpoint.logger, "request handler received error from upstream", resWriter)
 			return
 		}
 
 		// check if response is empty - we send a response to close the HTTP cycle
 		if sendPair.response == nil {
-			sendDefaultErrorResponse(ctx.logger, "request handler received empty ResponseMesage", resWriter)
+			sendDefaultErrorResponse(endpoint.logger, "request handler received empty ResponseMesage", resWriter)
 			return
 		}
 
-		sendResponse(ctx.logger, sendPair, resWriter)
+		if err := endpoint.interceptors.interceptResponse(request, sendPair.response); err != nil {
+			sendDefaultErrorResponse(endpoint.logger, "response interceptor failed - "+err.Error(), resWriter)
+			return
+		}
+
+		if sendPair.stream != nil {
+			writeStream(endpoint.logger, request, sendPair, resWriter)
+		} else {
+			sendResponse(endpoint.logger, sendPair, resWriter)
+		}
+		logExchangeTimings(endpoint.logger, requestArrival, time.Now())
+	case <-handledExchange.canceled:
+		endpoint.logger.Warn("response handling canceled - the context of the receive action ended")
+		endpoint.tracker.TimedOut(tracked)
+	case <-(*endpoint.context).Done():
+		endpoint.logger.Warn("response handling canceled - endpoint was closed")
+		endpoint.tracker.TimedOut(tracked)
 	case <-time.After(config.ActionTimeout()):
-		ctx.logger.Warn("response handling timed out - no server send action called in test")
+		endpoint.logger.Warn("response handling timed out - no server send action called in test")
+		endpoint.tracker.TimedOut(tracked)
 	}
 }
 
+// replay answers the request from the fixtures, without involving the test
+func (endpoint *Endpoint) replay(request *http.Request, resWriter http.ResponseWriter) {
+	body, _ := io.ReadAll(request.Body)
+
+	fixture, err := endpoint.replayer.Find(request, body)
+	if err != nil {
+		sendDefaultErrorResponse(endpoint.logger, "could not load fixtures - "+err.Error(), resWriter)
+		return
+	} else if fixture == nil {
+		endpoint.logger.Errorf("no fixture matches request [method: %s, url: %s]", request.Method, request.URL)
+		resWriter.WriteHeader(http.StatusNotFound)
+		logOutgoingResponse(endpoint.logger, http.StatusNotFound, "", resWriter)
+		return
+	}
+
+	responseBody, err := fixture.Response.BodyBytes()
+	if err != nil {
+		sendDefaultErrorResponse(endpoint.logger, "invalid fixture ["+fixture.FilePath+"] - "+err.Error(), resWriter)
+		return
+	}
+
+	endpoint.logger.Infof("replaying fixture [%s]", fixture.FilePath)
+	for header, values := range fixture.Response.Headers {
+		resWriter.Header()[header] = values
+	}
+	resWriter.WriteHeader(fixture.Response.Status)
+
+	if _, err := resWriter.Write(responseBody); err != nil {
+		endpoint.logger.Errorf("could not write response body - %s", err)
+	}
+	logOutgoingResponse(endpoint.logger, fixture.Response.Status, fixture.Response.Body, resWriter)
+}
+
 func sendResponse(logger *logging.Logger, sendPair *sendPair, resWriter http.ResponseWriter) {
 	for header, value := range sendPair.response.Headers {
 		resWriter.Header().Set(header, value)
@@ -237,7 +629,7 @@ func (endpoint *Endpoint) handleError(message string, err error) error {
 	} else {
 		errorMessage = message
 	}
-	endpoint.logger.Errorf(errorMessage)
+	endpoint.logger.Error(errorMessage)
 	return errors.New(endpoint.logger.Prefix() + errorMessage)
 }
 
@@ -245,13 +637,17 @@ func finishOrRecover(logger *logging.Logger) {
 	control.RunningActions.Done()
 
 	if r := recover(); r != nil {
+		// an aborted request is handled by the server
+		if r == http.ErrAbortHandler {
+			panic(r)
+		}
 		logger.Errorf("endpoint panicked: error - %s", r)
 	}
 }
 
 // we read the body 'as is' for logging, after which we put it back into the request
 // with an open reader so that it can be read downstream again
-func logIncomingRequest(logger *logging.Logger, request *http.Request) {
+func logIncomingRequest(logger *logging.Logger, request *http.Request) string {
 	bodyBytes, _ := io.ReadAll(request.Body)
 	bodyString := ""
 
@@ -270,6 +666,8 @@ func logIncomingRequest(logger *logging.Logger, request *http.Request) {
 		"payload: %s"+
 		"]",
 		request.Method, request.URL.String(), request.Header, bodyString)
+
+	return bodyString
 }
 
 func logOutgoingResponse(logger *logging.Logger, statusCode int, payload string, res http.ResponseWriter) {
@@ -281,6 +679,16 @@ func logOutgoingResponse(logger *logging.Logger, statusCode int, payload string,
 		statusCode, res.Header(), payload)
 }
 
+func logExchangeTimings(logger *logging.Logger, requestArrival time.Time, responseWritten time.Time) {
+	logger.Infof("exchange timings ["+
+		"request arrival: %s, "+
+		"response written: %s, "+
+		"total: %s"+
+		"]",
+		requestArrival.Format(time.RFC3339Nano), responseWritten.Format(time.RFC3339Nano),
+		responseWritten.Sub(requestArrival))
+}
+
 func serverLogPrefix(endpointName string) string {
 	return fmt.Sprintf("%s: ", endpointName)
 }
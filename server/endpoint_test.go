@@ -0,0 +1,229 @@
+package server
+
+import (
+	"context"
+	"github.com/go-clarum/clarum-http/constants"
+	"github.com/go-clarum/clarum-http/har"
+	"github.com/go-clarum/clarum-http/internal/exchanges"
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSendSkipsReturnedHandler(t *testing.T) {
+	endpoint := NewEndpointBuilder().
+		Name("returnedHandlerServer").
+		InProcess().
+		Build()
+
+	handlerReturned := make(chan struct{})
+	go func() {
+		defer close(handlerReturned)
+		endpoint.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if _, err := endpoint.Receive().Context(ctx).Message(message.Get("users")); err != nil {
+		t.Fatalf("unexpected receive error - %s", err)
+	}
+	// the handler is released when the context of the receive action ends
+	cancel()
+	<-handlerReturned
+
+	err := endpoint.Send().Message(message.Response(http.StatusOK))
+	if err == nil || !strings.Contains(err.Error(), "the handler of the received request has already returned") {
+		t.Errorf("Expected send error for a returned handler, but got [%v]", err)
+	}
+}
+
+func TestIsolatedTestChannelIsRemoved(t *testing.T) {
+	endpoint := NewEndpointBuilder().
+		Name("isolatedServer").
+		IsolateTests().
+		InProcess().
+		Build()
+
+	t.Run("isolated", func(t *testing.T) {
+		// the request is sent while the test is running, like by a client endpoint used in the test
+		test := endpoint.In(t)
+		handlerReturned := make(chan struct{})
+		go func() {
+			defer close(handlerReturned)
+			request := httptest.NewRequest(http.MethodGet, "/users", nil)
+			request.Header.Set(constants.TestHeaderName, t.Name())
+			endpoint.ServeHTTP(httptest.NewRecorder(), request)
+		}()
+
+		test.Receive().Message(message.Get("users"))
+		test.Send().Message(message.Response(http.StatusOK))
+		<-handlerReturned
+
+		if len(endpoint.testChannels) != 1 {
+			t.Errorf("Expected the channel of the test, but got %v", endpoint.testChannels)
+		}
+	})
+
+	if len(endpoint.testChannels) != 0 {
+		t.Errorf("Channel of the test was not removed when it ended %v", endpoint.testChannels)
+	}
+}
+
+func TestHandlerReleasedWhenTestEnds(t *testing.T) {
+	endpoint := NewEndpointBuilder().
+		Name("endedTestServer").
+		IsolateTests().
+		InProcess().
+		Build()
+
+	// the test is running once any endpoint watches it
+	exchanges.NewTracker().Watch(t, "", nil)
+	handlerReturned := make(chan struct{})
+	go func() {
+		defer close(handlerReturned)
+		request := httptest.NewRequest(http.MethodGet, "/users", nil)
+		request.Header.Set(constants.TestHeaderName, t.Name())
+		endpoint.ServeHTTP(httptest.NewRecorder(), request)
+	}()
+
+	// the request is waiting for a receive action when the test ends
+	for !hasTestChannel(endpoint, t.Name()) {
+		time.Sleep(time.Millisecond)
+	}
+	endpoint.testEnded(t.Name())
+	expectReturned(t, handlerReturned)
+}
+
+func TestHandlerOfUnknownTestReleased(t *testing.T) {
+	endpoint := NewEndpointBuilder().
+		Name("unknownTestServer").
+		IsolateTests().
+		InProcess().
+		Build()
+
+	handlerReturned := make(chan struct{})
+	go func() {
+		defer close(handlerReturned)
+		request := httptest.NewRequest(http.MethodGet, "/users", nil)
+		request.Header.Set(constants.TestHeaderName, "TestUnknown")
+		endpoint.ServeHTTP(httptest.NewRecorder(), request)
+	}()
+
+	expectReturned(t, handlerReturned)
+	if hasTestChannel(endpoint, "TestUnknown") {
+		t.Errorf("Channel was created for the unknown test %v", endpoint.testChannels)
+	}
+}
+
+func TestHandlerReleasedWhenEndpointCloses(t *testing.T) {
+	endpoint := NewEndpointBuilder().
+		Name("closedServer").
+		InProcess().
+		Build()
+
+	handlerReturned := make(chan struct{})
+	go func() {
+		defer close(handlerReturned)
+		endpoint.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	}()
+
+	if err := endpoint.Close(); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	expectReturned(t, handlerReturned)
+}
+
+func TestCloseLetsRequestsFinish(t *testing.T) {
+	endpoint := NewEndpointBuilder().
+		Name("gracefulServer").
+		Port(8111).
+		Build()
+	endpoint.AddStubs(&Stub{
+		Request:  message.Get("users"),
+		Response: message.Response(http.StatusOK).Payload("[]"),
+		Delay:    200 * time.Millisecond,
+	})
+
+	responseStatus := make(chan int, 1)
+	go func() {
+		response, err := http.Get("http://localhost:8111/users")
+		if err != nil {
+			responseStatus <- 0
+			return
+		}
+		_ = response.Body.Close()
+		responseStatus <- response.StatusCode
+	}()
+
+	// the endpoint is closed while the stub delays the response
+	for len(endpoint.Journal()) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if err := endpoint.Close(); err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if status := <-responseStatus; status != http.StatusOK {
+		t.Errorf("Expected the request to finish with 200, but got %d", status)
+	}
+}
+
+func TestHarRecordsExchangesNotHandledByTheTest(t *testing.T) {
+	recorder := har.NewRecorder()
+	endpoint := NewEndpointBuilder().
+		Name("harStubServer").
+		InProcess().
+		Har(recorder).
+		Build()
+
+	endpoint.AddStubs(&Stub{
+		Request:  message.Get("users"),
+		Response: message.Response(http.StatusOK).Payload("[]"),
+	})
+	endpoint.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	endpoint.InjectFault(&Fault{Status: http.StatusServiceUnavailable})
+	endpoint.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+
+	endpoint.InjectFault(&Fault{Abort: true})
+	func() {
+		defer func() {
+			if r := recover(); r != http.ErrAbortHandler {
+				t.Errorf("Expected the abort panic, but got [%v]", r)
+			}
+		}()
+		endpoint.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users", nil))
+	}()
+
+	entries := recorder.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("Expected 3 HAR entries, but got %d", len(entries))
+	}
+	if entries[0].Response.Status != http.StatusOK || entries[0].Response.Content.Text != "[]" {
+		t.Errorf("Unexpected stub response %+v", entries[0].Response)
+	}
+	if entries[1].Response.Status != http.StatusServiceUnavailable {
+		t.Errorf("Unexpected fault response %+v", entries[1].Response)
+	}
+	if entries[2].Response.Status != 0 {
+		t.Errorf("Expected the aborted exchange without status, but got %+v", entries[2].Response)
+	}
+}
+
+func hasTestChannel(endpoint *Endpoint, test string) bool {
+	endpoint.testChannelsLock.Lock()
+	defer endpoint.testChannelsLock.Unlock()
+
+	_, exists := endpoint.testChannels[test]
+	return exists
+}
+
+func expectReturned(t *testing.T, handlerReturned chan struct{}) {
+	t.Helper()
+	select {
+	case <-handlerReturned:
+	case <-time.After(time.Second):
+		t.Errorf("request handler was not released")
+	}
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"github.com/go-clarum/clarum-http/constants"
+	"github.com/go-clarum/clarum-http/internal/sse"
+	"github.com/go-clarum/clarum-http/message"
+	"testing"
+)
+
+// EventStream is an open text/event-stream response on a server endpoint without the context of a test.
+// Events are pushed to the client one by one, until the stream is closed.
+type EventStream struct {
+	endpoint *Endpoint
+	stream   *responseStream
+}
+
+// TestEventStream is an open text/event-stream response on a server endpoint with the context of a test.
+// Any error encountered while pushing events will fail the test by calling t.Error().
+type TestEventStream struct {
+	test *testing.T
+	EventStream
+}
+
+func (endpoint *Endpoint) sendEventStream(message *message.ResponseMessage, options sendOptions) (*EventStream, error) {
+	messageToSend := message.Clone()
+	if len(messageToSend.Headers[constants.ContentTypeHeaderName]) == 0 {
+		messageToSend.ContentType(sse.ContentType)
+	}
+	if len(messageToSend.Headers[constants.CacheControlHeaderName]) == 0 {
+		messageToSend.Header(constants.CacheControlHeaderName, "no-cache")
+	}
+
+	stream, err := endpoint.sendStream(messageToSend, options)
+	return &EventStream{
+		endpoint: endpoint,
+		stream:   stream,
+	}, err
+}
+
+// Event pushes a single event to the client & blocks until it was written.
+func (eventStream *EventStream) Event(event *message.EventMessage) error {
+	if eventStream.stream == nil {
+		return eventStream.endpoint.handleError("event stream is not open", nil)
+	}
+
+	eventStream.endpoint.logger.Infof("sending event %s", event.ToString())
+	if err := eventStream.stream.write(sse.Encode(event)); err != nil {
+		return eventStream.endpoint.handleError("could not send event", err)
+	}
+
+	return nil
+}
+
+// Close ends the response. The client will see the end of the stream.
+func (eventStream *EventStream) Close() {
+	if eventStream.stream != nil {
+		eventStream.stream.close()
+	}
+}
+
+func (testStream *TestEventStream) Event(event *message.EventMessage) {
+	if err := testStream.EventStream.Event(event); err != nil {
+		testStream.test.Error(err)
+	}
+}
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// Fault is injected into every request received by the endpoint, until it is cleared.
+// The delay is applied first; an aborted request is not answered at all.
+type Fault struct {
+	Delay time.Duration
+	// Status answers the request with this status code, instead of handling it
+	Status int
+	// Abort closes the connection without a response
+	Abort bool
+}
+
+func (endpoint *Endpoint) InjectFault(fault *Fault) {
+	endpoint.faultLock.Lock()
+	defer endpoint.faultLock.Unlock()
+
+	endpoint.fault = fault
+}
+
+func (endpoint *Endpoint) ClearFault() {
+	endpoint.InjectFault(nil)
+}
+
+// Fault returns the injected fault, nil if there is none
+func (endpoint *Endpoint) Fault() *Fault {
+	endpoint.faultLock.Lock()
+	defer endpoint.faultLock.Unlock()
+
+	return endpoint.fault
+}
+
+// applyFault reports whether the request was handled by the fault
+func (endpoint *Endpoint) applyFault(request *http.Request, resWriter http.ResponseWriter) bool {
+	fault := endpoint.Fault()
+	if fault == nil {
+		return false
+	}
+
+	if fault.Delay > 0 {
+		endpoint.logger.Infof("injected fault - delaying request by %s", fault.Delay)
+		select {
+		case <-time.After(fault.Delay):
+		case <-request.Context().Done():
+			return true
+		}
+	}
+
+	if fault.Abort {
+		endpoint.logger.Info("injected fault - aborting request")
+		// the server closes the connection, or resets the stream for HTTP/2, without logging
+		panic(http.ErrAbortHandler)
+	} else if fault.Status != 0 {
+		endpoint.logger.Infof("injected fault - answering with status %d", fault.Status)
+		resWriter.WriteHeader(fault.Status)
+		logOutgoingResponse(endpoint.logger, fault.Status, "", resWriter)
+		return true
+	}
+
+	return false
+}
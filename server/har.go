@@ -0,0 +1,83 @@
+package server
+
+import (
+	"github.com/go-clarum/clarum-http/har"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// harWriter captures the response written by the endpoint, whichever way the request is answered
+// (test action, stub, mock, replay or fault)
+type harWriter struct {
+	http.ResponseWriter
+	statusCode int
+	body       strings.Builder
+	streamed   bool
+}
+
+func (writer *harWriter) WriteHeader(statusCode int) {
+	if writer.statusCode == 0 {
+		writer.statusCode = statusCode
+	}
+	writer.ResponseWriter.WriteHeader(statusCode)
+}
+
+func (writer *harWriter) Write(data []byte) (int, error) {
+	if writer.statusCode == 0 {
+		writer.statusCode = http.StatusOK
+	}
+	if !writer.streamed {
+		writer.body.Write(data)
+	}
+	return writer.ResponseWriter.Write(data)
+}
+
+// Flush is only called by streamed responses, whose body is not recorded
+func (writer *harWriter) Flush() {
+	writer.streamed = true
+	writer.body.Reset()
+	flush(writer.ResponseWriter)
+}
+
+func (writer *harWriter) Unwrap() http.ResponseWriter {
+	return writer.ResponseWriter
+}
+
+// recordHar adds the exchange to the HAR recorder of the endpoint once the handler is done.
+// It is deferred by ServeHTTP, so that every exchange is recorded; an aborted exchange is recorded
+// with the status 0. On the server side only the total time is known, so it is recorded as the wait time.
+// The body of a stream is not recorded.
+func (endpoint *Endpoint) recordHar(request *http.Request, requestPayload string, writer *harWriter,
+	requestArrival time.Time) {
+	aborted := recover()
+	statusCode := writer.statusCode
+	if aborted != nil {
+		statusCode = 0
+	} else if statusCode == 0 {
+		// the server answers with an empty 200 if the handler did not write anything
+		statusCode = http.StatusOK
+	}
+
+	total := time.Since(requestArrival)
+	endpoint.harRecorder.Add(har.Entry{
+		StartedDateTime: requestArrival,
+		Time:            har.Milliseconds(total),
+		Request:         har.NewRequest(request, requestPayload),
+		Response:        har.NewResponse(statusCode, request.Proto, writer.Header(), writer.body.String()),
+		Timings: har.Timings{
+			Blocked: -1,
+			DNS:     -1,
+			Connect: -1,
+			Ssl:     -1,
+			Send:    0,
+			Wait:    har.Milliseconds(total),
+			Receive: 0,
+		},
+	})
+
+	// the panic is passed on, so that the server can close the connection
+	if aborted != nil {
+		panic(aborted)
+	}
+}
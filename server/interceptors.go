@@ -0,0 +1,39 @@
+package server
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"net/http"
+)
+
+// RequestInterceptor is called for every incoming request, before it is handed over to a receive action.
+// Returning an error will make the endpoint answer with a default error response.
+type RequestInterceptor func(request *http.Request) error
+
+// ResponseInterceptor is called right before a response is written. It may modify the response message,
+// for example to inject headers. Returning an error will make the endpoint answer with a default error response.
+type ResponseInterceptor func(request *http.Request, response *message.ResponseMessage) error
+
+type interceptors struct {
+	afterReceive []RequestInterceptor
+	beforeSend   []ResponseInterceptor
+}
+
+func (interceptors *interceptors) interceptRequest(request *http.Request) error {
+	for _, interceptor := range interceptors.afterReceive {
+		if err := interceptor(request); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (interceptors *interceptors) interceptResponse(request *http.Request, response *message.ResponseMessage) error {
+	for _, interceptor := range interceptors.beforeSend {
+		if err := interceptor(request, response); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"net/http"
+	"time"
+)
+
+// the oldest entries are dropped when the journal is full
+const journalCapacity = 1000
+
+// JournalEntry is a request received by the endpoint, regardless of how it was answered
+type JournalEntry struct {
+	ReceivedAt time.Time   `json:"receivedAt"`
+	Method     string      `json:"method"`
+	Url        string      `json:"url"`
+	Headers    http.Header `json:"headers"`
+	Payload    string      `json:"payload,omitempty"`
+}
+
+// Journal returns the received requests, the oldest first
+func (endpoint *Endpoint) Journal() []JournalEntry {
+	endpoint.journalLock.Lock()
+	defer endpoint.journalLock.Unlock()
+
+	return append([]JournalEntry(nil), endpoint.journal...)
+}
+
+func (endpoint *Endpoint) ClearJournal() {
+	endpoint.journalLock.Lock()
+	defer endpoint.journalLock.Unlock()
+
+	endpoint.journal = nil
+}
+
+func (endpoint *Endpoint) addToJournal(request *http.Request, payload string, receivedAt time.Time) {
+	endpoint.journalLock.Lock()
+	defer endpoint.journalLock.Unlock()
+
+	if len(endpoint.journal) == journalCapacity {
+		endpoint.journal = endpoint.journal[1:]
+	}
+	endpoint.journal = append(endpoint.journal, JournalEntry{
+		ReceivedAt: receivedAt,
+		Method:     request.Method,
+		Url:        request.URL.String(),
+		Headers:    request.Header.Clone(),
+		Payload:    payload,
+	})
+}
@@ -0,0 +1,71 @@
+package server
+
+import (
+	"net/http"
+)
+
+// Override passes the requests of the given operations to the receive & send actions of the test,
+// instead of answering them from the spec of a mock endpoint.
+func (endpoint *Endpoint) Override(operationIds ...string) {
+	endpoint.overridesLock.Lock()
+	defer endpoint.overridesLock.Unlock()
+
+	for _, operationId := range operationIds {
+		endpoint.overrides[operationId] = true
+	}
+}
+
+// RemoveOverrides makes the mock endpoint answer the given operations from the spec again,
+// unless a running test still overrides them
+func (endpoint *Endpoint) RemoveOverrides(operationIds ...string) {
+	endpoint.overridesLock.Lock()
+	defer endpoint.overridesLock.Unlock()
+
+	for _, operationId := range operationIds {
+		delete(endpoint.overrides, operationId)
+	}
+}
+
+// Override passes the requests of the given operations to the actions of the test, until the test ends.
+// The overrides are counted, so that the end of a test does not remove the overrides of the endpoint or of other tests.
+func (testBuilder *TestActionBuilder) Override(operationIds ...string) {
+	endpoint := testBuilder.endpoint
+	endpoint.countOverrides(operationIds, 1)
+	testBuilder.test.Cleanup(func() {
+		endpoint.countOverrides(operationIds, -1)
+	})
+}
+
+func (endpoint *Endpoint) countOverrides(operationIds []string, delta int) {
+	endpoint.overridesLock.Lock()
+	defer endpoint.overridesLock.Unlock()
+
+	for _, operationId := range operationIds {
+		endpoint.testOverrides[operationId] += delta
+		if endpoint.testOverrides[operationId] <= 0 {
+			delete(endpoint.testOverrides, operationId)
+		}
+	}
+}
+
+func (endpoint *Endpoint) isOverridden(request *http.Request) bool {
+	operationId := endpoint.mockSpec.OperationId(request)
+
+	endpoint.overridesLock.Lock()
+	defer endpoint.overridesLock.Unlock()
+
+	return endpoint.overrides[operationId] || endpoint.testOverrides[operationId] > 0
+}
+
+// mock answers the request from the spec, without involving the test
+func (endpoint *Endpoint) mock(request *http.Request, resWriter http.ResponseWriter) {
+	response := endpoint.mockSpec.MockResponse(request)
+	if response.StatusCode >= http.StatusBadRequest {
+		endpoint.logger.Errorf("mocked request is invalid [method: %s, url: %s] - %s",
+			request.Method, request.URL, response.MessagePayload)
+	} else {
+		endpoint.logger.Infof("mocking operation [%s]", endpoint.mockSpec.OperationId(request))
+	}
+
+	sendResponse(endpoint.logger, &sendPair{response: response}, resWriter)
+}
@@ -1,14 +1,21 @@
 package server
 
 import (
+	"context"
 	"github.com/go-clarum/clarum-http/internal"
 	"github.com/go-clarum/clarum-http/message"
 	"net/http"
 	"testing"
+	"time"
 )
 
 type receiveOptions struct {
 	expectedPayloadType internal.PayloadType
+	expectedProto       string
+	// test receives the requests of this test first, when the endpoint isolates tests
+	test    string
+	ctx     context.Context
+	timeout time.Duration
 }
 
 // ReceiveActionBuilder used to configure a receive action on a server endpoint without the context of a test
@@ -37,6 +44,44 @@ func (builder *ReceiveActionBuilder) Json() *ReceiveActionBuilder {
 	return builder
 }
 
+// Proto validates the protocol of the request, for example 'HTTP/1.1' or 'HTTP/2.0'.
+func (testBuilder *TestReceiveActionBuilder) Proto(proto string) *TestReceiveActionBuilder {
+	testBuilder.options.expectedProto = proto
+	return testBuilder
+}
+
+// Proto validates the protocol of the request, for example 'HTTP/1.1' or 'HTTP/2.0'.
+func (builder *ReceiveActionBuilder) Proto(proto string) *ReceiveActionBuilder {
+	builder.options.expectedProto = proto
+	return builder
+}
+
+// Context cancels the action when the context ends. A request that was received is not answered anymore by
+// its handler once the context ends. The context of the test is used by default.
+func (testBuilder *TestReceiveActionBuilder) Context(ctx context.Context) *TestReceiveActionBuilder {
+	testBuilder.options.ctx = ctx
+	return testBuilder
+}
+
+// Context cancels the action when the context ends. A request that was received is not answered anymore by
+// its handler once the context ends.
+func (builder *ReceiveActionBuilder) Context(ctx context.Context) *ReceiveActionBuilder {
+	builder.options.ctx = ctx
+	return builder
+}
+
+// Timeout overrides the action timeout of the config for this action: how long it waits for a request.
+func (testBuilder *TestReceiveActionBuilder) Timeout(timeout time.Duration) *TestReceiveActionBuilder {
+	testBuilder.options.timeout = timeout
+	return testBuilder
+}
+
+// Timeout overrides the action timeout of the config for this action: how long it waits for a request.
+func (builder *ReceiveActionBuilder) Timeout(timeout time.Duration) *ReceiveActionBuilder {
+	builder.options.timeout = timeout
+	return builder
+}
+
 func (testBuilder *TestReceiveActionBuilder) Message(message *message.RequestMessage) {
 	if _, err := testBuilder.endpoint.receive(message, *testBuilder.options); err != nil {
 		testBuilder.test.Error(err)
@@ -0,0 +1,98 @@
+package server
+
+import (
+	"github.com/go-clarum/clarum-http/message"
+	"maps"
+	"testing"
+	"time"
+)
+
+// ResponseStream is an open streamed response on a server endpoint without the context of a test.
+// The body is written chunk by chunk, each chunk being flushed to the client right away.
+type ResponseStream struct {
+	endpoint *Endpoint
+	stream   *responseStream
+	trailers map[string]string
+}
+
+// TestResponseStream is an open streamed response on a server endpoint with the context of a test.
+// Any error encountered while writing will fail the test by calling t.Error().
+type TestResponseStream struct {
+	test *testing.T
+	ResponseStream
+}
+
+func (endpoint *Endpoint) sendResponseStream(message *message.ResponseMessage, options sendOptions) (*ResponseStream, error) {
+	stream, err := endpoint.sendStream(message, options)
+	return &ResponseStream{
+		endpoint: endpoint,
+		stream:   stream,
+	}, err
+}
+
+// Chunk writes & flushes a part of the body. It blocks until the part was written.
+func (responseStream *ResponseStream) Chunk(data string) error {
+	if responseStream.stream == nil {
+		return responseStream.endpoint.handleError("response stream is not open", nil)
+	}
+
+	responseStream.endpoint.logger.Infof("sending chunk [%s]", data)
+	if err := responseStream.stream.write(data); err != nil {
+		return responseStream.endpoint.handleError("could not send chunk", err)
+	}
+
+	return nil
+}
+
+// Record writes & flushes a line-delimited record (NDJSON for example). A line break is appended to the data.
+func (responseStream *ResponseStream) Record(data string) error {
+	return responseStream.Chunk(data + "\n")
+}
+
+// Pause waits before the next part is written, to simulate a slow producer.
+func (responseStream *ResponseStream) Pause(duration time.Duration) {
+	time.Sleep(duration)
+}
+
+// Trailer sets a trailer which will be sent after the body, when the stream is closed.
+func (responseStream *ResponseStream) Trailer(key string, value string) {
+	if responseStream.trailers == nil {
+		responseStream.trailers = make(map[string]string)
+	}
+	responseStream.trailers[key] = value
+}
+
+// Close finishes the response, sending the trailers if any were set. Closing a closed stream does nothing.
+func (responseStream *ResponseStream) Close() error {
+	if responseStream.stream == nil || responseStream.stream.isClosed() {
+		return nil
+	}
+
+	if len(responseStream.trailers) > 0 {
+		if err := responseStream.stream.writeTrailers(maps.Clone(responseStream.trailers)); err != nil {
+			responseStream.stream.close()
+			return responseStream.endpoint.handleError("could not send trailers", err)
+		}
+	}
+
+	responseStream.stream.close()
+	return nil
+}
+
+func (testStream *TestResponseStream) Chunk(data string) {
+	if err := testStream.ResponseStream.Chunk(data); err != nil {
+		testStream.test.Error(err)
+	}
+}
+
+func (testStream *TestResponseStream) Record(data string) {
+	if err := testStream.ResponseStream.Record(data); err != nil {
+		testStream.test.Error(err)
+	}
+}
+
+func (testStream *TestResponseStream) Close() {
+	if err := testStream.ResponseStream.Close(); err != nil {
+		testStream.test.Error(err)
+	}
+}
@@ -0,0 +1,79 @@
+package server
+
+// ScenarioStarted is the state of every scenario before any of its stubs changed it
+const ScenarioStarted = "Started"
+
+// ScenarioState returns the current state of the scenario
+func (endpoint *Endpoint) ScenarioState(scenario string) string {
+	endpoint.scenariosLock.Lock()
+	defer endpoint.scenariosLock.Unlock()
+
+	return endpoint.scenarioState(scenario)
+}
+
+// SetScenarioState moves the scenario into a state, for example to start a test in the middle of a flow
+func (endpoint *Endpoint) SetScenarioState(scenario string, state string) {
+	endpoint.scenariosLock.Lock()
+	defer endpoint.scenariosLock.Unlock()
+
+	endpoint.scenarios[scenario] = state
+}
+
+// Scenarios returns the current state of all scenarios used by the stubs or set on the endpoint
+func (endpoint *Endpoint) Scenarios() map[string]string {
+	stubs := endpoint.Stubs()
+
+	endpoint.scenariosLock.Lock()
+	defer endpoint.scenariosLock.Unlock()
+
+	result := make(map[string]string)
+	for _, stub := range stubs {
+		if stub.Scenario != "" {
+			result[stub.Scenario] = endpoint.scenarioState(stub.Scenario)
+		}
+	}
+	for scenario, state := range endpoint.scenarios {
+		result[scenario] = state
+	}
+	return result
+}
+
+// ResetScenarios moves all scenarios back into the ScenarioStarted state
+func (endpoint *Endpoint) ResetScenarios() {
+	endpoint.scenariosLock.Lock()
+	defer endpoint.scenariosLock.Unlock()
+
+	clear(endpoint.scenarios)
+}
+
+// ResetScenario moves the scenario back into the ScenarioStarted state
+func (endpoint *Endpoint) ResetScenario(scenario string) {
+	endpoint.scenariosLock.Lock()
+	defer endpoint.scenariosLock.Unlock()
+
+	delete(endpoint.scenarios, scenario)
+}
+
+func (endpoint *Endpoint) scenarioState(scenario string) string {
+	if state, exists := endpoint.scenarios[scenario]; exists {
+		return state
+	}
+	return ScenarioStarted
+}
+
+// inState reports whether the scenario of the stub is in the state the stub requires
+func (endpoint *Endpoint) inState(stub *Stub) bool {
+	return stub.Scenario == "" || stub.RequiredState == "" ||
+		endpoint.scenarioState(stub.Scenario) == stub.RequiredState
+}
+
+// transition moves the scenario of a matched stub into its new state
+func (endpoint *Endpoint) transition(stub *Stub) {
+	if stub.Scenario == "" || stub.NewState == "" {
+		return
+	}
+
+	from := endpoint.scenarioState(stub.Scenario)
+	endpoint.scenarios[stub.Scenario] = stub.NewState
+	endpoint.logger.Infof("scenario [%s] moved from state [%s] to [%s]", stub.Scenario, from, stub.NewState)
+}
@@ -1,8 +1,10 @@
 package server
 
 import (
+	"context"
 	"github.com/go-clarum/clarum-http/message"
 	"testing"
+	"time"
 )
 
 // SendActionBuilder used to configure a send action on a server endpoint without the context of a test
@@ -10,6 +12,15 @@ import (
 // The error will be a problem encountered during sending.
 type SendActionBuilder struct {
 	endpoint *Endpoint
+	options  sendOptions
+}
+
+// sendOptions apply to a single send action
+type sendOptions struct {
+	// test answers only the requests of this test, when the endpoint isolates tests
+	test    string
+	ctx     context.Context
+	timeout time.Duration
 }
 
 // TestSendActionBuilder used to configure a send action on a server endpoint with the context of a test
@@ -20,12 +31,76 @@ type TestSendActionBuilder struct {
 	SendActionBuilder
 }
 
+// Context cancels the action when the context ends. The context of the test is used by default.
+func (testBuilder *TestSendActionBuilder) Context(ctx context.Context) *TestSendActionBuilder {
+	testBuilder.options.ctx = ctx
+	return testBuilder
+}
+
+// Context cancels the action when the context ends.
+func (builder *SendActionBuilder) Context(ctx context.Context) *SendActionBuilder {
+	builder.options.ctx = ctx
+	return builder
+}
+
+// Timeout overrides the action timeout of the config for this action: how long it waits for a request to answer.
+func (testBuilder *TestSendActionBuilder) Timeout(timeout time.Duration) *TestSendActionBuilder {
+	testBuilder.options.timeout = timeout
+	return testBuilder
+}
+
+// Timeout overrides the action timeout of the config for this action: how long it waits for a request to answer.
+func (builder *SendActionBuilder) Timeout(timeout time.Duration) *SendActionBuilder {
+	builder.options.timeout = timeout
+	return builder
+}
+
 func (testBuilder *TestSendActionBuilder) Message(message *message.ResponseMessage) {
-	if err := testBuilder.endpoint.send(message); err != nil {
+	if err := testBuilder.endpoint.send(message, testBuilder.options); err != nil {
 		testBuilder.test.Error(err)
 	}
 }
 
 func (builder *SendActionBuilder) Message(message *message.ResponseMessage) error {
-	return builder.endpoint.send(message)
+	return builder.endpoint.send(message, builder.options)
+}
+
+// EventStream opens a text/event-stream response. The headers are sent right away,
+// after which events can be pushed through the returned stream until it is closed.
+func (testBuilder *TestSendActionBuilder) EventStream(message *message.ResponseMessage) *TestEventStream {
+	eventStream, err := testBuilder.endpoint.sendEventStream(message, testBuilder.options)
+	if err != nil {
+		testBuilder.test.Error(err)
+	}
+
+	return &TestEventStream{
+		test:        testBuilder.test,
+		EventStream: *eventStream,
+	}
+}
+
+// EventStream opens a text/event-stream response. The headers are sent right away,
+// after which events can be pushed through the returned stream until it is closed.
+func (builder *SendActionBuilder) EventStream(message *message.ResponseMessage) (*EventStream, error) {
+	return builder.endpoint.sendEventStream(message, builder.options)
+}
+
+// Stream opens a streamed response. The headers are sent right away,
+// after which the body can be written chunk by chunk through the returned stream until it is closed.
+func (testBuilder *TestSendActionBuilder) Stream(message *message.ResponseMessage) *TestResponseStream {
+	responseStream, err := testBuilder.endpoint.sendResponseStream(message, testBuilder.options)
+	if err != nil {
+		testBuilder.test.Error(err)
+	}
+
+	return &TestResponseStream{
+		test:           testBuilder.test,
+		ResponseStream: *responseStream,
+	}
+}
+
+// Stream opens a streamed response. The headers are sent right away,
+// after which the body can be written chunk by chunk through the returned stream until it is closed.
+func (builder *SendActionBuilder) Stream(message *message.ResponseMessage) (*ResponseStream, error) {
+	return builder.endpoint.sendResponseStream(message, builder.options)
 }
@@ -0,0 +1,161 @@
+package server
+
+import (
+	"errors"
+	"github.com/go-clarum/clarum-core/config"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/message"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// responseStream is used to write a response in multiple parts, under the control of the test.
+// The request handler writes & flushes each part as soon as it is received.
+type responseStream struct {
+	parts chan *streamPart
+	// closed is closed by the test when the stream ends, parts is never closed so that a late write cannot panic
+	closed    chan struct{}
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type streamPart struct {
+	data     string
+	trailers map[string]string
+	result   chan error
+}
+
+func newResponseStream() *responseStream {
+	return &responseStream{
+		parts:  make(chan *streamPart),
+		closed: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+}
+
+// write blocks until the part was written by the request handler
+func (stream *responseStream) write(data string) error {
+	return stream.writePart(&streamPart{
+		data:   data,
+		result: make(chan error, 1),
+	})
+}
+
+// writeTrailers sets trailers which will be sent after the body, when the stream is closed
+func (stream *responseStream) writeTrailers(trailers map[string]string) error {
+	return stream.writePart(&streamPart{
+		trailers: trailers,
+		result:   make(chan error, 1),
+	})
+}
+
+func (stream *responseStream) writePart(part *streamPart) error {
+	if stream.isClosed() {
+		return errors.New("stream has already ended")
+	}
+
+	select {
+	case stream.parts <- part:
+		return <-part.result
+	case <-stream.closed:
+		return errors.New("stream has already ended")
+	case <-stream.done:
+		return errors.New("stream has already ended")
+	case <-time.After(config.ActionTimeout()):
+		return errors.New("stream write timed out")
+	}
+}
+
+func (stream *responseStream) close() {
+	stream.closeOnce.Do(func() {
+		close(stream.closed)
+	})
+}
+
+func (stream *responseStream) isClosed() bool {
+	select {
+	case <-stream.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// sendStream hands over a stream to the request handler, which will send the headers of the response right away.
+// The body is written part by part through the returned stream.
+func (endpoint *Endpoint) sendStream(message *message.ResponseMessage, options sendOptions) (*responseStream, error) {
+	messageToSend := endpoint.getMessageToSend(message)
+	err := endpoint.validateMessageToSend(messageToSend)
+
+	stream := newResponseStream()
+	toSend := &sendPair{
+		response: messageToSend,
+		stream:   stream,
+		error:    err,
+	}
+
+	if dispatchErr := endpoint.dispatch(toSend, options); dispatchErr != nil {
+		return nil, dispatchErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return stream, nil
+}
+
+func writeStream(logger *logging.Logger, request *http.Request, sendPair *sendPair, resWriter http.ResponseWriter) {
+	stream := sendPair.stream
+
+	for header, value := range sendPair.response.Headers {
+		resWriter.Header().Set(header, value)
+	}
+	resWriter.WriteHeader(sendPair.response.StatusCode)
+	flush(resWriter)
+
+	logger.Infof("sending stream response ["+
+		"status: %d, "+
+		"headers: %s"+
+		"]",
+		sendPair.response.StatusCode, resWriter.Header())
+
+	for {
+		select {
+		case <-stream.closed:
+			logger.Info("stream response closed")
+			return
+		case part := <-stream.parts:
+			if part.trailers != nil {
+				// trailers are sent by the server when the handler returns
+				for trailer, value := range part.trailers {
+					resWriter.Header().Set(http.TrailerPrefix+trailer, value)
+				}
+				logger.Infof("set stream trailers %s", part.trailers)
+				part.result <- nil
+				continue
+			}
+
+			_, err := io.WriteString(resWriter, part.data)
+			if err != nil {
+				logger.Errorf("could not write stream part - %s", err)
+			} else {
+				flush(resWriter)
+				logger.Debugf("sent stream part [%s]", part.data)
+			}
+			part.result <- err
+		case <-request.Context().Done():
+			logger.Warn("stream response ended - client disconnected")
+			return
+		case <-time.After(config.ActionTimeout()):
+			logger.Warn("stream response timed out - no stream action called in test")
+			return
+		}
+	}
+}
+
+func flush(resWriter http.ResponseWriter) {
+	if flusher, ok := resWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
@@ -0,0 +1,160 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/internal"
+	"github.com/go-clarum/clarum-http/internal/validators"
+	"github.com/go-clarum/clarum-http/message"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// matching a request against a stub is not a validation, so the mismatches are not logged
+var silentLogger = logging.NewLogger(slog.LevelError+4, "")
+
+// Stub answers the requests it matches, without involving the test. A request is matched the same way a receive
+// action validates it: only the parts set on the request message are compared. A blank method or path matches any.
+//
+// Stubs of a scenario form a state machine: a stub with a RequiredState only matches while its scenario is in that
+// state, and a matched stub with a NewState moves the scenario into it. Every scenario starts in ScenarioStarted.
+type Stub struct {
+	Name     string
+	Request  *message.RequestMessage
+	Response *message.ResponseMessage
+	// Json compares the payload as JSON instead of plain text
+	Json          bool
+	Delay         time.Duration
+	Scenario      string
+	RequiredState string
+	NewState      string
+}
+
+// AddStubs adds stubs after the existing ones; the first stub that matches a request answers it.
+// A stub replaces an existing stub with the same name.
+func (endpoint *Endpoint) AddStubs(stubs ...*Stub) {
+	endpoint.stubsLock.Lock()
+	defer endpoint.stubsLock.Unlock()
+
+	endpoint.addStubs(stubs)
+}
+
+// ReplaceStubs removes all stubs and adds the given ones at once, so that no request is handled without stubs
+func (endpoint *Endpoint) ReplaceStubs(stubs ...*Stub) {
+	endpoint.stubsLock.Lock()
+	defer endpoint.stubsLock.Unlock()
+
+	endpoint.stubs = nil
+	endpoint.addStubs(stubs)
+}
+
+func (endpoint *Endpoint) addStubs(stubs []*Stub) {
+	for _, stub := range stubs {
+		replaced := false
+		for i, existing := range endpoint.stubs {
+			if stub.Name != "" && existing.Name == stub.Name {
+				endpoint.stubs[i] = stub
+				replaced = true
+			}
+		}
+		if !replaced {
+			endpoint.stubs = append(endpoint.stubs, stub)
+		}
+	}
+}
+
+// RemoveStub removes the stub with the given name and reports whether it existed
+func (endpoint *Endpoint) RemoveStub(name string) bool {
+	endpoint.stubsLock.Lock()
+	defer endpoint.stubsLock.Unlock()
+
+	for i, existing := range endpoint.stubs {
+		if existing.Name == name {
+			endpoint.stubs = append(endpoint.stubs[:i], endpoint.stubs[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+func (endpoint *Endpoint) ClearStubs() {
+	endpoint.stubsLock.Lock()
+	defer endpoint.stubsLock.Unlock()
+
+	endpoint.stubs = nil
+}
+
+// Stubs returns the stubs of the endpoint, in the order they are matched
+func (endpoint *Endpoint) Stubs() []*Stub {
+	endpoint.stubsLock.Lock()
+	defer endpoint.stubsLock.Unlock()
+
+	return append([]*Stub(nil), endpoint.stubs...)
+}
+
+// matchStub returns the first stub matching the request & moves its scenario into the new state.
+// The scenarios are locked while matching, so concurrent requests see the states one after the other.
+func (endpoint *Endpoint) matchStub(request *http.Request, payload string) *Stub {
+	stubs := endpoint.Stubs()
+
+	endpoint.scenariosLock.Lock()
+	defer endpoint.scenariosLock.Unlock()
+
+	for _, stub := range stubs {
+		if endpoint.inState(stub) && stub.matches(request, payload) {
+			endpoint.transition(stub)
+			return stub
+		}
+	}
+	return nil
+}
+
+func (stub *Stub) matches(request *http.Request, payload string) bool {
+	if stub.Request == nil {
+		return true
+	}
+
+	payloadType := internal.Plaintext
+	if stub.Json {
+		payloadType = internal.Json
+	}
+
+	var methodErr, pathErr error
+	if stub.Request.Method != "" {
+		methodErr = validators.ValidateHttpMethod(stub.Request, request.Method, silentLogger)
+	}
+	if stub.Request.Path != "" {
+		pathErr = validators.ValidatePath(stub.Request, request.URL, silentLogger)
+	}
+
+	return errors.Join(
+		methodErr,
+		pathErr,
+		validators.ValidateHttpHeaders(&stub.Request.Message, request.Header, silentLogger),
+		validators.ValidateHttpQueryParams(stub.Request, request.URL, silentLogger),
+		validators.ValidateHttpPayload(&stub.Request.Message, io.NopCloser(bytes.NewBufferString(payload)),
+			payloadType, silentLogger)) == nil
+}
+
+// respondWithStub answers the request with the response of the stub, after its delay
+func (endpoint *Endpoint) respondWithStub(stub *Stub, request *http.Request, resWriter http.ResponseWriter) {
+	endpoint.logger.Infof("request matches stub [%s]", stub.Name)
+
+	response := message.Response(http.StatusOK)
+	if stub.Response != nil {
+		response = endpoint.getMessageToSend(stub.Response)
+	}
+
+	if stub.Delay > 0 {
+		select {
+		case <-time.After(stub.Delay):
+		case <-request.Context().Done():
+			return
+		}
+	}
+
+	sendResponse(endpoint.logger, &sendPair{response: response}, resWriter)
+}
@@ -12,7 +12,10 @@ type TestActionBuilder struct {
 	endpoint *Endpoint
 }
 
+// In runs the actions in the context of the test. The test fails when it ends, if a request that arrived during
+// the test was never received by a receive action, or never answered by a send action.
 func (endpoint *Endpoint) In(t *testing.T) *TestActionBuilder {
+	endpoint.tracker.Watch(t, endpoint.logger.Prefix(), func() { endpoint.testEnded(t.Name()) })
 	return &TestActionBuilder{
 		test:     t,
 		endpoint: endpoint,
@@ -39,6 +42,7 @@ func (testBuilder *TestActionBuilder) Send() *TestSendActionBuilder {
 		test: testBuilder.test,
 		SendActionBuilder: SendActionBuilder{
 			endpoint: testBuilder.endpoint,
+			options:  sendOptions{test: testBuilder.isolatedTest(), ctx: testBuilder.test.Context()},
 		},
 	}
 }
@@ -50,7 +54,24 @@ func (testBuilder *TestActionBuilder) Receive() *TestReceiveActionBuilder {
 			endpoint: testBuilder.endpoint,
 			options: &receiveOptions{
 				expectedPayloadType: internal.Plaintext,
+				test:                testBuilder.isolatedTest(),
+				ctx:                 testBuilder.test.Context(),
 			},
 		},
 	}
 }
+
+// isolatedTest is the name of the test, if the endpoint isolates tests
+func (testBuilder *TestActionBuilder) isolatedTest() string {
+	if !testBuilder.endpoint.isolateTests {
+		return ""
+	}
+	return testBuilder.test.Name()
+}
+
+// testEnded is called once at the end of every test that used the endpoint
+func (endpoint *Endpoint) testEnded(test string) {
+	if endpoint.isolateTests {
+		endpoint.removeTestChannel(test)
+	}
+}
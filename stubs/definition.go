@@ -0,0 +1,139 @@
+package stubs
+
+import (
+	"fmt"
+	"github.com/go-clarum/clarum-http/message"
+	"github.com/go-clarum/clarum-http/server"
+	"net/http"
+	"time"
+)
+
+// Definition describes a server stub in YAML or JSON, for tools that do not use the Go API
+type Definition struct {
+	Name     string             `yaml:"name" json:"name"`
+	Request  RequestDefinition  `yaml:"request" json:"request"`
+	Response ResponseDefinition `yaml:"response" json:"response"`
+	// Scenario, RequiredState & NewState make the stub part of a scenario, see server.Stub
+	Scenario      string `yaml:"scenario" json:"scenario,omitempty"`
+	RequiredState string `yaml:"requiredState" json:"requiredState,omitempty"`
+	NewState      string `yaml:"newState" json:"newState,omitempty"`
+}
+
+type RequestDefinition struct {
+	Method  string            `yaml:"method" json:"method,omitempty"`
+	Path    string            `yaml:"path" json:"path,omitempty"`
+	Query   map[string]string `yaml:"query" json:"query,omitempty"`
+	Headers map[string]string `yaml:"headers" json:"headers,omitempty"`
+	Payload string            `yaml:"payload" json:"payload,omitempty"`
+	// Json compares the payload as JSON instead of plain text
+	Json bool `yaml:"json" json:"json,omitempty"`
+}
+
+type ResponseDefinition struct {
+	Status  int               `yaml:"status" json:"status,omitempty"`
+	Headers map[string]string `yaml:"headers" json:"headers,omitempty"`
+	Payload string            `yaml:"payload" json:"payload,omitempty"`
+	// Delay before the response is sent, like '250ms'
+	Delay string `yaml:"delay" json:"delay,omitempty"`
+}
+
+// Stub converts the definition into a stub of a server endpoint. A missing status means 200 (OK).
+func (definition *Definition) Stub() (*server.Stub, error) {
+	request := &message.RequestMessage{
+		Method: definition.Request.Method,
+		Path:   definition.Request.Path,
+	}
+	for key, value := range definition.Request.Query {
+		request.QueryParam(key, value)
+	}
+	for header, value := range definition.Request.Headers {
+		request.Header(header, value)
+	}
+	if definition.Request.Payload != "" {
+		request.Payload(definition.Request.Payload)
+	}
+
+	status := definition.Response.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	response := message.Response(status)
+	for header, value := range definition.Response.Headers {
+		response.Header(header, value)
+	}
+	if definition.Response.Payload != "" {
+		response.Payload(definition.Response.Payload)
+	}
+
+	var delay time.Duration
+	if definition.Response.Delay != "" {
+		var err error
+		if delay, err = time.ParseDuration(definition.Response.Delay); err != nil {
+			return nil, fmt.Errorf("invalid delay of stub [%s] - %w", definition.Name, err)
+		}
+	}
+
+	return &server.Stub{
+		Name:          definition.Name,
+		Request:       request,
+		Response:      response,
+		Json:          definition.Request.Json,
+		Delay:         delay,
+		Scenario:      definition.Scenario,
+		RequiredState: definition.RequiredState,
+		NewState:      definition.NewState,
+	}, nil
+}
+
+// Stubs converts all definitions, in their order
+func Stubs(definitions []Definition) ([]*server.Stub, error) {
+	result := make([]*server.Stub, 0, len(definitions))
+	for _, definition := range definitions {
+		stub, err := definition.Stub()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, stub)
+	}
+	return result, nil
+}
+
+// FromStub describes an existing stub, the reverse of Stub()
+func FromStub(stub *server.Stub) Definition {
+	definition := Definition{
+		Name:          stub.Name,
+		Scenario:      stub.Scenario,
+		RequiredState: stub.RequiredState,
+		NewState:      stub.NewState,
+	}
+
+	if stub.Request != nil {
+		definition.Request = RequestDefinition{
+			Method:  stub.Request.Method,
+			Path:    stub.Request.Path,
+			Headers: stub.Request.Headers,
+			Payload: stub.Request.MessagePayload,
+			Json:    stub.Json,
+		}
+		if len(stub.Request.QueryParams) > 0 {
+			definition.Request.Query = make(map[string]string)
+			for key, values := range stub.Request.QueryParams {
+				if len(values) > 0 {
+					definition.Request.Query[key] = values[0]
+				}
+			}
+		}
+	}
+	if stub.Response != nil {
+		definition.Response = ResponseDefinition{
+			Status:  stub.Response.StatusCode,
+			Headers: stub.Response.Headers,
+			Payload: stub.Response.MessagePayload,
+		}
+	}
+	if stub.Delay > 0 {
+		definition.Response.Delay = stub.Delay.String()
+	}
+
+	return definition
+}
@@ -0,0 +1,74 @@
+package stubs
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestStub(t *testing.T) {
+	definition := Definition{
+		Name: "get-user",
+		Request: RequestDefinition{
+			Method:  http.MethodGet,
+			Path:    "/users/1",
+			Query:   map[string]string{"details": "true"},
+			Headers: map[string]string{"Accept": "application/json"},
+		},
+		Response: ResponseDefinition{
+			Headers: map[string]string{"Content-Type": "application/json"},
+			Payload: "{\"id\": 1}",
+			Delay:   "250ms",
+		},
+	}
+
+	stub, err := definition.Stub()
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if stub.Name != "get-user" || stub.Request.Method != http.MethodGet || stub.Request.Path != "/users/1" ||
+		stub.Request.QueryParams["details"][0] != "true" || stub.Request.Headers["Accept"] != "application/json" {
+		t.Errorf("Unexpected request %s", stub.Request.ToString())
+	}
+	if stub.Response.StatusCode != http.StatusOK || stub.Response.MessagePayload != "{\"id\": 1}" ||
+		stub.Delay != 250*time.Millisecond {
+		t.Errorf("Unexpected response %s after %s", stub.Response.ToString(), stub.Delay)
+	}
+
+	described := FromStub(stub)
+	if described.Name != definition.Name || described.Request.Query["details"] != "true" ||
+		described.Response.Status != http.StatusOK || described.Response.Delay != "250ms" {
+		t.Errorf("Unexpected definition %+v", described)
+	}
+}
+
+func TestScenarioStub(t *testing.T) {
+	definition := Definition{
+		Name:          "export-done",
+		Request:       RequestDefinition{Method: http.MethodGet, Path: "/exports/1"},
+		Scenario:      "export",
+		RequiredState: "pending",
+		NewState:      "downloaded",
+	}
+
+	stub, err := definition.Stub()
+	if err != nil {
+		t.Fatalf("No error expected, but got %s", err)
+	}
+	if stub.Scenario != "export" || stub.RequiredState != "pending" || stub.NewState != "downloaded" {
+		t.Errorf("Unexpected scenario of stub %+v", stub)
+	}
+
+	described := FromStub(stub)
+	if described.Scenario != "export" || described.RequiredState != "pending" || described.NewState != "downloaded" {
+		t.Errorf("Unexpected definition %+v", described)
+	}
+}
+
+func TestInvalidDelay(t *testing.T) {
+	_, err := Stubs([]Definition{{Name: "slow", Response: ResponseDefinition{Delay: "soon"}}})
+
+	if err == nil || err.Error() != "invalid delay of stub [slow] - time: invalid duration \"soon\"" {
+		t.Errorf("Invalid delay error expected, but got %s", err)
+	}
+}
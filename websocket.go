@@ -0,0 +1,42 @@
+package http
+
+import (
+	"github.com/go-clarum/clarum-http/registry"
+	wsclient "github.com/go-clarum/clarum-http/websocket/client"
+	wsserver "github.com/go-clarum/clarum-http/websocket/server"
+)
+
+type WebSocketEndpointBuilder struct {
+}
+
+func WebSocket() *WebSocketEndpointBuilder {
+	return &WebSocketEndpointBuilder{}
+}
+
+func (web *WebSocketEndpointBuilder) Client() *wsclient.EndpointBuilder {
+	return wsclient.NewEndpointBuilder().Registry(endpoints)
+}
+
+func (web *WebSocketEndpointBuilder) Server() *wsserver.EndpointBuilder {
+	return wsserver.NewEndpointBuilder().Registry(endpoints)
+}
+
+// ClientNamed returns the WebSocket client endpoint built through WebSocket() with the given name.
+// It panics if there is no such client.
+func (web *WebSocketEndpointBuilder) ClientNamed(name string) *wsclient.Endpoint {
+	endpoint, err := endpoints.Lookup(registry.WebSocketClient, name)
+	if err != nil {
+		panic(err)
+	}
+	return endpoint.(*wsclient.Endpoint)
+}
+
+// ServerNamed returns the WebSocket server endpoint built through WebSocket() with the given name.
+// It panics if there is no such server.
+func (web *WebSocketEndpointBuilder) ServerNamed(name string) *wsserver.Endpoint {
+	endpoint, err := endpoints.Lookup(registry.WebSocketServer, name)
+	if err != nil {
+		panic(err)
+	}
+	return endpoint.(*wsserver.Endpoint)
+}
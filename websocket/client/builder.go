@@ -0,0 +1,66 @@
+package client
+
+import (
+	"github.com/go-clarum/clarum-core/durations"
+	"github.com/go-clarum/clarum-http/internal/lifecycle"
+	"github.com/go-clarum/clarum-http/registry"
+	"time"
+)
+
+type EndpointBuilder struct {
+	baseUrl      string
+	name         string
+	timeout      time.Duration
+	subprotocols []string
+	registry     *registry.Registry
+}
+
+func NewEndpointBuilder() *EndpointBuilder {
+	return &EndpointBuilder{}
+}
+
+func (builder *EndpointBuilder) Name(name string) *EndpointBuilder {
+	builder.name = name
+	return builder
+}
+
+// BaseUrl of the endpoint, with the ws:// or wss:// scheme
+func (builder *EndpointBuilder) BaseUrl(baseUrl string) *EndpointBuilder {
+	builder.baseUrl = baseUrl
+	return builder
+}
+
+// Timeout of the opening handshake
+func (builder *EndpointBuilder) Timeout(timeout time.Duration) *EndpointBuilder {
+	builder.timeout = timeout
+	return builder
+}
+
+// Subprotocols requested by the client during the opening handshake, in order of preference.
+func (builder *EndpointBuilder) Subprotocols(subprotocols ...string) *EndpointBuilder {
+	builder.subprotocols = append(builder.subprotocols, subprotocols...)
+	return builder
+}
+
+// Registry registers the endpoint when it is built. Its name must not be used by other endpoints.
+// Endpoints built through clarumhttp.WebSocket() are registered automatically.
+// A registered endpoint is unregistered when it is closed.
+func (builder *EndpointBuilder) Registry(registry *registry.Registry) *EndpointBuilder {
+	builder.registry = registry
+	return builder
+}
+
+// Build panics if the endpoint cannot be registered, so that conflicts are found when global variables are initialized
+func (builder *EndpointBuilder) Build() *Endpoint {
+	endpoint := newEndpoint(builder.name, builder.baseUrl, builder.subprotocols,
+		durations.GetDurationWithDefault(builder.timeout, 10*time.Second))
+	if builder.registry != nil {
+		if err := builder.registry.Register(registry.WebSocketClient, builder.name, 0, endpoint, endpoint.Close); err != nil {
+			panic(err)
+		}
+		endpoint.registry = builder.registry
+	}
+
+	lifecycle.OnFinish(endpoint.finish)
+	return endpoint
+}
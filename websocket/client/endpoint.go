@@ -0,0 +1,250 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-core/config"
+	"github.com/go-clarum/clarum-core/control"
+	"github.com/go-clarum/clarum-core/logging"
+	clarumstrings "github.com/go-clarum/clarum-core/validators/strings"
+	"github.com/go-clarum/clarum-http/internal"
+	"github.com/go-clarum/clarum-http/internal/exchanges"
+	"github.com/go-clarum/clarum-http/internal/utils"
+	"github.com/go-clarum/clarum-http/internal/validators"
+	"github.com/go-clarum/clarum-http/internal/ws"
+	"github.com/go-clarum/clarum-http/message"
+	"github.com/go-clarum/clarum-http/registry"
+	"github.com/gorilla/websocket"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoint is a WebSocket client endpoint. A connection is opened by sending an upgrade request
+// and is available for frames after the upgrade response was received & validated.
+type Endpoint struct {
+	name             string
+	baseUrl          string
+	subprotocols     []string
+	dialer           *websocket.Dialer
+	handshakeChannel chan *handshakeResult
+	connection       *ws.Connection
+	connectionLock   sync.Mutex
+	registry         *registry.Registry
+	tracker          *exchanges.Tracker
+	logger           *logging.Logger
+}
+
+type handshakeResult struct {
+	response   *http.Response
+	connection *websocket.Conn
+	tracked    *exchanges.Exchange
+	error      error
+}
+
+func newEndpoint(name string, baseUrl string, subprotocols []string, timeout time.Duration) *Endpoint {
+	return &Endpoint{
+		name:         name,
+		baseUrl:      baseUrl,
+		subprotocols: subprotocols,
+		dialer: &websocket.Dialer{
+			Proxy:            http.ProxyFromEnvironment,
+			HandshakeTimeout: timeout,
+		},
+		handshakeChannel: make(chan *handshakeResult),
+		tracker:          exchanges.NewTracker(),
+		logger:           logging.NewLogger(config.LoggingLevel(), clientLogPrefix(name)),
+	}
+}
+
+// Close closes the open connection of the endpoint and frees its name, so that an endpoint built
+// inside a test can be built again by the next run of the test
+func (endpoint *Endpoint) Close() error {
+	if endpoint.registry != nil {
+		endpoint.registry.Unregister(endpoint.name)
+	}
+
+	endpoint.connectionLock.Lock()
+	defer endpoint.connectionLock.Unlock()
+
+	if endpoint.connection != nil {
+		endpoint.connection.Close()
+	}
+	return nil
+}
+
+// finish is called when the test run finishes: the upgrade responses that were not received by the test are reported
+// and the endpoint is closed
+func (endpoint *Endpoint) finish() error {
+	defer endpoint.Close()
+
+	if unhandled := endpoint.tracker.Unhandled(); len(unhandled) > 0 {
+		return endpoint.handleError("unhandled exchanges:\n"+strings.Join(unhandled, "\n"), nil)
+	}
+	return nil
+}
+
+// sendUpgrade starts the opening handshake in the background; the result is picked up by receiveUpgrade()
+func (endpoint *Endpoint) sendUpgrade(message *message.RequestMessage, options sendOptions) error {
+	if message == nil {
+		return endpoint.handleError("upgrade request to send is nil", nil)
+	}
+
+	target := message.Url
+	if clarumstrings.IsBlank(target) {
+		target = endpoint.baseUrl
+	}
+	if !utils.IsValidUrl(target) {
+		return endpoint.handleError("upgrade request to send is invalid - invalid url", nil)
+	}
+	target = utils.BuildPath(target, message.Path)
+	if len(message.QueryParams) > 0 {
+		target = target + "?" + url.Values(message.QueryParams).Encode()
+	}
+
+	headers := http.Header{}
+	for header, value := range message.Headers {
+		headers.Set(header, value)
+	}
+
+	dialer := *endpoint.dialer
+	dialer.Subprotocols = endpoint.subprotocols
+
+	ctx := internal.ActionContext(options.ctx)
+	tracked := endpoint.tracker.Start(fmt.Sprintf("upgrade response to [%s]", target), "a client receive action", "")
+
+	control.RunningActions.Add(1)
+	go func() {
+		defer control.RunningActions.Done()
+
+		dialCtx, cancelTimeout := ctx, context.CancelFunc(func() {})
+		if options.timeout > 0 {
+			dialCtx, cancelTimeout = context.WithTimeout(ctx, options.timeout)
+		}
+		defer cancelTimeout()
+
+		endpoint.logger.Infof("sending upgrade request [url: %s, headers: %s, subprotocols: %s]",
+			target, headers, endpoint.subprotocols)
+		conn, res, err := dialer.DialContext(dialCtx, target, headers)
+		if err != nil && !errors.Is(err, websocket.ErrBadHandshake) {
+			endpoint.logger.Errorf("error on upgrade - %s", err)
+		} else {
+			endpoint.logger.Infof("received upgrade response [status: %s, headers: %s]", res.Status, res.Header)
+		}
+
+		select {
+		case endpoint.handshakeChannel <- &handshakeResult{response: res, connection: conn, tracked: tracked, error: err}:
+		case <-time.After(config.ActionTimeout()):
+			endpoint.handleError("action timed out - no client receive action called in test", nil)
+			endpoint.tracker.TimedOut(tracked)
+			if conn != nil {
+				conn.Close()
+			}
+		case <-ctx.Done():
+			endpoint.handleError("action canceled - no client receive action called before the context ended", ctx.Err())
+			endpoint.tracker.TimedOut(tracked)
+			if conn != nil {
+				conn.Close()
+			}
+		}
+	}()
+
+	return nil
+}
+
+// receiveUpgrade validates the upgrade response. A rejected handshake can be validated as well,
+// in which case no connection is opened.
+func (endpoint *Endpoint) receiveUpgrade(message *message.ResponseMessage, options receiveOptions) error {
+	ctx := internal.ActionContext(options.ctx)
+	select {
+	case result := <-endpoint.handshakeChannel:
+		endpoint.tracker.Done(result.tracked)
+		if result.response == nil {
+			return endpoint.handleError("error while receiving upgrade response", result.error)
+		}
+
+		if result.connection != nil {
+			endpoint.setConnection(ws.NewConnection(result.connection, endpoint.logger))
+		}
+
+		return errors.Join(
+			validators.ValidateHttpStatusCode(message, result.response.StatusCode, endpoint.logger),
+			validators.ValidateHttpHeaders(&message.Message, result.response.Header, endpoint.logger))
+	case <-time.After(internal.ActionTimeout(options.timeout)):
+		return endpoint.handleError("receive action timed out - no upgrade response received for validation", nil)
+	case <-ctx.Done():
+		return endpoint.handleError("receive action canceled", ctx.Err())
+	}
+}
+
+func (endpoint *Endpoint) sendFrame(frame *message.FrameMessage) error {
+	if frame == nil {
+		return endpoint.handleError("frame to send is nil", nil)
+	}
+
+	connection, err := endpoint.getConnection()
+	if err != nil {
+		return err
+	}
+
+	endpoint.logger.Infof("sending frame %s", frame.ToString())
+	if err := connection.Write(frame); err != nil {
+		return endpoint.handleError("error while sending frame", err)
+	}
+
+	return nil
+}
+
+func (endpoint *Endpoint) receiveFrame(expected *message.FrameMessage, options receiveOptions) error {
+	connection, err := endpoint.getConnection()
+	if err != nil {
+		return err
+	}
+
+	frame, err := connection.Next(options.ctx, options.timeout)
+	if err != nil {
+		return endpoint.handleError("error while receiving frame", err)
+	}
+
+	endpoint.logger.Infof("received frame %s", frame.ToString())
+	return validators.ValidateFrame(expected, frame, options.expectedPayloadType, endpoint.logger)
+}
+
+func (endpoint *Endpoint) setConnection(connection *ws.Connection) {
+	endpoint.connectionLock.Lock()
+	defer endpoint.connectionLock.Unlock()
+
+	// there is only one open connection per endpoint
+	if endpoint.connection != nil {
+		endpoint.connection.Close()
+	}
+	endpoint.connection = connection
+}
+
+func (endpoint *Endpoint) getConnection() (*ws.Connection, error) {
+	endpoint.connectionLock.Lock()
+	defer endpoint.connectionLock.Unlock()
+
+	if endpoint.connection == nil {
+		return nil, endpoint.handleError("no open connection - an upgrade response must be received first", nil)
+	}
+	return endpoint.connection, nil
+}
+
+func (endpoint *Endpoint) handleError(message string, err error) error {
+	var errorMessage string
+	if err != nil {
+		errorMessage = message + " - " + err.Error()
+	} else {
+		errorMessage = message
+	}
+	endpoint.logger.Error(errorMessage)
+	return errors.New(endpoint.logger.Prefix() + errorMessage)
+}
+
+func clientLogPrefix(endpointName string) string {
+	return fmt.Sprintf("%s: ", endpointName)
+}
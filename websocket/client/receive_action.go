@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"github.com/go-clarum/clarum-http/internal"
+	"github.com/go-clarum/clarum-http/message"
+	"testing"
+	"time"
+)
+
+type receiveOptions struct {
+	expectedPayloadType internal.PayloadType
+	ctx                 context.Context
+	timeout             time.Duration
+}
+
+// ReceiveActionBuilder used to configure a receive action on a WebSocket client endpoint without the context of a test
+// the method chain will end with the .Upgrade() or .Frame() method which will return an error.
+// The error will be a problem encountered during receiving or a validation error.
+type ReceiveActionBuilder struct {
+	endpoint *Endpoint
+	options  *receiveOptions
+}
+
+// TestReceiveActionBuilder used to configure a receive action on a WebSocket client endpoint with the context of a test
+// the method chain will end with the .Upgrade() or .Frame() method which will not return anything.
+// Any error encountered during receiving or validating will fail the test by calling t.Error().
+type TestReceiveActionBuilder struct {
+	test *testing.T
+	ReceiveActionBuilder
+}
+
+// Json validates the payload of text frames as JSON
+func (testBuilder *TestReceiveActionBuilder) Json() *TestReceiveActionBuilder {
+	testBuilder.options.expectedPayloadType = internal.Json
+	return testBuilder
+}
+
+// Json validates the payload of text frames as JSON
+func (builder *ReceiveActionBuilder) Json() *ReceiveActionBuilder {
+	builder.options.expectedPayloadType = internal.Json
+	return builder
+}
+
+// Context cancels the action when the context ends. The context of the test is used by default.
+func (testBuilder *TestReceiveActionBuilder) Context(ctx context.Context) *TestReceiveActionBuilder {
+	testBuilder.options.ctx = ctx
+	return testBuilder
+}
+
+// Context cancels the action when the context ends.
+func (builder *ReceiveActionBuilder) Context(ctx context.Context) *ReceiveActionBuilder {
+	builder.options.ctx = ctx
+	return builder
+}
+
+// Timeout overrides the action timeout of the config for this action: how long it waits for an upgrade response
+// or a frame.
+func (testBuilder *TestReceiveActionBuilder) Timeout(timeout time.Duration) *TestReceiveActionBuilder {
+	testBuilder.options.timeout = timeout
+	return testBuilder
+}
+
+// Timeout overrides the action timeout of the config for this action: how long it waits for an upgrade response
+// or a frame.
+func (builder *ReceiveActionBuilder) Timeout(timeout time.Duration) *ReceiveActionBuilder {
+	builder.options.timeout = timeout
+	return builder
+}
+
+// Upgrade validates the response of the opening handshake. Set the 'Sec-WebSocket-Protocol' header
+// on the expected message to validate the subprotocol selected by the server.
+func (testBuilder *TestReceiveActionBuilder) Upgrade(message *message.ResponseMessage) {
+	if err := testBuilder.endpoint.receiveUpgrade(message, *testBuilder.options); err != nil {
+		testBuilder.test.Error(err)
+	}
+}
+
+// Upgrade validates the response of the opening handshake. Set the 'Sec-WebSocket-Protocol' header
+// on the expected message to validate the subprotocol selected by the server.
+func (builder *ReceiveActionBuilder) Upgrade(message *message.ResponseMessage) error {
+	return builder.endpoint.receiveUpgrade(message, *builder.options)
+}
+
+// Frame blocks until the next frame arrives and validates it. Control frames (ping, pong & close) are received as well.
+func (testBuilder *TestReceiveActionBuilder) Frame(frame *message.FrameMessage) {
+	if err := testBuilder.endpoint.receiveFrame(frame, *testBuilder.options); err != nil {
+		testBuilder.test.Error(err)
+	}
+}
+
+// Frame blocks until the next frame arrives and validates it. Control frames (ping, pong & close) are received as well.
+func (builder *ReceiveActionBuilder) Frame(frame *message.FrameMessage) error {
+	return builder.endpoint.receiveFrame(frame, *builder.options)
+}
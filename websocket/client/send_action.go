@@ -0,0 +1,77 @@
+package client
+
+import (
+	"context"
+	"github.com/go-clarum/clarum-http/message"
+	"testing"
+	"time"
+)
+
+// SendActionBuilder used to configure a send action on a WebSocket client endpoint without the context of a test
+// the method chain will end with the .Upgrade() or .Frame() method which will return an error.
+// The error will be a problem encountered during sending.
+type SendActionBuilder struct {
+	endpoint *Endpoint
+	options  sendOptions
+}
+
+// sendOptions apply to a single send action
+type sendOptions struct {
+	ctx     context.Context
+	timeout time.Duration
+}
+
+// TestSendActionBuilder used to configure a send action on a WebSocket client endpoint with the context of a test
+// the method chain will end with the .Upgrade() or .Frame() method which will not return anything.
+// Any error encountered during sending will fail the test by calling t.Error().
+type TestSendActionBuilder struct {
+	test *testing.T
+	SendActionBuilder
+}
+
+// Context is used for the opening handshake, which is canceled when the context ends.
+// The context of the test is used by default.
+func (testBuilder *TestSendActionBuilder) Context(ctx context.Context) *TestSendActionBuilder {
+	testBuilder.options.ctx = ctx
+	return testBuilder
+}
+
+// Context is used for the opening handshake, which is canceled when the context ends.
+func (builder *SendActionBuilder) Context(ctx context.Context) *SendActionBuilder {
+	builder.options.ctx = ctx
+	return builder
+}
+
+// Timeout cancels the opening handshake if it did not complete in time.
+func (testBuilder *TestSendActionBuilder) Timeout(timeout time.Duration) *TestSendActionBuilder {
+	testBuilder.options.timeout = timeout
+	return testBuilder
+}
+
+// Timeout cancels the opening handshake if it did not complete in time.
+func (builder *SendActionBuilder) Timeout(timeout time.Duration) *SendActionBuilder {
+	builder.options.timeout = timeout
+	return builder
+}
+
+// Upgrade sends the upgrade request that opens the connection. The response is validated with Receive().Upgrade().
+func (testBuilder *TestSendActionBuilder) Upgrade(message *message.RequestMessage) {
+	if err := testBuilder.endpoint.sendUpgrade(message, testBuilder.options); err != nil {
+		testBuilder.test.Error(err)
+	}
+}
+
+// Upgrade sends the upgrade request that opens the connection. The response is validated with Receive().Upgrade().
+func (builder *SendActionBuilder) Upgrade(message *message.RequestMessage) error {
+	return builder.endpoint.sendUpgrade(message, builder.options)
+}
+
+func (testBuilder *TestSendActionBuilder) Frame(frame *message.FrameMessage) {
+	if err := testBuilder.endpoint.sendFrame(frame); err != nil {
+		testBuilder.test.Error(err)
+	}
+}
+
+func (builder *SendActionBuilder) Frame(frame *message.FrameMessage) error {
+	return builder.endpoint.sendFrame(frame)
+}
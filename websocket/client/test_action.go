@@ -0,0 +1,61 @@
+package client
+
+import (
+	"github.com/go-clarum/clarum-http/internal"
+	"testing"
+)
+
+// TestActionBuilder used to initiate a send or receive action on a WebSocket client endpoint
+// with the context of a test
+type TestActionBuilder struct {
+	test     *testing.T
+	endpoint *Endpoint
+}
+
+// In runs the actions in the context of the test. The test fails when it ends, if the response to an upgrade request
+// it sent was never received by a receive action.
+func (endpoint *Endpoint) In(t *testing.T) *TestActionBuilder {
+	endpoint.tracker.Watch(t, endpoint.logger.Prefix(), nil)
+	return &TestActionBuilder{
+		test:     t,
+		endpoint: endpoint,
+	}
+}
+
+func (endpoint *Endpoint) Send() *SendActionBuilder {
+	return &SendActionBuilder{
+		endpoint: endpoint,
+	}
+}
+
+func (endpoint *Endpoint) Receive() *ReceiveActionBuilder {
+	return &ReceiveActionBuilder{
+		endpoint: endpoint,
+		options: &receiveOptions{
+			expectedPayloadType: internal.Plaintext,
+		},
+	}
+}
+
+func (testBuilder *TestActionBuilder) Send() *TestSendActionBuilder {
+	return &TestSendActionBuilder{
+		test: testBuilder.test,
+		SendActionBuilder: SendActionBuilder{
+			endpoint: testBuilder.endpoint,
+			options:  sendOptions{ctx: testBuilder.test.Context()},
+		},
+	}
+}
+
+func (testBuilder *TestActionBuilder) Receive() *TestReceiveActionBuilder {
+	return &TestReceiveActionBuilder{
+		test: testBuilder.test,
+		ReceiveActionBuilder: ReceiveActionBuilder{
+			endpoint: testBuilder.endpoint,
+			options: &receiveOptions{
+				expectedPayloadType: internal.Plaintext,
+				ctx:                 testBuilder.test.Context(),
+			},
+		},
+	}
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"github.com/go-clarum/clarum-http/internal/lifecycle"
+	"github.com/go-clarum/clarum-http/registry"
+)
+
+type EndpointBuilder struct {
+	port         uint
+	name         string
+	subprotocols []string
+	registry     *registry.Registry
+}
+
+func NewEndpointBuilder() *EndpointBuilder {
+	return &EndpointBuilder{}
+}
+
+func (builder *EndpointBuilder) Name(name string) *EndpointBuilder {
+	builder.name = name
+	return builder
+}
+
+func (builder *EndpointBuilder) Port(port uint) *EndpointBuilder {
+	builder.port = port
+	return builder
+}
+
+// Subprotocols supported by the server, in order of preference. The first one also requested
+// by the client is selected during the opening handshake.
+func (builder *EndpointBuilder) Subprotocols(subprotocols ...string) *EndpointBuilder {
+	builder.subprotocols = append(builder.subprotocols, subprotocols...)
+	return builder
+}
+
+// Registry registers the endpoint when it is built, before it starts listening. Its name & port must not be used
+// by other endpoints. Endpoints built through clarumhttp.WebSocket() are registered automatically.
+// A registered endpoint is unregistered when it is closed.
+func (builder *EndpointBuilder) Registry(registry *registry.Registry) *EndpointBuilder {
+	builder.registry = registry
+	return builder
+}
+
+// Build starts the server of the endpoint. It is closed when the test run finishes, which fails if upgrade requests
+// were not handled by the test.
+// Build panics if the endpoint cannot be registered, so that conflicts are found when global variables are initialized
+func (builder *EndpointBuilder) Build() *Endpoint {
+	endpoint := newEndpoint(builder.name, builder.port, builder.subprotocols)
+	if builder.registry != nil {
+		if err := builder.registry.Register(registry.WebSocketServer, builder.name, builder.port, endpoint, endpoint.Close); err != nil {
+			panic(err)
+		}
+		endpoint.registry = builder.registry
+	}
+
+	lifecycle.OnFinish(endpoint.finish)
+	endpoint.start()
+
+	return endpoint
+}
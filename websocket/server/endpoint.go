@@ -0,0 +1,330 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/go-clarum/clarum-core/config"
+	"github.com/go-clarum/clarum-core/control"
+	"github.com/go-clarum/clarum-core/logging"
+	"github.com/go-clarum/clarum-http/internal"
+	"github.com/go-clarum/clarum-http/internal/exchanges"
+	"github.com/go-clarum/clarum-http/internal/validators"
+	"github.com/go-clarum/clarum-http/internal/ws"
+	"github.com/go-clarum/clarum-http/message"
+	"github.com/go-clarum/clarum-http/registry"
+	"github.com/gorilla/websocket"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const closeTimeout = time.Second
+
+// Endpoint is a WebSocket server endpoint. An incoming upgrade request is validated with Receive().Upgrade()
+// and answered with Send().Upgrade(). The connection is opened only if the response has the status 101.
+type Endpoint struct {
+	name                     string
+	port                     uint
+	server                   *http.Server
+	upgrader                 *websocket.Upgrader
+	requestValidationChannel chan *upgradeExchange
+	sendChannel              chan *upgradePair
+	connection               *ws.Connection
+	connectionLock           sync.Mutex
+	closed                   chan struct{}
+	closeOnce                sync.Once
+	closeErr                 error
+	registry                 *registry.Registry
+	tracker                  *exchanges.Tracker
+	logger                   *logging.Logger
+}
+
+type upgradeExchange struct {
+	request *http.Request
+	// tracked is updated by the actions handling the exchange
+	tracked *exchanges.Exchange
+}
+
+type upgradePair struct {
+	response *message.ResponseMessage
+	result   chan error
+}
+
+func newEndpoint(name string, port uint, subprotocols []string) *Endpoint {
+	return &Endpoint{
+		name: name,
+		port: port,
+		upgrader: &websocket.Upgrader{
+			Subprotocols: subprotocols,
+			// the origin is part of the upgrade request & can be validated in the test
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		requestValidationChannel: make(chan *upgradeExchange),
+		sendChannel:              make(chan *upgradePair),
+		closed:                   make(chan struct{}),
+		tracker:                  exchanges.NewTracker(),
+		logger:                   logging.NewLogger(config.LoggingLevel(), serverLogPrefix(name)),
+	}
+}
+
+func (endpoint *Endpoint) start() {
+	mux := http.NewServeMux()
+	mux.Handle("/", endpoint)
+
+	endpoint.server = &http.Server{
+		Addr:    fmt.Sprintf(":%d", endpoint.port),
+		Handler: mux,
+	}
+
+	listener, err := net.Listen("tcp", endpoint.server.Addr)
+	if err != nil {
+		endpoint.logger.Errorf("error - %s", err)
+		return
+	}
+
+	go func() {
+		if err := endpoint.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			endpoint.logger.Errorf("error - %s", err)
+		} else {
+			endpoint.logger.Info("closed server")
+		}
+	}()
+}
+
+// Close stops the server of the endpoint, closes the open connection and frees its name & port. Upgrade requests that
+// are being handled are released. Only the first call closes the endpoint, later calls return its result.
+func (endpoint *Endpoint) Close() error {
+	endpoint.closeOnce.Do(func() {
+		close(endpoint.closed)
+		if endpoint.registry != nil {
+			endpoint.registry.Unregister(endpoint.name)
+		}
+
+		endpoint.connectionLock.Lock()
+		if endpoint.connection != nil {
+			endpoint.connection.Close()
+		}
+		endpoint.connectionLock.Unlock()
+
+		if endpoint.server == nil {
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+		defer cancel()
+
+		endpoint.closeErr = endpoint.server.Shutdown(ctx)
+	})
+	return endpoint.closeErr
+}
+
+// finish is called when the test run finishes: the upgrade requests that were not handled by the test are reported
+// and the endpoint is closed
+func (endpoint *Endpoint) finish() error {
+	var unhandledErr error
+	if unhandled := endpoint.tracker.Unhandled(); len(unhandled) > 0 {
+		unhandledErr = endpoint.handleError("unhandled exchanges:\n"+strings.Join(unhandled, "\n"), nil)
+	}
+
+	var closeErr error
+	if err := endpoint.Close(); err != nil {
+		closeErr = endpoint.handleError("could not close server", err)
+	}
+
+	return errors.Join(unhandledErr, closeErr)
+}
+
+// ServeHTTP is called when the server receives an upgrade request. Just like with the HTTP server endpoint,
+// the handler is blocked until the request was validated and the send action provided the upgrade response.
+func (endpoint *Endpoint) ServeHTTP(resWriter http.ResponseWriter, request *http.Request) {
+	control.RunningActions.Add(1)
+	defer finishOrRecover(endpoint.logger)
+
+	endpoint.logger.Infof("received upgrade request [url: %s, headers: %s]", request.URL.String(), request.Header)
+
+	tracked := endpoint.tracker.Start(fmt.Sprintf("upgrade request [%s]", request.URL), "a server receive action", "")
+	select {
+	case endpoint.requestValidationChannel <- &upgradeExchange{request: request, tracked: tracked}:
+		endpoint.logger.Debug("received request was sent to validation channel")
+	case <-endpoint.closed:
+		endpoint.logger.Warn("request handling canceled - endpoint was closed")
+		endpoint.tracker.TimedOut(tracked)
+		return
+	case <-time.After(config.ActionTimeout()):
+		endpoint.logger.Warn("request handling timed out - no server receive action called in test")
+		endpoint.tracker.TimedOut(tracked)
+		return
+	}
+
+	select {
+	case upgradePair := <-endpoint.sendChannel:
+		endpoint.tracker.Done(tracked)
+		upgradePair.result <- endpoint.upgrade(resWriter, request, upgradePair.response)
+	case <-endpoint.closed:
+		endpoint.logger.Warn("response handling canceled - endpoint was closed")
+		endpoint.tracker.TimedOut(tracked)
+	case <-time.After(config.ActionTimeout()):
+		endpoint.logger.Warn("response handling timed out - no server send action called in test")
+		endpoint.tracker.TimedOut(tracked)
+	}
+}
+
+// upgrade opens the connection or rejects the handshake, depending on the status of the response
+func (endpoint *Endpoint) upgrade(resWriter http.ResponseWriter, request *http.Request, response *message.ResponseMessage) error {
+	headers := http.Header{}
+	for header, value := range response.Headers {
+		headers.Set(header, value)
+	}
+
+	if response.StatusCode != http.StatusSwitchingProtocols {
+		for header, values := range headers {
+			resWriter.Header()[header] = values
+		}
+		resWriter.WriteHeader(response.StatusCode)
+		if _, err := io.WriteString(resWriter, response.MessagePayload); err != nil {
+			endpoint.logger.Errorf("could not write response body - %s", err)
+		}
+
+		endpoint.logger.Infof("rejected upgrade [status: %d, headers: %s]", response.StatusCode, headers)
+		return nil
+	}
+
+	conn, err := endpoint.upgrader.Upgrade(resWriter, request, headers)
+	if err != nil {
+		return err
+	}
+
+	endpoint.logger.Infof("connection upgraded [subprotocol: %s]", conn.Subprotocol())
+	endpoint.setConnection(ws.NewConnection(conn, endpoint.logger))
+	return nil
+}
+
+// this Method is blocking, until an upgrade request is received
+func (endpoint *Endpoint) receiveUpgrade(message *message.RequestMessage, options receiveOptions) error {
+	ctx := internal.ActionContext(options.ctx)
+	select {
+	case received := <-endpoint.requestValidationChannel:
+		endpoint.tracker.Await(received.tracked, "a server send action")
+		receivedRequest := received.request
+		return errors.Join(
+			validators.ValidatePath(message, receivedRequest.URL, endpoint.logger),
+			validators.ValidateHttpMethod(message, receivedRequest.Method, endpoint.logger),
+			validators.ValidateHttpHeaders(&message.Message, receivedRequest.Header, endpoint.logger),
+			validators.ValidateHttpQueryParams(message, receivedRequest.URL, endpoint.logger))
+	case <-time.After(internal.ActionTimeout(options.timeout)):
+		return endpoint.handleError("receive action timed out - no upgrade request received for validation", nil)
+	case <-ctx.Done():
+		return endpoint.handleError("receive action canceled", ctx.Err())
+	}
+}
+
+// sendUpgrade blocks until the connection was opened, so that frames can be sent & received right after
+func (endpoint *Endpoint) sendUpgrade(message *message.ResponseMessage, options sendOptions) error {
+	if message == nil {
+		return endpoint.handleError("upgrade response to send is nil", nil)
+	}
+	if message.StatusCode < 100 || message.StatusCode > 999 {
+		return endpoint.handleError(fmt.Sprintf("upgrade response to send is invalid - unsupported status code [%d]",
+			message.StatusCode), nil)
+	}
+
+	toSend := &upgradePair{
+		response: message.Clone(),
+		result:   make(chan error, 1),
+	}
+
+	ctx := internal.ActionContext(options.ctx)
+	select {
+	case endpoint.sendChannel <- toSend:
+	case <-time.After(internal.ActionTimeout(options.timeout)):
+		return endpoint.handleError("send action timed out - no upgrade request received", nil)
+	case <-ctx.Done():
+		return endpoint.handleError("send action canceled", ctx.Err())
+	}
+
+	if err := <-toSend.result; err != nil {
+		return endpoint.handleError("upgrade failed", err)
+	}
+	return nil
+}
+
+func (endpoint *Endpoint) sendFrame(frame *message.FrameMessage) error {
+	if frame == nil {
+		return endpoint.handleError("frame to send is nil", nil)
+	}
+
+	connection, err := endpoint.getConnection()
+	if err != nil {
+		return err
+	}
+
+	endpoint.logger.Infof("sending frame %s", frame.ToString())
+	if err := connection.Write(frame); err != nil {
+		return endpoint.handleError("error while sending frame", err)
+	}
+
+	return nil
+}
+
+func (endpoint *Endpoint) receiveFrame(expected *message.FrameMessage, options receiveOptions) error {
+	connection, err := endpoint.getConnection()
+	if err != nil {
+		return err
+	}
+
+	frame, err := connection.Next(options.ctx, options.timeout)
+	if err != nil {
+		return endpoint.handleError("error while receiving frame", err)
+	}
+
+	endpoint.logger.Infof("received frame %s", frame.ToString())
+	return validators.ValidateFrame(expected, frame, options.expectedPayloadType, endpoint.logger)
+}
+
+func (endpoint *Endpoint) setConnection(connection *ws.Connection) {
+	endpoint.connectionLock.Lock()
+	defer endpoint.connectionLock.Unlock()
+
+	// there is only one open connection per endpoint
+	if endpoint.connection != nil {
+		endpoint.connection.Close()
+	}
+	endpoint.connection = connection
+}
+
+func (endpoint *Endpoint) getConnection() (*ws.Connection, error) {
+	endpoint.connectionLock.Lock()
+	defer endpoint.connectionLock.Unlock()
+
+	if endpoint.connection == nil {
+		return nil, endpoint.handleError("no open connection - an upgrade response must be sent first", nil)
+	}
+	return endpoint.connection, nil
+}
+
+func (endpoint *Endpoint) handleError(message string, err error) error {
+	var errorMessage string
+	if err != nil {
+		errorMessage = message + " - " + err.Error()
+	} else {
+		errorMessage = message
+	}
+	endpoint.logger.Error(errorMessage)
+	return errors.New(endpoint.logger.Prefix() + errorMessage)
+}
+
+func finishOrRecover(logger *logging.Logger) {
+	control.RunningActions.Done()
+
+	if r := recover(); r != nil {
+		logger.Errorf("endpoint panicked: error - %s", r)
+	}
+}
+
+func serverLogPrefix(endpointName string) string {
+	return fmt.Sprintf("%s: ", endpointName)
+}
@@ -0,0 +1,45 @@
+package server
+
+import (
+	"github.com/go-clarum/clarum-http/internal/lifecycle"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestFinishClosesEndpoint(t *testing.T) {
+	NewEndpointBuilder().
+		Name("finishedWsServer").
+		Port(8099).
+		Build()
+
+	requestDone := make(chan struct{})
+	go func() {
+		defer close(requestDone)
+		if response, err := http.Get("http://localhost:8099/chat"); err == nil {
+			response.Body.Close()
+		}
+	}()
+	// the upgrade request is waiting for a receive action when the test run finishes
+	time.Sleep(100 * time.Millisecond)
+
+	// the upgrade request was never handled by the test
+	err := lifecycle.Finish()
+	if err == nil || !strings.Contains(err.Error(), "upgrade request [/chat] is waiting for a server receive action") {
+		t.Errorf("Expected unhandled upgrade request error, but got [%v]", err)
+	}
+
+	select {
+	case <-requestDone:
+	case <-time.After(time.Second):
+		t.Errorf("upgrade request was not released when the endpoint was closed")
+	}
+
+	listener, err := net.Listen("tcp", ":8099")
+	if err != nil {
+		t.Fatalf("port was not freed - %s", err)
+	}
+	listener.Close()
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"github.com/go-clarum/clarum-http/internal"
+	"testing"
+)
+
+// TestActionBuilder used to initiate a send or receive action on a WebSocket server endpoint
+// with the context of a test
+type TestActionBuilder struct {
+	test     *testing.T
+	endpoint *Endpoint
+}
+
+// In runs the actions in the context of the test. The test fails when it ends, if an upgrade request that arrived
+// during the test was never received by a receive action, or never answered by a send action.
+func (endpoint *Endpoint) In(t *testing.T) *TestActionBuilder {
+	endpoint.tracker.Watch(t, endpoint.logger.Prefix(), nil)
+	return &TestActionBuilder{
+		test:     t,
+		endpoint: endpoint,
+	}
+}
+
+func (endpoint *Endpoint) Send() *SendActionBuilder {
+	return &SendActionBuilder{
+		endpoint: endpoint,
+	}
+}
+
+func (endpoint *Endpoint) Receive() *ReceiveActionBuilder {
+	return &ReceiveActionBuilder{
+		endpoint: endpoint,
+		options: &receiveOptions{
+			expectedPayloadType: internal.Plaintext,
+		},
+	}
+}
+
+func (testBuilder *TestActionBuilder) Send() *TestSendActionBuilder {
+	return &TestSendActionBuilder{
+		test: testBuilder.test,
+		SendActionBuilder: SendActionBuilder{
+			endpoint: testBuilder.endpoint,
+			options:  sendOptions{ctx: testBuilder.test.Context()},
+		},
+	}
+}
+
+func (testBuilder *TestActionBuilder) Receive() *TestReceiveActionBuilder {
+	return &TestReceiveActionBuilder{
+		test: testBuilder.test,
+		ReceiveActionBuilder: ReceiveActionBuilder{
+			endpoint: testBuilder.endpoint,
+			options: &receiveOptions{
+				expectedPayloadType: internal.Plaintext,
+				ctx:                 testBuilder.test.Context(),
+			},
+		},
+	}
+}